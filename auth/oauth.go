@@ -3,7 +3,6 @@ package auth
 import (
 	"fmt"
 	"golang.org/x/oauth2"
-	"golang.org/x/oauth2/google"
 	"net/http"
 	"time"
 )
@@ -66,20 +65,11 @@ func NewAccessTokenClient(clientId, clientSecret, accessToken string) *http.Clie
 }
 
 func NewServiceAccountClient(serviceAccountFile string) (*http.Client, error) {
-	content, exists, err := ReadFile(serviceAccountFile)
-	if(!exists) {
-		return nil, fmt.Errorf("Service account filename %q not found", serviceAccountFile)
-	}
-
-	if(err != nil) {
-		return nil, err
-	}
-
-	conf, err := google.JWTConfigFromJSON(content, "https://www.googleapis.com/auth/drive")
+	serviceAccount, err := NewServiceAccount(serviceAccountFile)
 	if(err != nil) {
 		return nil, err
 	}
-	return conf.Client(oauth2.NoContext), nil
+	return serviceAccount.Client()
 }
 
 func getConfig(clientId, clientSecret string) *oauth2.Config {
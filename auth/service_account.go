@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"fmt"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"net/http"
+	"sync"
+)
+
+const driveScope = "https://www.googleapis.com/auth/drive"
+
+// ServiceAccount creates clients for a service account, optionally impersonating
+// users with domain-wide delegation. Clients are cached per subject, so repeated
+// operations for the same user reuse the token until it expires and is refreshed
+type ServiceAccount struct {
+	content []byte
+	mutex   sync.Mutex
+	clients map[string]*http.Client
+}
+
+func NewServiceAccount(serviceAccountFile string) (*ServiceAccount, error) {
+	content, exists, err := ReadFile(serviceAccountFile)
+	if !exists {
+		return nil, fmt.Errorf("Service account filename %q not found", serviceAccountFile)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the key before any clients are created
+	if _, err := google.JWTConfigFromJSON(content, driveScope); err != nil {
+		return nil, err
+	}
+
+	return &ServiceAccount{
+		content: content,
+		clients: map[string]*http.Client{},
+	}, nil
+}
+
+// Returns a client acting as the service account itself
+func (self *ServiceAccount) Client() (*http.Client, error) {
+	return self.WithSubject("")
+}
+
+// Returns a client impersonating the user with the given email. The returned
+// client is a copy sharing the cached transport, so it can be modified freely
+func (self *ServiceAccount) WithSubject(email string) (*http.Client, error) {
+	client, err := self.client(email)
+	if err != nil {
+		return nil, err
+	}
+
+	shallow := *client
+	return &shallow, nil
+}
+
+func (self *ServiceAccount) client(email string) (*http.Client, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if client, ok := self.clients[email]; ok {
+		return client, nil
+	}
+
+	conf, err := google.JWTConfigFromJSON(self.content, driveScope)
+	if err != nil {
+		return nil, err
+	}
+	conf.Subject = email
+
+	// The client transport reuses the token until it expires
+	client := conf.Client(oauth2.NoContext)
+	self.clients[email] = client
+	return client, nil
+}
+
+// Fetches tokens for the given subjects up front, so that the first
+// request for each subject does not have to wait for a token
+func (self *ServiceAccount) Prewarm(emails ...string) error {
+	for _, email := range emails {
+		client, err := self.client(email)
+		if err != nil {
+			return err
+		}
+
+		transport, ok := client.Transport.(*oauth2.Transport)
+		if !ok {
+			continue
+		}
+
+		if _, err := transport.Source.Token(); err != nil {
+			return fmt.Errorf("Failed to get token for %s: %s", email, err)
+		}
+	}
+
+	return nil
+}
@@ -94,6 +94,9 @@ type IntFlag struct {
 	Name         string
 	Description  string
 	DefaultValue int64
+	// The value may be omitted, OmittedValue is used if the flag is not followed by an integer
+	OmitValue    bool
+	OmittedValue int64
 }
 
 func (self IntFlag) GetName() string {
@@ -115,6 +118,8 @@ func (self IntFlag) GetParser() Parser {
 			pattern:      p,
 			key:          self.Name,
 			defaultValue: self.DefaultValue,
+			omitValue:    self.OmitValue,
+			omittedValue: self.OmittedValue,
 		})
 	}
 
@@ -135,30 +135,44 @@ type IntFlagParser struct {
 	pattern      string
 	key          string
 	defaultValue int64
+	omitValue    bool
+	omittedValue int64
 }
 
 func (self IntFlagParser) Match(values []string) ([]string, bool) {
 	remaining, value, ok := flagKeyValueMatch(self.pattern, values, 0)
-	if !ok {
-		return remaining, false
-	}
 
 	// Check that value is a valid integer
-	if _, err := strconv.ParseInt(value, 10, 64); err != nil {
-		return remaining, false
+	if ok {
+		if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return remaining, true
+		}
 	}
 
-	return remaining, true
+	// The flag may also be given without a value
+	if self.omitValue {
+		return flagKeyMatch(self.pattern, values, 0)
+	}
+
+	return remaining, false
 }
 
 func (self IntFlagParser) Capture(values []string) ([]string, map[string]interface{}) {
 	remaining, value, ok := flagKeyValueMatch(self.pattern, values, 0)
-	if !ok {
-		return remaining, map[string]interface{}{self.key: self.defaultValue}
+	if ok {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err == nil || !self.omitValue {
+			return remaining, map[string]interface{}{self.key: n}
+		}
+	}
+
+	if self.omitValue {
+		if remaining, ok := flagKeyMatch(self.pattern, values, 0); ok {
+			return remaining, map[string]interface{}{self.key: self.omittedValue}
+		}
 	}
 
-	n, _ := strconv.ParseInt(value, 10, 64)
-	return remaining, map[string]interface{}{self.key: n}
+	return values, map[string]interface{}{self.key: self.defaultValue}
 }
 
 func (self IntFlagParser) String() string {
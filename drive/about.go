@@ -7,27 +7,73 @@ import (
 )
 
 type AboutArgs struct {
-	Out         io.Writer
-	SizeInBytes bool
+	Out          io.Writer
+	SizeInBytes  bool
+	DecimalUnits bool
+	SharedDrives bool
 }
 
 func (self *Drive) About(args AboutArgs) (err error) {
 	about, err := self.service.About.Get().Fields("maxImportSizes", "maxUploadSize", "storageQuota", "user").Do()
 	if err != nil {
-		return fmt.Errorf("Failed to get about: %s", err)
+		return wrapError("Failed to get about", err)
 	}
 
 	user := about.User
 	quota := about.StorageQuota
 
 	fmt.Fprintf(args.Out, "User: %s, %s\n", user.DisplayName, user.EmailAddress)
-	fmt.Fprintf(args.Out, "Used: %s\n", formatSize(quota.Usage, args.SizeInBytes))
-	fmt.Fprintf(args.Out, "Free: %s\n", formatSize(quota.Limit-quota.Usage, args.SizeInBytes))
-	fmt.Fprintf(args.Out, "Total: %s\n", formatSize(quota.Limit, args.SizeInBytes))
-	fmt.Fprintf(args.Out, "Max upload size: %s\n", formatSize(about.MaxUploadSize, args.SizeInBytes))
+	fmt.Fprintf(args.Out, "Used: %s\n", formatSize(quota.Usage, args.SizeInBytes, args.DecimalUnits))
+	fmt.Fprintf(args.Out, "Free: %s\n", formatSize(quota.Limit-quota.Usage, args.SizeInBytes, args.DecimalUnits))
+	fmt.Fprintf(args.Out, "Total: %s\n", formatSize(quota.Limit, args.SizeInBytes, args.DecimalUnits))
+	fmt.Fprintf(args.Out, "Max upload size: %s\n", formatSize(about.MaxUploadSize, args.SizeInBytes, args.DecimalUnits))
+
+	if args.SharedDrives {
+		return self.printSharedDrives(args.Out)
+	}
 	return
 }
 
+func (self *Drive) printSharedDrives(out io.Writer) error {
+	drives, err := self.listSharedDrives()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out)
+
+	if len(drives) == 0 {
+		fmt.Fprintln(out, "No shared drives")
+		return nil
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(out, 0, 0, 3, ' ', 0)
+
+	fmt.Fprintln(w, "Shared drive\tId")
+	for _, d := range drives {
+		fmt.Fprintf(w, "%s\t%s\n", d.Name, d.Id)
+	}
+
+	w.Flush()
+	return nil
+}
+
+type WhoamiArgs struct {
+	Out io.Writer
+}
+
+// Only fetches the user, fails if the token is expired or revoked
+func (self *Drive) Whoami(args WhoamiArgs) error {
+	about, err := self.service.About.Get().Fields("user").Do()
+	if err != nil {
+		return wrapError("Failed to get user", err)
+	}
+
+	fmt.Fprintf(args.Out, "%s, %s\n", about.User.EmailAddress, about.User.DisplayName)
+	return nil
+}
+
 type AboutImportArgs struct {
 	Out io.Writer
 }
@@ -35,7 +81,7 @@ type AboutImportArgs struct {
 func (self *Drive) AboutImport(args AboutImportArgs) (err error) {
 	about, err := self.service.About.Get().Fields("importFormats").Do()
 	if err != nil {
-		return fmt.Errorf("Failed to get about: %s", err)
+		return wrapError("Failed to get about", err)
 	}
 	printAboutFormats(args.Out, about.ImportFormats)
 	return
@@ -48,7 +94,7 @@ type AboutExportArgs struct {
 func (self *Drive) AboutExport(args AboutExportArgs) (err error) {
 	about, err := self.service.About.Get().Fields("exportFormats").Do()
 	if err != nil {
-		return fmt.Errorf("Failed to get about: %s", err)
+		return wrapError("Failed to get about", err)
 	}
 	printAboutFormats(args.Out, about.ExportFormats)
 	return
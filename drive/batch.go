@@ -0,0 +1,352 @@
+package drive
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"google.golang.org/api/drive/v3"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type BatchArgs struct {
+	Out         io.Writer
+	Path        string
+	Concurrency int
+	ChunkSize   int64
+	Timeout     time.Duration
+}
+
+// A single row of the batch file. Csv files use the json names as column headers
+type batchOperation struct {
+	line   int
+	Action string `json:"action"`
+	Id     string `json:"id"`
+	Path   string `json:"path"`
+	Name   string `json:"name"`
+	Parent string `json:"parent"`
+	Role   string `json:"role"`
+	Type   string `json:"type"`
+	Email  string `json:"email"`
+	Domain string `json:"domain"`
+}
+
+type batchResult struct {
+	message string
+	err     error
+}
+
+// Runs the operations of the batch file in order. With a concurrency above 1
+// the rows are started in order by a pool of workers, so the operations run at
+// the same time and must not depend on each other, results are still printed in
+// order. All rows are validated before anything is run
+func (self *Drive) Batch(args BatchArgs) error {
+	ops, err := readBatchFile(args.Path)
+	if err != nil {
+		return err
+	}
+
+	concurrency := args.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]chan batchResult, len(ops))
+	for i := range results {
+		results[i] = make(chan batchResult, 1)
+	}
+
+	run := func(i int) {
+		message, err := self.runBatchOperation(ops[i], args)
+		results[i] <- batchResult{message, err}
+	}
+
+	if concurrency > 1 {
+		indexes := make(chan int)
+		for w := 0; w < concurrency; w++ {
+			go func() {
+				for i := range indexes {
+					run(i)
+				}
+			}()
+		}
+
+		go func() {
+			for i := range ops {
+				indexes <- i
+			}
+			close(indexes)
+		}()
+	}
+
+	failed := 0
+
+	for i, op := range ops {
+		// Each row is finished before the next one is started
+		if concurrency == 1 {
+			run(i)
+		}
+
+		result := <-results[i]
+		if result.err != nil {
+			fmt.Fprintf(args.Out, "Line %d: %s failed: %s\n", op.line, op.Action, result.err)
+			failed++
+		} else {
+			fmt.Fprintf(args.Out, "Line %d: %s\n", op.line, result.message)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d operations failed", failed, len(ops))
+	}
+
+	return nil
+}
+
+func (self *Drive) runBatchOperation(op *batchOperation, args BatchArgs) (string, error) {
+	switch op.Action {
+	case "upload":
+		parents, err := self.resolvePathsToIds(optionalList(op.Parent))
+		if err != nil {
+			return "", err
+		}
+
+		f, _, err := self.uploadFile(UploadArgs{
+			Out:       ioutil.Discard,
+			Progress:  ioutil.Discard,
+			Path:      op.Path,
+			Name:      op.Name,
+			Parents:   parents,
+			ChunkSize: args.ChunkSize,
+			Timeout:   args.Timeout,
+		})
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Uploaded %s as %s", op.Path, f.Id), nil
+
+	case "mkdir":
+		parents, err := self.resolvePathsToIds(optionalList(op.Parent))
+		if err != nil {
+			return "", err
+		}
+
+		f, err := self.mkdir(MkdirArgs{
+			Name:    op.Name,
+			Parents: parents,
+		})
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Directory %s created", f.Id), nil
+
+	case "share":
+		permission := &drive.Permission{
+			Role:         op.Role,
+			Type:         op.Type,
+			EmailAddress: op.Email,
+			Domain:       op.Domain,
+		}
+		if _, err := self.createPermission(op.Id, permission, true, 0); err != nil {
+			return "", wrapError("Failed to share file", err)
+		}
+		return fmt.Sprintf("Granted %s permission on %s to %s", op.Role, op.Id, op.Type), nil
+
+	case "move":
+		if err := self.moveFile(op.Id, op.Parent); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Moved %s to %s", op.Id, op.Parent), nil
+	}
+
+	// Actions are validated when reading the file
+	return "", fmt.Errorf("Unknown action '%s'", op.Action)
+}
+
+// Moves the file from all its current parents to the given parent
+func (self *Drive) moveFile(id, parent string) error {
+	parent, err := self.resolvePathToId(parent)
+	if err != nil {
+		return err
+	}
+
+	_, err = self.moveToParent(id, parent)
+	return err
+}
+
+func optionalList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return []string{s}
+}
+
+// Files ending with .jsonl, .ndjson or .json are read as one json object per line,
+// all other files are read as csv with a header row
+func readBatchFile(path string) ([]*batchOperation, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, wrapError("Failed to read batch file", err)
+	}
+
+	var ops []*batchOperation
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsonl", ".ndjson", ".json":
+		ops, err = parseBatchJson(content)
+	default:
+		ops, err = parseBatchCsv(content)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ops) == 0 {
+		return nil, fmt.Errorf("No operations found in %s", path)
+	}
+
+	for _, op := range ops {
+		if err := op.validate(); err != nil {
+			return nil, fmt.Errorf("Invalid operation on line %d: %s", op.line, err)
+		}
+	}
+
+	return ops, nil
+}
+
+func parseBatchJson(content []byte) ([]*batchOperation, error) {
+	var ops []*batchOperation
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(nil, 1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		decoder := json.NewDecoder(strings.NewReader(text))
+		decoder.DisallowUnknownFields()
+
+		op := &batchOperation{line: line}
+		if err := decoder.Decode(op); err != nil {
+			return nil, fmt.Errorf("Failed to parse line %d: %s", line, err)
+		}
+		ops = append(ops, op)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, wrapError("Failed to read batch file", err)
+	}
+
+	return ops, nil
+}
+
+func parseBatchCsv(content []byte) ([]*batchOperation, error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, wrapError("Failed to parse csv", err)
+	}
+
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	header := records[0]
+	for _, column := range header {
+		if !containsString(batchColumns, column) {
+			return nil, fmt.Errorf("Unknown column '%s', available columns: %s", column, strings.Join(batchColumns, ", "))
+		}
+	}
+
+	var ops []*batchOperation
+
+	for i, record := range records[1:] {
+		fields := map[string]string{}
+		for j, value := range record {
+			fields[header[j]] = value
+		}
+
+		ops = append(ops, &batchOperation{
+			line:   i + 2,
+			Action: fields["action"],
+			Id:     fields["id"],
+			Path:   fields["path"],
+			Name:   fields["name"],
+			Parent: fields["parent"],
+			Role:   fields["role"],
+			Type:   fields["type"],
+			Email:  fields["email"],
+			Domain: fields["domain"],
+		})
+	}
+
+	return ops, nil
+}
+
+var batchColumns = []string{"action", "id", "path", "name", "parent", "role", "type", "email", "domain"}
+
+// Checks that the fields required by the action are given,
+// and sets the same share defaults as the share command
+func (self *batchOperation) validate() error {
+	require := func(fields ...string) error {
+		values := map[string]string{"id": self.Id, "path": self.Path, "name": self.Name, "parent": self.Parent}
+		for _, field := range fields {
+			if values[field] == "" {
+				return fmt.Errorf("%s requires '%s'", self.Action, field)
+			}
+		}
+		return nil
+	}
+
+	switch self.Action {
+	case "upload":
+		if err := require("path"); err != nil {
+			return err
+		}
+		info, err := os.Stat(self.Path)
+		if err != nil {
+			return wrapError("Failed stat file", err)
+		}
+		if !info.Mode().IsRegular() {
+			return fmt.Errorf("'%s' is not a regular file", self.Path)
+		}
+		return nil
+
+	case "mkdir":
+		return require("name")
+
+	case "move":
+		return require("id", "parent")
+
+	case "share":
+		if self.Role == "" {
+			self.Role = "reader"
+		}
+		if self.Type == "" {
+			self.Type = "anyone"
+		}
+		if (self.Type == "user" || self.Type == "group") && self.Email == "" {
+			return fmt.Errorf("share with type '%s' requires 'email'", self.Type)
+		}
+		if self.Type == "domain" && self.Domain == "" {
+			return fmt.Errorf("share with type 'domain' requires 'domain'")
+		}
+		return require("id")
+
+	case "":
+		return fmt.Errorf("Missing action")
+	}
+
+	return fmt.Errorf("Unknown action '%s', available actions: upload, mkdir, share, move", self.Action)
+}
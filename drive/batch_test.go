@@ -0,0 +1,77 @@
+package drive
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeBatchFile(t *testing.T, lines []string) string {
+	dir, err := ioutil.TempDir("", "gdrive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "batch.jsonl")
+	if err := ioutil.WriteFile(path, []byte(strings.Join(lines, "\n")), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// Rows are run one after another in file order, not only printed in order
+func TestBatchRunsRowsInOrder(t *testing.T) {
+	var lines, expected []string
+	for i := 1; i <= 20; i++ {
+		lines = append(lines, fmt.Sprintf(`{"action":"mkdir","name":"dir%d"}`, i))
+		expected = append(expected, fmt.Sprintf("dir%d", i))
+	}
+	path := writeBatchFile(t, lines)
+
+	for i := 0; i < 5; i++ {
+		d, fake := newFakeDrive(t)
+
+		if err := d.Batch(BatchArgs{Out: &bytes.Buffer{}, Path: path, Concurrency: 1}); err != nil {
+			t.Fatal(err)
+		}
+
+		var names []string
+		for _, id := range fake.order {
+			names = append(names, fake.files[id].Name)
+		}
+		if !reflect.DeepEqual(names, expected) {
+			t.Fatalf("run %d: got %v, want %v", i, names, expected)
+		}
+	}
+}
+
+func TestBatchConcurrentPrintsInOrder(t *testing.T) {
+	var lines, expected []string
+	for i := 1; i <= 20; i++ {
+		lines = append(lines, fmt.Sprintf(`{"action":"move","id":"missing%d","parent":"root"}`, i))
+		expected = append(expected, fmt.Sprintf("Line %d: move failed", i))
+	}
+	path := writeBatchFile(t, lines)
+
+	d, _ := newFakeDrive(t)
+
+	out := &bytes.Buffer{}
+	err := d.Batch(BatchArgs{Out: out, Path: path, Concurrency: 3})
+	if err == nil || err.Error() != "20 of 20 operations failed" {
+		t.Fatalf("got error %v, want all operations failed", err)
+	}
+
+	var prefixes []string
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		prefixes = append(prefixes, line[:strings.Index(line, " failed")+len(" failed")])
+	}
+	if !reflect.DeepEqual(prefixes, expected) {
+		t.Errorf("got %v, want %v", prefixes, expected)
+	}
+}
@@ -0,0 +1,78 @@
+package drive
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+)
+
+// Text formats google documents are exported to when printed
+var catExportMime = map[string]string{
+	"application/vnd.google-apps.document":     "text/plain",
+	"application/vnd.google-apps.presentation": "text/plain",
+	"application/vnd.google-apps.spreadsheet":  "text/csv",
+	"application/vnd.google-apps.script":       "application/vnd.google-apps.script+json",
+}
+
+type CatArgs struct {
+	Out     io.Writer
+	Id      string
+	Timeout time.Duration
+}
+
+// Writes the content of the file with the given id or absolute path to Out,
+// google documents are exported as text
+func (self *Drive) Cat(args CatArgs) error {
+	id, err := self.resolvePathToId(args.Id)
+	if err != nil {
+		return err
+	}
+
+	downloadArgs := DownloadArgs{
+		Out:      args.Out,
+		Progress: ioutil.Discard,
+		Stdout:   true,
+		Timeout:  args.Timeout,
+	}
+
+	f, err := self.service.Files.Get(id).Fields(downloadFields(downloadArgs)...).Do()
+	if err != nil {
+		return wrapError("Failed to get file", err)
+	}
+
+	if isDir(f) {
+		return fmt.Errorf("'%s' is a directory", f.Name)
+	}
+
+	if isBinary(f) {
+		_, _, err = self.downloadBinary(f, downloadArgs)
+		return err
+	}
+
+	if err := unexportableError(f); err != nil {
+		return err
+	}
+
+	exportMime, ok := catExportMime[f.MimeType]
+	if !ok {
+		return fmt.Errorf("'%s' with type '%s' can not be exported as text, see the export command", f.Name, f.MimeType)
+	}
+
+	timeoutReaderWrapper, ctx := getTimeoutReaderWrapperContext(args.Timeout)
+
+	res, err := self.service.Files.Export(f.Id, exportMime).Context(ctx).Download()
+	if err != nil {
+		if isTimeoutError(err) {
+			return fmt.Errorf("Failed to export file: timeout, no data was transferred for %v", args.Timeout)
+		}
+		return wrapError("Failed to export file", err)
+	}
+	defer res.Body.Close()
+
+	if _, err := io.Copy(args.Out, timeoutReaderWrapper(res.Body)); err != nil {
+		return wrapError("Failed writing to stdout", err)
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"google.golang.org/api/drive/v3"
 	"io"
+	"io/ioutil"
 	"text/tabwriter"
 )
 
@@ -14,7 +15,10 @@ type ListChangesArgs struct {
 	Now        bool
 	NameWidth  int64
 	SkipHeader bool
-	UseCsv	   bool
+	UseCsv     bool
+	All        bool
+	TokenOut   io.Writer
+	TokenFile  string
 }
 
 func (self *Drive) ListChanges(args ListChangesArgs) error {
@@ -28,9 +32,13 @@ func (self *Drive) ListChanges(args ListChangesArgs) error {
 		return nil
 	}
 
+	if args.All {
+		return self.listAllChanges(args)
+	}
+
 	changeList, err := self.service.Changes.List(args.PageToken).PageSize(args.MaxChanges).RestrictToMyDrive(true).Fields("newStartPageToken", "nextPageToken", "changes(fileId,removed,time,file(id,name,md5Checksum,mimeType,createdTime,modifiedTime))").Do()
 	if err != nil {
-		return fmt.Errorf("Failed listing changes: %s", err)
+		return wrapError("Failed listing changes", err)
 	}
 
 	PrintChanges(PrintChangesArgs{
@@ -43,10 +51,51 @@ func (self *Drive) ListChanges(args ListChangesArgs) error {
 	return nil
 }
 
+// Lists all changes since the page token, the new start page token is written
+// to the token file, or to TokenOut if no file is given, to be used the next time
+func (self *Drive) listAllChanges(args ListChangesArgs) error {
+	var changes []*drive.Change
+	pageToken := args.PageToken
+
+	for {
+		changeList, err := self.service.Changes.List(pageToken).PageSize(args.MaxChanges).RestrictToMyDrive(true).Fields("newStartPageToken", "nextPageToken", "changes(fileId,removed,time,file(id,name,md5Checksum,mimeType,createdTime,modifiedTime))").Do()
+		if err != nil {
+			return wrapError("Failed listing changes", err)
+		}
+
+		changes = append(changes, changeList.Changes...)
+
+		var hasMore bool
+		pageToken, hasMore = nextChangesPageToken(changeList)
+		if !hasMore {
+			break
+		}
+	}
+
+	PrintChanges(PrintChangesArgs{
+		Out:        args.Out,
+		ChangeList: &drive.ChangeList{Changes: changes},
+		NameWidth:  int(args.NameWidth),
+		SkipHeader: args.SkipHeader,
+		HideToken:  true,
+	})
+
+	if args.TokenFile != "" {
+		err := ioutil.WriteFile(args.TokenFile, []byte(pageToken+"\n"), 0600)
+		if err != nil {
+			return wrapError("Failed to write page token", err)
+		}
+		return nil
+	}
+
+	fmt.Fprintf(args.TokenOut, "Token: %s\n", pageToken)
+	return nil
+}
+
 func (self *Drive) GetChangesStartPageToken() (string, error) {
 	res, err := self.service.Changes.GetStartPageToken().Do()
 	if err != nil {
-		return "", fmt.Errorf("Failed getting start page token: %s", err)
+		return "", wrapError("Failed getting start page token", err)
 	}
 
 	return res.StartPageToken, nil
@@ -57,6 +106,7 @@ type PrintChangesArgs struct {
 	ChangeList *drive.ChangeList
 	NameWidth  int
 	SkipHeader bool
+	HideToken  bool
 }
 
 func PrintChanges(args PrintChangesArgs) {
@@ -88,8 +138,10 @@ func PrintChanges(args PrintChangesArgs) {
 
 	if len(args.ChangeList.Changes) > 0 {
 		w.Flush()
-		pageToken, hasMore := nextChangesPageToken(args.ChangeList)
-		fmt.Fprintf(args.Out, "\nToken: %s, more: %t\n", pageToken, hasMore)
+		if !args.HideToken {
+			pageToken, hasMore := nextChangesPageToken(args.ChangeList)
+			fmt.Fprintf(args.Out, "\nToken: %s, more: %t\n", pageToken, hasMore)
+		}
 	} else {
 		fmt.Fprintln(args.Out, "No changes")
 	}
@@ -0,0 +1,134 @@
+package drive
+
+import (
+	"fmt"
+	"google.golang.org/api/drive/v3"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type WatchChangesArgs struct {
+	Out io.Writer
+	Err io.Writer
+	// Defaults to the page token in the token file, or the current start page token
+	PageToken  string
+	TokenFile  string
+	Interval   time.Duration
+	Once       bool
+	MaxChanges int64
+	NameWidth  int64
+	// Only list changes to files in the My Drive hierarchy
+	RestrictToMyDrive bool
+}
+
+// Polls the changes feed and prints each change as it is detected. Push
+// notifications require a public webhook, so this is the fallback.
+// The page token is written to the token file after each poll
+func (self *Drive) WatchChanges(args WatchChangesArgs) error {
+	pageToken, err := self.watchStartPageToken(args)
+	if err != nil {
+		return err
+	}
+
+	for {
+		changes, nextToken, err := self.pollChanges(pageToken, args)
+		if ae, ok := apiError(err); ok && ae.Code == http.StatusGone {
+			nextToken, err = self.GetChangesStartPageToken()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(args.Err, "Page token %s is no longer valid, continuing from %s, some changes may have been missed\n", pageToken, nextToken)
+		} else if err != nil {
+			return err
+		}
+
+		for _, c := range changes {
+			printWatchedChange(args.Out, c, int(args.NameWidth))
+		}
+
+		pageToken = nextToken
+		if args.TokenFile != "" {
+			err := ioutil.WriteFile(args.TokenFile, []byte(pageToken+"\n"), 0600)
+			if err != nil {
+				return wrapError("Failed to write page token", err)
+			}
+		}
+
+		if args.Once {
+			if args.TokenFile == "" {
+				fmt.Fprintf(args.Err, "Token: %s\n", pageToken)
+			}
+			return nil
+		}
+
+		time.Sleep(args.Interval)
+	}
+}
+
+func (self *Drive) watchStartPageToken(args WatchChangesArgs) (string, error) {
+	if args.PageToken != "" {
+		return args.PageToken, nil
+	}
+
+	if args.TokenFile != "" && fileExists(args.TokenFile) {
+		content, err := ioutil.ReadFile(args.TokenFile)
+		if err != nil {
+			return "", wrapError("Failed to read page token", err)
+		}
+		if token := strings.TrimSpace(string(content)); token != "" {
+			return token, nil
+		}
+	}
+
+	pageToken, err := self.GetChangesStartPageToken()
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(args.Err, "Watching changes from page token %s\n", pageToken)
+	return pageToken, nil
+}
+
+// Lists all changes since the page token and returns them with the new start
+// page token. Backend and rate limit errors are retried, expired page tokens
+// give a 410 Gone api error
+func (self *Drive) pollChanges(pageToken string, args WatchChangesArgs) ([]*drive.Change, string, error) {
+	var changes []*drive.Change
+	try := 0
+
+	for {
+		changeList, err := self.service.Changes.List(pageToken).PageSize(args.MaxChanges).RestrictToMyDrive(args.RestrictToMyDrive).Fields("newStartPageToken", "nextPageToken", "changes(fileId,removed,time,file(id,name,md5Checksum,mimeType,createdTime,modifiedTime))").Do()
+		if isBackendOrRateLimitError(err) && try < MaxErrorRetries {
+			exponentialBackoffSleep(try)
+			try++
+			continue
+		} else if err != nil {
+			return nil, "", wrapError("Failed listing changes", err)
+		}
+		try = 0
+
+		changes = append(changes, changeList.Changes...)
+
+		var hasMore bool
+		pageToken, hasMore = nextChangesPageToken(changeList)
+		if !hasMore {
+			return changes, pageToken, nil
+		}
+	}
+}
+
+// Changes are printed one per line as they arrive, so the columns are not aligned
+func printWatchedChange(out io.Writer, c *drive.Change, nameWidth int) {
+	action := "update"
+	var name string
+
+	if c.Removed {
+		action = "remove"
+	} else if c.File != nil {
+		name = truncateString(c.File.Name, nameWidth)
+	}
+
+	fmt.Fprintf(out, "%s   %s   %s   %s\n", formatDatetime(c.Time), action, c.FileId, name)
+}
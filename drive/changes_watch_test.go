@@ -0,0 +1,58 @@
+package drive
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// A backend error while polling is retried instead of stopping the watch
+func TestWatchChangesRetriesBackendErrors(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Query().Get("restrictToMyDrive"))
+		if len(requests) == 1 {
+			fakeError(w, http.StatusServiceUnavailable, "Backend Error")
+			return
+		}
+		fmt.Fprint(w, `{"newStartPageToken":"11","changes":[{"fileId":"id1","time":"2020-05-01T10:00:00Z","file":{"id":"id1","name":"file1"}}]}`)
+	}))
+	defer srv.Close()
+
+	d, err := New(srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.service.BasePath = srv.URL + "/"
+
+	out := &bytes.Buffer{}
+	errOut := &bytes.Buffer{}
+	err = d.WatchChanges(WatchChangesArgs{
+		Out:        out,
+		Err:        errOut,
+		PageToken:  "10",
+		Once:       true,
+		MaxChanges: 100,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(requests) != 2 {
+		t.Errorf("got %d requests, want 2", len(requests))
+	}
+	if !strings.Contains(out.String(), "id1") {
+		t.Errorf("change was not printed: %q", out.String())
+	}
+	if errOut.String() != "Token: 11\n" {
+		t.Errorf("got %q, want the new page token", errOut.String())
+	}
+	for _, restrict := range requests {
+		if restrict != "false" {
+			t.Errorf("got restrictToMyDrive %q, want false", restrict)
+		}
+	}
+}
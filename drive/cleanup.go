@@ -0,0 +1,74 @@
+package drive
+
+import (
+	"fmt"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+	"io"
+	"time"
+)
+
+type CleanupArgs struct {
+	Out            io.Writer
+	OlderThan      time.Duration
+	Parent         string
+	IncludeFolders bool
+	Force          bool
+	SizeInBytes    bool
+	DecimalUnits   bool
+}
+
+// Trashes files not modified within the given duration.
+// Nothing is trashed unless force is given, the files are only listed
+func (self *Drive) Cleanup(args CleanupArgs) error {
+	cutoff := time.Now().Add(-args.OlderThan).UTC().Format(time.RFC3339)
+
+	query := fmt.Sprintf("trashed = false and 'me' in owners and modifiedTime < '%s'", cutoff)
+	if args.Parent != "" {
+		query += fmt.Sprintf(" and '%s' in parents", escapeQueryValue(args.Parent))
+	}
+	if !args.IncludeFolders {
+		query += fmt.Sprintf(" and mimeType != '%s'", DirectoryMimeType)
+	}
+
+	files, err := self.listAllFiles(listAllFilesArgs{
+		query:  query,
+		fields: []googleapi.Field{"nextPageToken", "files(id,name,mimeType,size,createdTime,modifiedTime)"},
+	})
+	if err != nil {
+		return wrapError("Failed to list files", err)
+	}
+
+	if len(files) == 0 {
+		fmt.Fprintf(args.Out, "No files modified before %s\n", formatDatetime(cutoff))
+		return nil
+	}
+
+	PrintTabbedFileList(PrintFileListArgs{
+		Out:          args.Out,
+		Files:        files,
+		SizeInBytes:  args.SizeInBytes,
+		DecimalUnits: args.DecimalUnits,
+		Columns:      []string{"id", "name", "type", "size", "modified"},
+	})
+
+	var total int64
+	for _, f := range files {
+		total += f.Size
+	}
+
+	if !args.Force {
+		fmt.Fprintf(args.Out, "Found %d files, total %s, use --force to move them to trash\n", len(files), formatSize(total, args.SizeInBytes, args.DecimalUnits))
+		return nil
+	}
+
+	for _, f := range files {
+		_, err := self.service.Files.Update(f.Id, &drive.File{Trashed: true}).Fields("id").Do()
+		if err != nil {
+			return fmt.Errorf("Failed to trash '%s': %s", f.Name, err)
+		}
+	}
+
+	fmt.Fprintf(args.Out, "Moved %d files to trash, total %s\n", len(files), formatSize(total, args.SizeInBytes, args.DecimalUnits))
+	return nil
+}
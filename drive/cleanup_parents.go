@@ -0,0 +1,103 @@
+package drive
+
+import (
+	"fmt"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+type CleanupParentsArgs struct {
+	Out io.Writer
+	// Only check files in the given directory
+	Parent string
+	// Parent to keep for files that have it, otherwise the first parent is kept
+	KeepParent string
+	Fix        bool
+}
+
+// Finds files with more than one parent, or the same parent listed twice, left
+// from the old multi-parent model. Parents are only collapsed to a single one when Fix is set
+func (self *Drive) CleanupParents(args CleanupParentsArgs) error {
+	query := "trashed = false and 'me' in owners"
+	if args.Parent != "" {
+		parentId, err := self.resolvePathToId(args.Parent)
+		if err != nil {
+			return err
+		}
+		query += fmt.Sprintf(" and '%s' in parents", escapeQueryValue(parentId))
+	}
+
+	files, err := self.listAllFiles(listAllFilesArgs{
+		query:  query,
+		fields: []googleapi.Field{"nextPageToken", "files(id,name,parents)"},
+		filter: func(f *drive.File) bool {
+			return len(f.Parents) > 1
+		},
+	})
+	if err != nil {
+		return wrapError("Failed to list files", err)
+	}
+
+	if len(files) == 0 {
+		fmt.Fprintln(args.Out, "No files with multiple parents found")
+		return nil
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(args.Out, 0, 0, 3, ' ', 0)
+
+	fmt.Fprintln(w, "Id\tName\tParents\tKeep")
+	for _, f := range files {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", f.Id, truncateString(f.Name, 40), strings.Join(f.Parents, ", "), keptParent(f, args.KeepParent))
+	}
+	w.Flush()
+
+	if !args.Fix {
+		fmt.Fprintf(args.Out, "Found %d files with multiple parents, use --fix to keep a single parent\n", len(files))
+		return nil
+	}
+
+	for _, f := range files {
+		keep := keptParent(f, args.KeepParent)
+
+		// Adding the kept parent again is a no-op, but leaves a single
+		// entry when the same parent was listed more than once
+		call := self.service.Files.Update(f.Id, &drive.File{}).AddParents(keep).Fields("id")
+		if others := otherParents(f, keep); len(others) > 0 {
+			call.RemoveParents(strings.Join(others, ","))
+		}
+
+		if _, err := call.Do(); err != nil {
+			return wrapError(fmt.Sprintf("Failed to update parents of '%s'", f.Name), err)
+		}
+	}
+
+	fmt.Fprintf(args.Out, "Collapsed the parents of %d files\n", len(files))
+	return nil
+}
+
+func keptParent(f *drive.File, preferred string) string {
+	for _, parent := range f.Parents {
+		if parent == preferred {
+			return parent
+		}
+	}
+	return f.Parents[0]
+}
+
+// Returns the distinct parents other than the kept one
+func otherParents(f *drive.File, keep string) []string {
+	seen := map[string]bool{keep: true}
+	var others []string
+
+	for _, parent := range f.Parents {
+		if !seen[parent] {
+			seen[parent] = true
+			others = append(others, parent)
+		}
+	}
+	return others
+}
@@ -0,0 +1,175 @@
+package drive
+
+import (
+	"encoding/json"
+	"fmt"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+type CopyArgs struct {
+	Out     io.Writer
+	Id      string
+	Name    string
+	Parents []string
+	// Recreate the permissions of the source file on the copy
+	PreservePermissions bool
+	// Send notification emails to the users and groups given the permissions
+	Notify bool
+}
+
+// Permission as returned by the permissions.list call, the client library
+// in use does not include the permission details telling if it is inherited
+type sourcePermission struct {
+	Id                 string `json:"id"`
+	Type               string `json:"type"`
+	Role               string `json:"role"`
+	EmailAddress       string `json:"emailAddress"`
+	Domain             string `json:"domain"`
+	AllowFileDiscovery bool   `json:"allowFileDiscovery"`
+	PermissionDetails  []struct {
+		Inherited bool `json:"inherited"`
+	} `json:"permissionDetails"`
+}
+
+type sourcePermissionList struct {
+	Permissions   []*sourcePermission `json:"permissions"`
+	NextPageToken string              `json:"nextPageToken"`
+}
+
+func (self *Drive) Copy(args CopyArgs) error {
+	// Parents starting with / are paths
+	parents, err := self.resolvePathsToIds(args.Parents)
+	if err != nil {
+		return err
+	}
+
+	dstFile := &drive.File{
+		Name:    args.Name,
+		Parents: parents,
+	}
+
+	f, err := self.service.Files.Copy(args.Id, dstFile).Fields("id", "name").Do()
+	if err != nil {
+		return wrapError("Failed to copy file", err)
+	}
+
+	fmt.Fprintf(args.Out, "Copied %s to '%s' with id: %s\n", args.Id, f.Name, f.Id)
+
+	if args.PreservePermissions {
+		return self.copyPermissions(args.Out, args.Id, f.Id, args.Notify)
+	}
+
+	return nil
+}
+
+// The owner permission is skipped as the copy is owned by the user making it,
+// inherited permissions are given to the copy by its parents
+func (self *Drive) copyPermissions(out io.Writer, srcId, dstId string, notify bool) error {
+	permissions, err := self.listSourcePermissions(srcId)
+	if err != nil {
+		return err
+	}
+
+	copied := 0
+
+	for _, p := range permissions {
+		if p.Role == "owner" || isInherited(p) {
+			continue
+		}
+
+		permission := &drive.Permission{
+			Type:               p.Type,
+			Role:               p.Role,
+			EmailAddress:       p.EmailAddress,
+			Domain:             p.Domain,
+			AllowFileDiscovery: p.AllowFileDiscovery,
+		}
+
+		if _, err := self.createPermission(dstId, permission, notify, 0); err != nil {
+			return wrapError(fmt.Sprintf("Failed to copy %s permission for %s", p.Role, permissionTarget(p)), err)
+		}
+
+		fmt.Fprintf(out, "Granted %s permission to %s\n", p.Role, permissionTarget(p))
+		copied++
+	}
+
+	fmt.Fprintf(out, "Copied %d permissions\n", copied)
+	return nil
+}
+
+func (self *Drive) listSourcePermissions(fileId string) ([]*sourcePermission, error) {
+	var permissions []*sourcePermission
+	pageToken := ""
+
+	for {
+		list, err := self.listSourcePermissionsPage(fileId, pageToken)
+		if err != nil {
+			return nil, wrapError("Failed to list permissions", err)
+		}
+
+		permissions = append(permissions, list.Permissions...)
+
+		if list.NextPageToken == "" {
+			return permissions, nil
+		}
+		pageToken = list.NextPageToken
+	}
+}
+
+func (self *Drive) listSourcePermissionsPage(fileId, pageToken string) (*sourcePermissionList, error) {
+	params := url.Values{}
+	params.Set("fields", "nextPageToken,permissions(id,type,role,emailAddress,domain,allowFileDiscovery,permissionDetails(inherited))")
+	if pageToken != "" {
+		params.Set("pageToken", pageToken)
+	}
+
+	urls := googleapi.ResolveRelative(self.service.BasePath, "files/{fileId}/permissions") + "?" + params.Encode()
+	req, _ := http.NewRequest("GET", urls, nil)
+	googleapi.Expand(req.URL, map[string]string{
+		"fileId": fileId,
+	})
+
+	res, err := self.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if err := googleapi.CheckResponse(res); err != nil {
+		return nil, err
+	}
+
+	list := &sourcePermissionList{}
+	if err := json.NewDecoder(res.Body).Decode(list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Permission details are only given for files in shared drives
+func isInherited(p *sourcePermission) bool {
+	if len(p.PermissionDetails) == 0 {
+		return false
+	}
+
+	for _, details := range p.PermissionDetails {
+		if !details.Inherited {
+			return false
+		}
+	}
+	return true
+}
+
+func permissionTarget(p *sourcePermission) string {
+	switch {
+	case p.EmailAddress != "":
+		return p.EmailAddress
+	case p.Domain != "":
+		return p.Domain
+	}
+	return p.Type
+}
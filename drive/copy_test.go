@@ -0,0 +1,75 @@
+package drive
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"google.golang.org/api/drive/v3"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// Permissions are listed page by page and copied without notification emails
+// unless asked for
+func TestCopyPreservePermissions(t *testing.T) {
+	for _, notify := range []bool{false, true} {
+		var created []string
+		var notifications []string
+		mutex := sync.Mutex{}
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			switch {
+			case r.URL.Path == "/files/src/copy":
+				fmt.Fprint(w, `{"id":"dst","name":"Copy of src"}`)
+			case r.URL.Path == "/files/src/permissions" && r.URL.Query().Get("pageToken") == "":
+				fmt.Fprint(w, `{"nextPageToken":"2","permissions":[{"id":"1","type":"user","role":"owner","emailAddress":"owner@example.com"},{"id":"2","type":"user","role":"writer","emailAddress":"a@example.com"}]}`)
+			case r.URL.Path == "/files/src/permissions" && r.URL.Query().Get("pageToken") == "2":
+				fmt.Fprint(w, `{"permissions":[{"id":"3","type":"user","role":"reader","emailAddress":"b@example.com"}]}`)
+			case r.URL.Path == "/files/dst/permissions" && r.Method == "POST":
+				permission := &drive.Permission{}
+				json.NewDecoder(r.Body).Decode(permission)
+				created = append(created, permission.EmailAddress)
+				notifications = append(notifications, r.URL.Query().Get("sendNotificationEmail"))
+				fmt.Fprint(w, `{"id":"permission"}`)
+			default:
+				t.Errorf("unexpected request %s %s", r.Method, r.URL)
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer srv.Close()
+
+		d, err := New(srv.Client())
+		if err != nil {
+			t.Fatal(err)
+		}
+		d.service.BasePath = srv.URL + "/"
+
+		err = d.Copy(CopyArgs{
+			Out:                 &bytes.Buffer{},
+			Id:                  "src",
+			PreservePermissions: true,
+			Notify:              notify,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if expected := []string{"a@example.com", "b@example.com"}; !reflect.DeepEqual(created, expected) {
+			t.Errorf("notify %v: got permissions for %v, want %v", notify, created, expected)
+		}
+
+		expected := []string{"false", "false"}
+		if notify {
+			expected = []string{"", ""}
+		}
+		if !reflect.DeepEqual(notifications, expected) {
+			t.Errorf("notify %v: got sendNotificationEmail %q, want %q", notify, notifications, expected)
+		}
+	}
+}
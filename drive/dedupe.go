@@ -0,0 +1,125 @@
+package drive
+
+import (
+	"fmt"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+	"io"
+	"sort"
+)
+
+type DedupeArgs struct {
+	Out             io.Writer
+	Id              string
+	TrashDuplicates bool
+	SizeInBytes     bool
+	DecimalUnits    bool
+}
+
+// Finds files with the same content in a directory. Files are only trashed
+// when TrashDuplicates is set, the oldest file of each group is kept
+func (self *Drive) Dedupe(args DedupeArgs) error {
+	f, err := self.service.Files.Get(args.Id).Fields("id", "name", "mimeType").Do()
+	if err != nil {
+		return wrapError("Failed to get file", err)
+	}
+
+	if !isDir(f) {
+		return fmt.Errorf("'%s' is not a directory", f.Name)
+	}
+
+	files, err := self.listAllFiles(listAllFilesArgs{
+		query:  fmt.Sprintf("'%s' in parents and trashed = false", f.Id),
+		fields: []googleapi.Field{"nextPageToken", "files(id,name,mimeType,size,md5Checksum,createdTime)"},
+	})
+	if err != nil {
+		return wrapError("Failed listing files", err)
+	}
+
+	groups := duplicateGroups(files)
+	if len(groups) == 0 {
+		fmt.Fprintf(args.Out, "No duplicates found in '%s'\n", f.Name)
+		return nil
+	}
+
+	var count int
+	var reclaimed int64
+
+	for _, group := range groups {
+		keep := group[0]
+		fmt.Fprintf(args.Out, "Keeping %s %s, created %s\n", keep.Id, keep.Name, formatDatetime(keep.CreatedTime))
+
+		for _, dup := range group[1:] {
+			fmt.Fprintf(args.Out, "  Duplicate %s %s, created %s\n", dup.Id, dup.Name, formatDatetime(dup.CreatedTime))
+
+			if args.TrashDuplicates {
+				_, err := self.service.Files.Update(dup.Id, &drive.File{Trashed: true}).Fields("id").Do()
+				if err != nil {
+					return fmt.Errorf("Failed to trash '%s': %s", dup.Name, err)
+				}
+			}
+
+			count++
+			reclaimed += dup.Size
+		}
+	}
+
+	if args.TrashDuplicates {
+		fmt.Fprintf(args.Out, "Moved %d duplicates to trash, reclaimed %s\n", count, formatSize(reclaimed, args.SizeInBytes, args.DecimalUnits))
+	} else {
+		fmt.Fprintf(args.Out, "Found %d duplicates using %s, use --trash-duplicates to move them to trash\n", count, formatSize(reclaimed, args.SizeInBytes, args.DecimalUnits))
+	}
+
+	return nil
+}
+
+// Groups files by checksum, only groups with more than one file are returned.
+// Each group is sorted by created time with the oldest file first.
+// Directories and files without checksum (google documents) are skipped
+func duplicateGroups(files []*drive.File) [][]*drive.File {
+	byMd5 := map[string][]*drive.File{}
+	var checksums []string
+
+	for _, f := range files {
+		if isDir(f) || f.Md5Checksum == "" {
+			continue
+		}
+
+		if _, ok := byMd5[f.Md5Checksum]; !ok {
+			checksums = append(checksums, f.Md5Checksum)
+		}
+		byMd5[f.Md5Checksum] = append(byMd5[f.Md5Checksum], f)
+	}
+
+	var groups [][]*drive.File
+
+	for _, md5 := range checksums {
+		group := byMd5[md5]
+		if len(group) < 2 {
+			continue
+		}
+
+		sort.Stable(byCreatedTime(group))
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
+type byCreatedTime []*drive.File
+
+func (self byCreatedTime) Len() int {
+	return len(self)
+}
+
+func (self byCreatedTime) Swap(i, j int) {
+	self[i], self[j] = self[j], self[i]
+}
+
+// Ties are broken by id, so the same file is kept on every run
+func (self byCreatedTime) Less(i, j int) bool {
+	if self[i].CreatedTime != self[j].CreatedTime {
+		return self[i].CreatedTime < self[j].CreatedTime
+	}
+	return self[i].Id < self[j].Id
+}
@@ -2,38 +2,175 @@ package drive
 
 import (
 	"fmt"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
 	"io"
+	"path/filepath"
+	"strings"
 )
 
 type DeleteArgs struct {
 	Out       io.Writer
 	Id        string
 	Recursive bool
+	Confirm   ConfirmFunc
+	// Delete the directory content file by file, logging failures and
+	// keeping going instead of deleting the directory in a single request
+	ContinueOnError bool
+	// Only delete files and keep the directories, or only delete
+	// directories without any files in them
+	OnlyFiles   bool
+	OnlyFolders bool
+	// Only print what would be deleted, nothing is confirmed
+	DryRun       bool
+	DecimalUnits bool
 }
 
 func (self *Drive) Delete(args DeleteArgs) error {
-	f, err := self.service.Files.Get(args.Id).Fields("name", "mimeType").Do()
+	f, err := self.service.Files.Get(args.Id).Fields("id", "name", "mimeType", "size").Do()
 	if err != nil {
-		return fmt.Errorf("Failed to get file: %s", err)
+		return wrapError("Failed to get file", err)
 	}
 
 	if isDir(f) && !args.Recursive {
 		return fmt.Errorf("'%s' is a directory, use the 'recursive' flag to delete directories", f.Name)
 	}
 
+	if (args.OnlyFiles || args.OnlyFolders) && !isDir(f) {
+		return fmt.Errorf("'%s' is not a directory, only the content of directories can be filtered by type", f.Name)
+	}
+
+	message := fmt.Sprintf("Permanently delete '%s'", f.Name)
+	if args.OnlyFiles {
+		message = fmt.Sprintf("Permanently delete all files in directory '%s', keeping the directories", f.Name)
+	} else if args.OnlyFolders {
+		message = fmt.Sprintf("Permanently delete all directories without files in directory '%s'", f.Name)
+	} else if isDir(f) {
+		message = fmt.Sprintf("Permanently delete directory '%s' and all its content", f.Name)
+	} else if f.Size > 0 {
+		message += fmt.Sprintf(", %s", formatSize(f.Size, false, args.DecimalUnits))
+	}
+
+	if args.DryRun {
+		printDryRun(args.Out, "%s (%s)", strings.ToLower(message[:1])+message[1:], f.Id)
+		return nil
+	}
+
+	if err := confirm(args.Confirm, message); err != nil {
+		return err
+	}
+
+	if isDir(f) && (args.ContinueOnError || args.OnlyFiles || args.OnlyFolders) {
+		// The id may be an alias like root, the walk compares with the actual id
+		args.Id = f.Id
+		failures := newFailureSummary(args.ContinueOnError)
+		summary := &deleteSummary{}
+
+		_, err = self.deleteTree(f, f.Name, args, failures, summary)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(args.Out, "Deleted %d files and %d directories\n", summary.files, summary.directories)
+		return failures.print(args.Out)
+	}
+
 	err = self.service.Files.Delete(args.Id).Do()
 	if err != nil {
-		return fmt.Errorf("Failed to delete file: %s", err)
+		return wrapError("Failed to delete file", err)
 	}
 
 	fmt.Fprintf(args.Out, "Deleted '%s'\n", f.Name)
 	return nil
 }
 
+type deleteSummary struct {
+	files       int
+	directories int
+}
+
+// Deletes the directory content before the directory itself, the directory
+// is kept if any of its content was not deleted. When only deleting directories
+// the directory of the operation is always kept. Returns true if the directory was deleted
+func (self *Drive) deleteTree(parent *drive.File, path string, args DeleteArgs, failures *failureSummary, summary *deleteSummary) (bool, error) {
+	listArgs := listAllFilesArgs{
+		query:  fmt.Sprintf("'%s' in parents", parent.Id),
+		fields: []googleapi.Field{"nextPageToken", "files(id,name,mimeType)"},
+	}
+	files, err := self.listAllFiles(listArgs)
+	if err != nil {
+		return false, failures.record(args.Out, path, wrapError("Failed listing files", err))
+	}
+
+	empty := true
+
+	for _, f := range files {
+		fpath := filepath.Join(path, f.Name)
+
+		if isDir(f) {
+			deleted, err := self.deleteTree(f, fpath, args, failures, summary)
+			if err != nil {
+				return false, err
+			}
+			empty = empty && deleted
+			continue
+		}
+
+		// Files are kept when only deleting empty directories
+		if args.OnlyFolders {
+			empty = false
+			continue
+		}
+
+		err = self.deleteFile(f.Id)
+		if err == nil {
+			fmt.Fprintf(args.Out, "Deleted '%s'\n", fpath)
+			summary.files++
+		} else {
+			empty = false
+		}
+
+		if err = failures.record(args.Out, fpath, err); err != nil {
+			return false, err
+		}
+	}
+
+	if args.OnlyFiles || (args.OnlyFolders && parent.Id == args.Id) {
+		return false, nil
+	}
+
+	if !empty {
+		if !args.OnlyFolders {
+			fmt.Fprintf(args.Out, "Keeping directory '%s', not all of its content was deleted\n", path)
+		}
+		return false, nil
+	}
+
+	if err := self.deleteFile(parent.Id); err != nil {
+		return false, failures.record(args.Out, path, err)
+	}
+
+	fmt.Fprintf(args.Out, "Deleted '%s'\n", path)
+	summary.directories++
+	return true, nil
+}
+
 func (self *Drive) deleteFile(fileId string) error {
 	err := self.service.Files.Delete(fileId).Do()
 	if err != nil {
-		return fmt.Errorf("Failed to delete file: %s", err)
+		return wrapError("Failed to delete file", err)
 	}
 	return nil
 }
+
+// Asks the user to confirm a destructive operation described by the message,
+// returns an error if the operation should not proceed
+type ConfirmFunc func(message string) error
+
+// Operations are not confirmed if no confirm function is given
+func confirm(fn ConfirmFunc, message string) error {
+	if fn == nil {
+		return nil
+	}
+	return fn(message)
+}
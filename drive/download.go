@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"google.golang.org/api/drive/v3"
@@ -12,34 +13,60 @@ import (
 )
 
 type DownloadArgs struct {
-	Out       io.Writer
-	Progress  io.Writer
-	Id        string
-	Path      string
-	Force     bool
-	Skip      bool
-	Recursive bool
-	Delete    bool
-	Stdout    bool
-	Timeout   time.Duration
+	Out           io.Writer
+	Progress      io.Writer
+	Id            string
+	Path          string
+	Force         bool
+	Skip          bool
+	Recursive     bool
+	Delete        bool
+	Stdout        bool
+	Resume        bool
+	PreserveMtime bool
+	Timeout       time.Duration
+	// Log failed files and keep going when downloading recursively
+	ContinueOnError bool
+	// Record the size and checksums of downloaded files in a csv file
+	ManifestPath string
+	manifest     *downloadManifest
+	DecimalUnits bool
 }
 
 func (self *Drive) Download(args DownloadArgs) error {
+	if args.ManifestPath != "" {
+		manifest, err := newDownloadManifest(args.ManifestPath)
+		if err != nil {
+			return err
+		}
+		defer manifest.close()
+		args.manifest = manifest
+	}
+
 	if args.Recursive {
-		return self.downloadRecursive(args)
+		failures := newFailureSummary(args.ContinueOnError)
+		err := self.downloadRecursive(args, failures)
+		if err != nil {
+			return err
+		}
+		return failures.print(args.Out)
 	}
 
-	f, err := self.service.Files.Get(args.Id).Fields("id", "name", "size", "mimeType", "md5Checksum").Do()
+	f, err := self.service.Files.Get(args.Id).Fields(downloadFields(args)...).Do()
 	if err != nil {
-		return fmt.Errorf("Failed to get file: %s", err)
+		return wrapError("Failed to get file", err)
 	}
 
 	if isDir(f) {
 		return fmt.Errorf("'%s' is a directory, use --recursive to download directories", f.Name)
 	}
 
+	if err := unexportableError(f); err != nil {
+		return err
+	}
+
 	if !isBinary(f) {
-		return fmt.Errorf("'%s' is a google document and must be exported, see the export command", f.Name)
+		return fmt.Errorf("'%s' is a google document with type '%s' and must be exported, see the export command", f.Name, f.MimeType)
 	}
 
 	bytes, rate, err := self.downloadBinary(f, args)
@@ -48,13 +75,13 @@ func (self *Drive) Download(args DownloadArgs) error {
 	}
 
 	if !args.Stdout {
-		fmt.Fprintf(args.Out, "Downloaded %s at %s/s, total %s\n", f.Id, formatSize(rate, false), formatSize(bytes, false))
+		fmt.Fprintf(args.Out, "Downloaded %s at %s/s, total %s\n", f.Id, formatSize(rate, false, args.DecimalUnits), formatSize(bytes, false, args.DecimalUnits))
 	}
 
 	if args.Delete {
 		err = self.deleteFile(args.Id)
 		if err != nil {
-			return fmt.Errorf("Failed to delete file: %s", err)
+			return wrapError("Failed to delete file", err)
 		}
 
 		if !args.Stdout {
@@ -65,13 +92,14 @@ func (self *Drive) Download(args DownloadArgs) error {
 }
 
 type DownloadQueryArgs struct {
-	Out       io.Writer
-	Progress  io.Writer
-	Query     string
-	Path      string
-	Force     bool
-	Skip      bool
-	Recursive bool
+	Out          io.Writer
+	Progress     io.Writer
+	Query        string
+	Path         string
+	Force        bool
+	Skip         bool
+	Recursive    bool
+	DecimalUnits bool
 }
 
 func (self *Drive) DownloadQuery(args DownloadQueryArgs) error {
@@ -81,20 +109,21 @@ func (self *Drive) DownloadQuery(args DownloadQueryArgs) error {
 	}
 	files, err := self.listAllFiles(listArgs)
 	if err != nil {
-		return fmt.Errorf("Failed to list files: %s", err)
+		return wrapError("Failed to list files", err)
 	}
 
 	downloadArgs := DownloadArgs{
-		Out:      args.Out,
-		Progress: args.Progress,
-		Path:     args.Path,
-		Force:    args.Force,
-		Skip:     args.Skip,
+		Out:          args.Out,
+		Progress:     args.Progress,
+		Path:         args.Path,
+		Force:        args.Force,
+		Skip:         args.Skip,
+		DecimalUnits: args.DecimalUnits,
 	}
 
 	for _, f := range files {
 		if isDir(f) && args.Recursive {
-			err = self.downloadDirectory(f, downloadArgs)
+			err = self.downloadDirectory(f, downloadArgs, nil)
 		} else if isBinary(f) {
 			_, _, err = self.downloadBinary(f, downloadArgs)
 		}
@@ -107,23 +136,55 @@ func (self *Drive) DownloadQuery(args DownloadQueryArgs) error {
 	return nil
 }
 
-func (self *Drive) downloadRecursive(args DownloadArgs) error {
-	f, err := self.service.Files.Get(args.Id).Fields("id", "name", "size", "mimeType", "md5Checksum").Do()
+func (self *Drive) downloadRecursive(args DownloadArgs, failures *failureSummary) error {
+	f, err := self.service.Files.Get(args.Id).Fields(downloadFields(args)...).Do()
 	if err != nil {
-		return fmt.Errorf("Failed to get file: %s", err)
+		return failures.record(args.Out, args.Id, wrapError("Failed to get file", err))
+	}
+
+	if err := unexportableError(f); err != nil {
+		// Skipped instead of failed when continuing on error
+		if args.ContinueOnError {
+			fmt.Fprintf(args.Out, "Skipping %s: %s\n", filepath.Join(args.Path, f.Name), err)
+			return nil
+		}
+		return err
 	}
 
 	if isDir(f) {
-		return self.downloadDirectory(f, args)
+		return self.downloadDirectory(f, args, failures)
 	} else if isBinary(f) {
 		_, _, err = self.downloadBinary(f, args)
-		return err
+		return failures.record(args.Out, filepath.Join(args.Path, f.Name), err)
 	}
 
 	return nil
 }
 
+// Downloads the file and records it in the manifest, files that are skipped are not recorded
 func (self *Drive) downloadBinary(f *drive.File, args DownloadArgs) (int64, int64, error) {
+	if name := localFileName(f.Name); name != f.Name {
+		renamed := *f
+		renamed.Name = name
+		f = &renamed
+	}
+
+	fpath := filepath.Join(args.Path, f.Name)
+	skipped := args.Skip && fileExists(fpath)
+
+	bytes, rate, err := self.transferBinary(f, args)
+	if err != nil || skipped || args.Stdout {
+		return bytes, rate, err
+	}
+
+	return bytes, rate, args.manifest.record(fpath, f.Md5Checksum)
+}
+
+func (self *Drive) transferBinary(f *drive.File, args DownloadArgs) (int64, int64, error) {
+	if args.Resume && !args.Stdout {
+		return self.downloadBinaryResumable(f, args)
+	}
+
 	// Get timeout reader wrapper and context
 	timeoutReaderWrapper, ctx := getTimeoutReaderWrapperContext(args.Timeout)
 
@@ -132,7 +193,10 @@ func (self *Drive) downloadBinary(f *drive.File, args DownloadArgs) (int64, int6
 		if isTimeoutError(err) {
 			return 0, 0, fmt.Errorf("Failed to download file: timeout, no data was transferred for %v", args.Timeout)
 		}
-		return 0, 0, fmt.Errorf("Failed to download file: %s", err)
+		if isRequestTimeoutError(err) {
+			return 0, 0, fmt.Errorf("Failed to download file: timeout, no response within the http timeout")
+		}
+		return 0, 0, wrapError("Failed to download file", err)
 	}
 
 	// Close body on function exit
@@ -154,9 +218,27 @@ func (self *Drive) downloadBinary(f *drive.File, args DownloadArgs) (int64, int6
 		skip:          args.Skip,
 		stdout:        args.Stdout,
 		progress:      args.Progress,
+		modifiedTime:  modifiedTime(f, args.PreserveMtime),
+		decimalUnits:  args.DecimalUnits,
 	})
 }
 
+func downloadFields(args DownloadArgs) []googleapi.Field {
+	fields := []googleapi.Field{"id", "name", "size", "mimeType", "md5Checksum"}
+	if args.PreserveMtime {
+		fields = append(fields, "modifiedTime")
+	}
+	return fields
+}
+
+// Returns the modified time to set on the downloaded file, if any
+func modifiedTime(f *drive.File, preserve bool) string {
+	if preserve {
+		return f.ModifiedTime
+	}
+	return ""
+}
+
 type saveFileArgs struct {
 	out           io.Writer
 	body          io.Reader
@@ -166,11 +248,13 @@ type saveFileArgs struct {
 	skip          bool
 	stdout        bool
 	progress      io.Writer
+	modifiedTime  string
+	decimalUnits  bool
 }
 
 func (self *Drive) saveFile(args saveFileArgs) (int64, int64, error) {
 	// Wrap response body in progress reader
-	srcReader := getProgressReader(args.body, args.progress, args.contentLength)
+	srcReader := getProgressReader(args.body, args.progress, args.contentLength, args.decimalUnits)
 
 	if args.stdout {
 		// Write file content to stdout
@@ -210,7 +294,7 @@ func (self *Drive) saveFile(args saveFileArgs) (int64, int64, error) {
 	if err != nil {
 		outFile.Close()
 		os.Remove(tmpPath)
-		return 0, 0, fmt.Errorf("Failed saving file: %s", err)
+		return 0, 0, wrapError("Failed saving file", err)
 	}
 
 	// Calculate average download rate
@@ -220,20 +304,58 @@ func (self *Drive) saveFile(args saveFileArgs) (int64, int64, error) {
 	outFile.Close()
 
 	// Rename tmp file to proper filename
-	return bytes, rate, os.Rename(tmpPath, args.fpath)
+	if err := os.Rename(tmpPath, args.fpath); err != nil {
+		return 0, 0, err
+	}
+
+	return bytes, rate, setModifiedTime(args.fpath, args.modifiedTime)
+}
+
+// Set local modified time to the given time, an empty time leaves the file untouched
+func setModifiedTime(path, modifiedTime string) error {
+	if modifiedTime == "" {
+		return nil
+	}
+
+	t, err := time.Parse(time.RFC3339, modifiedTime)
+	if err != nil {
+		return wrapError("Failed to parse modified time", err)
+	}
+
+	if err := os.Chtimes(path, t, t); err != nil {
+		return wrapError("Failed to set modified time", err)
+	}
+	return nil
 }
 
-func (self *Drive) downloadDirectory(parent *drive.File, args DownloadArgs) error {
+// Remote names are used as a single path element locally, so path separators
+// are replaced and the names . and .. are not used as is
+func localFileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r == '/' || r == filepath.Separator {
+			return '_'
+		}
+		return r
+	}, name)
+
+	switch name {
+	case "", ".", "..":
+		return "_"
+	}
+	return name
+}
+
+func (self *Drive) downloadDirectory(parent *drive.File, args DownloadArgs, failures *failureSummary) error {
 	listArgs := listAllFilesArgs{
 		query:  fmt.Sprintf("'%s' in parents", parent.Id),
 		fields: []googleapi.Field{"nextPageToken", "files(id,name)"},
 	}
 	files, err := self.listAllFiles(listArgs)
 	if err != nil {
-		return fmt.Errorf("Failed listing files: %s", err)
+		return failures.record(args.Out, filepath.Join(args.Path, parent.Name), wrapError("Failed listing files", err))
 	}
 
-	newPath := filepath.Join(args.Path, parent.Name)
+	newPath := filepath.Join(args.Path, localFileName(parent.Name))
 
 	for _, f := range files {
 		// Copy args and update changed fields
@@ -242,7 +364,7 @@ func (self *Drive) downloadDirectory(parent *drive.File, args DownloadArgs) erro
 		newArgs.Id = f.Id
 		newArgs.Stdout = false
 
-		err = self.downloadRecursive(newArgs)
+		err = self.downloadRecursive(newArgs, failures)
 		if err != nil {
 			return err
 		}
@@ -251,10 +373,23 @@ func (self *Drive) downloadDirectory(parent *drive.File, args DownloadArgs) erro
 	return nil
 }
 
+const googleAppsMimePrefix = "application/vnd.google-apps."
+
 func isDir(f *drive.File) bool {
 	return f.MimeType == DirectoryMimeType
 }
 
+// Files with the folder mime type are directories, files with any other google
+// apps mime type (application/vnd.google-apps.*) are documents that have no content
+// of their own and must be exported. Everything else is binary, including files with
+// an empty mime type or application/octet-stream. Only binary files have a md5
+// checksum, so files with a checksum are binary regardless of the mime type
 func isBinary(f *drive.File) bool {
-	return f.Md5Checksum != ""
+	switch {
+	case f.Md5Checksum != "":
+		return true
+	case f.MimeType == "", f.MimeType == "application/octet-stream":
+		return true
+	}
+	return !isDir(f) && !strings.HasPrefix(f.MimeType, googleAppsMimePrefix)
 }
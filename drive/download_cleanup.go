@@ -0,0 +1,174 @@
+package drive
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type CleanupDownloadsArgs struct {
+	Out      io.Writer
+	Progress io.Writer
+	Path     string
+	// Resume partial downloads of remote files that have not changed
+	Resume bool
+	// Remove partial downloads that are not resumed
+	Delete        bool
+	PreserveMtime bool
+	Timeout       time.Duration
+	DecimalUnits  bool
+}
+
+// Finds partial downloads left behind by interrupted resumable downloads in the directory.
+// Each partial download is reported, and resumed or removed depending on the args
+func (self *Drive) CleanupDownloads(args CleanupDownloadsArgs) error {
+	partPaths, err := findPartialDownloads(args.Path)
+	if err != nil {
+		return err
+	}
+
+	if len(partPaths) == 0 {
+		fmt.Fprintf(args.Out, "No partial downloads found in %s\n", args.Path)
+		return nil
+	}
+
+	resumed := 0
+	removed := 0
+	failed := 0
+
+	for _, partPath := range partPaths {
+		fpath := strings.TrimSuffix(partPath, PartialFileSuffix)
+
+		status, resumable := self.partialDownloadStatus(partPath, fpath+PartialMetaSuffix, args.DecimalUnits)
+		fmt.Fprintf(args.Out, "%s: %s\n", partPath, status)
+
+		if resumable && args.Resume {
+			if err := self.resumePartialDownload(fpath, args); err != nil {
+				fmt.Fprintf(args.Out, "Failed to resume %s: %s\n", partPath, err)
+				failed++
+				continue
+			}
+			resumed++
+			continue
+		}
+
+		if args.Delete {
+			if err := removePartialDownload(fpath); err != nil {
+				fmt.Fprintf(args.Out, "Failed to remove %s: %s\n", partPath, err)
+				failed++
+				continue
+			}
+			fmt.Fprintf(args.Out, "Removed %s\n", partPath)
+			removed++
+		}
+	}
+
+	fmt.Fprintf(args.Out, "Found %d partial downloads: %d resumed, %d removed, %d kept\n", len(partPaths), resumed, removed, len(partPaths)-resumed-removed)
+
+	if failed > 0 {
+		return fmt.Errorf("Failed to clean up %d of %d partial downloads", failed, len(partPaths))
+	}
+
+	return nil
+}
+
+// Returns a description of the partial download and
+// true if it can be resumed, i.e. the remote file has not changed
+func (self *Drive) partialDownloadStatus(partPath, metaPath string, decimal bool) (string, bool) {
+	if !fileExists(partPath) {
+		return "partial file is missing, only the download metadata was found", false
+	}
+
+	meta, err := readPartialMeta(metaPath)
+	if err != nil {
+		return "download metadata is missing", false
+	}
+
+	f, err := self.service.Files.Get(meta.Id).Fields("id", "name", "size", "md5Checksum").Do()
+	if err != nil {
+		if IsNotFound(err) {
+			return fmt.Sprintf("remote file %s no longer exists", meta.Id), false
+		}
+		return fmt.Sprintf("failed to get remote file %s: %s", meta.Id, err), false
+	}
+
+	offset, ok := partialOffset(f, partPath, metaPath)
+	if !ok {
+		return fmt.Sprintf("remote file %s has changed", meta.Id), false
+	}
+
+	if fileExists(strings.TrimSuffix(partPath, PartialFileSuffix)) {
+		return "the downloaded file already exists", false
+	}
+
+	return fmt.Sprintf("resumable, %s of %s downloaded", formatUsage(offset, false, decimal), formatUsage(f.Size, false, decimal)), true
+}
+
+func (self *Drive) resumePartialDownload(fpath string, args CleanupDownloadsArgs) error {
+	meta, err := readPartialMeta(fpath + PartialMetaSuffix)
+	if err != nil {
+		return err
+	}
+
+	downloadArgs := DownloadArgs{
+		Out:           args.Out,
+		Progress:      args.Progress,
+		PreserveMtime: args.PreserveMtime,
+		Timeout:       args.Timeout,
+		DecimalUnits:  args.DecimalUnits,
+	}
+
+	f, err := self.service.Files.Get(meta.Id).Fields(downloadFields(downloadArgs)...).Do()
+	if err != nil {
+		return wrapError("Failed to get file", err)
+	}
+
+	fmt.Fprintf(args.Out, "Resuming %s -> %s\n", f.Name, fpath)
+
+	_, err = self.resumeDownload(f, fpath, downloadArgs, 0)
+	return err
+}
+
+// Returns the partial files in the directory and its subdirectories,
+// metadata files without a partial file are included by the missing partial file path
+func findPartialDownloads(root string) ([]string, error) {
+	var partPaths []string
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		if strings.HasSuffix(path, PartialFileSuffix) {
+			partPaths = append(partPaths, path)
+		} else if strings.HasSuffix(path, PartialMetaSuffix) {
+			partPath := strings.TrimSuffix(path, PartialMetaSuffix) + PartialFileSuffix
+			if !fileExists(partPath) {
+				partPaths = append(partPaths, partPath)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, wrapError("Failed to find partial downloads", err)
+	}
+
+	return partPaths, nil
+}
+
+func removePartialDownload(fpath string) error {
+	for _, path := range []string{fpath + PartialFileSuffix, fpath + PartialMetaSuffix} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
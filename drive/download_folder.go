@@ -0,0 +1,432 @@
+package drive
+
+import (
+	"fmt"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type DownloadFolderArgs struct {
+	Out           io.Writer
+	Progress      io.Writer
+	Id            string
+	Path          string
+	Force         bool
+	Skip          bool
+	Mime          []string
+	ExcludeMime   []string
+	PreserveMtime bool
+	Flatten       bool
+	Timeout       time.Duration
+	// Only download files modified after the time, a date or RFC 3339 timestamp
+	Since string
+	// The since time is read from the file if not given, and the newest
+	// modified time of the listed files is written to it after downloading
+	SinceFile string
+	// Download directories containing a single directory and no files into
+	// one directory, named by the names of the chain joined with _. The same
+	// directories are collapsed with and without Since
+	FlattenSingleChild bool
+	// Number of files transferred at the same time, progress is only shown for 1
+	Concurrency  int
+	DecimalUnits bool
+}
+
+type downloadFolderSummary struct {
+	files    int
+	bytes    int64
+	skipped  int
+	filtered int
+	renamed  int
+	// Filenames used when flattening
+	names map[string]bool
+	// Newest modified time of the listed files
+	newest time.Time
+	// Files found while walking the folder, transferred after the walk
+	transfers []folderTransfer
+}
+
+// A file to download into the directory, documents are exported with the export mime
+type folderTransfer struct {
+	file       *drive.File
+	dirPath    string
+	filename   string
+	exportMime string
+}
+
+func (self *Drive) DownloadFolder(args DownloadFolderArgs) error {
+	f, err := self.service.Files.Get(args.Id).Fields("id", "name", "mimeType").Do()
+	if err != nil {
+		return wrapError("Failed to get file", err)
+	}
+
+	if !isDir(f) {
+		return fmt.Errorf("'%s' is not a directory, use 'download' to download files", f.Name)
+	}
+
+	if args.Since == "" && args.SinceFile != "" && fileExists(args.SinceFile) {
+		content, err := ioutil.ReadFile(args.SinceFile)
+		if err != nil {
+			return fmt.Errorf("Failed to read since file: %s", err)
+		}
+		args.Since = strings.TrimSpace(string(content))
+	}
+
+	if args.Since != "" {
+		args.Since, err = parseQueryTime(args.Since)
+		if err != nil {
+			return err
+		}
+	}
+
+	summary := &downloadFolderSummary{names: map[string]bool{}}
+	started := time.Now()
+
+	err = self.downloadFolder(f, args.Path, args, summary)
+	if err != nil {
+		return err
+	}
+
+	err = self.transferFolderFiles(args, summary)
+	if err != nil {
+		return err
+	}
+
+	rate := calcRate(summary.bytes, started, time.Now())
+	fmt.Fprintf(args.Out, "Downloaded %d files at %s/s, total %s\n", summary.files, formatSize(rate, false, args.DecimalUnits), formatSize(summary.bytes, false, args.DecimalUnits))
+
+	if summary.skipped > 0 {
+		fmt.Fprintf(args.Out, "Skipped %d files that could not be downloaded or exported\n", summary.skipped)
+	}
+
+	if summary.filtered > 0 {
+		fmt.Fprintf(args.Out, "Skipped %d files not matching the mime filter\n", summary.filtered)
+	}
+
+	if summary.renamed > 0 {
+		fmt.Fprintf(args.Out, "Renamed %d files to avoid name collisions\n", summary.renamed)
+	}
+
+	if args.Since == "" && args.SinceFile == "" {
+		return nil
+	}
+
+	// Nothing changed since the last run, the same time is used next time
+	newest := args.Since
+	if !summary.newest.IsZero() {
+		newest = summary.newest.UTC().Format(time.RFC3339Nano)
+	}
+	if newest == "" {
+		return nil
+	}
+
+	fmt.Fprintf(args.Out, "Newest modified time: %s, use it as --since on the next run\n", newest)
+
+	if args.SinceFile != "" {
+		if err := ioutil.WriteFile(args.SinceFile, []byte(newest+"\n"), 0600); err != nil {
+			return fmt.Errorf("Failed to write since file: %s", err)
+		}
+	}
+
+	return nil
+}
+
+func (self *Drive) downloadFolder(parent *drive.File, path string, args DownloadFolderArgs, summary *downloadFolderSummary) error {
+	query := fmt.Sprintf("'%s' in parents and trashed = false", parent.Id)
+
+	// Unchanged files are needed to know if the directory can be collapsed,
+	// so they are filtered after listing when collapsing directories
+	filterSince := args.Since != "" && args.FlattenSingleChild && !args.Flatten
+
+	// Directories are always listed to find changes below them
+	if args.Since != "" && !filterSince {
+		query += fmt.Sprintf(" and (mimeType = '%s' or modifiedTime > '%s')", DirectoryMimeType, args.Since)
+	}
+
+	listArgs := listAllFilesArgs{
+		query:  query,
+		fields: []googleapi.Field{"nextPageToken", "files(id,name,mimeType,size,md5Checksum,modifiedTime)"},
+	}
+	files, err := self.listAllFiles(listArgs)
+	if err != nil {
+		return wrapError("Failed listing files", err)
+	}
+
+	if args.FlattenSingleChild && !args.Flatten && len(files) == 1 && isDir(files[0]) {
+		collapsed := *files[0]
+		collapsed.Name = localSingleChildName(parent.Name, collapsed.Name)
+		return self.downloadFolder(&collapsed, path, args, summary)
+	}
+
+	dirPath := filepath.Join(path, localFileName(parent.Name))
+
+	// All files are saved directly in the download path when flattening
+	if args.Flatten {
+		dirPath = args.Path
+	} else if err := os.MkdirAll(dirPath, 0775); err != nil {
+		// Empty remote directories are also created locally
+		return wrapError("Failed to create directory", err)
+	}
+
+	for _, f := range files {
+		if isDir(f) {
+			err = self.downloadFolder(f, dirPath, args, summary)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if filterSince && !modifiedAfter(f.ModifiedTime, args.Since) {
+			continue
+		}
+
+		summary.addModified(f.ModifiedTime)
+
+		// Directories are always traversed, so only files are filtered
+		if !matchesMimeFilter(f.MimeType, args.Mime, args.ExcludeMime) {
+			summary.filtered++
+			continue
+		}
+
+		if isBinary(f) {
+			filename := localFileName(f.Name)
+			if args.Flatten {
+				filename = summary.flatName(filename, args.Out)
+			}
+
+			summary.transfers = append(summary.transfers, folderTransfer{file: f, dirPath: dirPath, filename: filename})
+		} else {
+			if err := unexportableError(f); err != nil {
+				fmt.Fprintf(args.Out, "Skipping file: %s\n", err)
+				summary.skipped++
+				continue
+			}
+
+			exportMime, ok := DefaultExportMime[f.MimeType]
+			if !ok {
+				fmt.Fprintf(args.Out, "Skipping %s, files with type '%s' cannot be exported\n", f.Name, f.MimeType)
+				summary.skipped++
+				continue
+			}
+
+			filename, err := getExportFilename(localFileName(f.Name), exportMime)
+			if err != nil {
+				return err
+			}
+
+			if args.Flatten {
+				filename = summary.flatName(filename, args.Out)
+			}
+
+			summary.transfers = append(summary.transfers, folderTransfer{file: f, dirPath: dirPath, filename: filename, exportMime: exportMime})
+		}
+	}
+
+	return nil
+}
+
+// Transfers the files found while walking the folder, at most Concurrency at
+// a time. Binary files are downloaded like with download --recursive. No new
+// transfers are started after one failed, the first error is returned
+func (self *Drive) transferFolderFiles(args DownloadFolderArgs, summary *downloadFolderSummary) error {
+	concurrency := args.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	// The progress of files transferred at the same time would be drawn over each other
+	progress := args.Progress
+	if concurrency > 1 {
+		progress = ioutil.Discard
+		args.Out = &lockedWriter{writer: args.Out}
+	}
+
+	transfers := summary.transfers
+	bytes := make([]int64, len(transfers))
+	errs := make([]error, len(transfers))
+	sem := make(chan struct{}, concurrency)
+	wg := &sync.WaitGroup{}
+	var failed int32
+
+	for i, t := range transfers {
+		sem <- struct{}{}
+		if atomic.LoadInt32(&failed) != 0 {
+			<-sem
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, t folderTransfer) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			bytes[i], errs[i] = self.transferFolderFile(t, args, progress)
+			if errs[i] != nil {
+				atomic.StoreInt32(&failed, 1)
+			}
+		}(i, t)
+	}
+
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return err
+		}
+		summary.files++
+		summary.bytes += bytes[i]
+	}
+
+	return nil
+}
+
+// Serializes the messages written by the download workers
+type lockedWriter struct {
+	mutex  sync.Mutex
+	writer io.Writer
+}
+
+func (self *lockedWriter) Write(p []byte) (int, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	return self.writer.Write(p)
+}
+
+func (self *Drive) transferFolderFile(t folderTransfer, args DownloadFolderArgs, progress io.Writer) (int64, error) {
+	if t.exportMime != "" {
+		return self.exportFile(t.file, t.exportMime, filepath.Join(t.dirPath, t.filename), args, progress)
+	}
+
+	renamed := *t.file
+	renamed.Name = t.filename
+
+	bytes, _, err := self.downloadBinary(&renamed, DownloadArgs{
+		Out:           args.Out,
+		Progress:      progress,
+		Path:          t.dirPath,
+		Force:         args.Force,
+		Skip:          args.Skip,
+		PreserveMtime: args.PreserveMtime,
+		Timeout:       args.Timeout,
+		DecimalUnits:  args.DecimalUnits,
+	})
+	return bytes, err
+}
+
+// Local directory name of a collapsed directory chain. The names are joined
+// with _ instead of the / shown by tree, which would nest the directories again
+func localSingleChildName(parent, child string) string {
+	return localFileName(parent) + "_" + localFileName(child)
+}
+
+func (self *Drive) exportFile(f *drive.File, exportMime, fpath string, args DownloadFolderArgs, progress io.Writer) (int64, error) {
+	// Get timeout reader wrapper and context
+	timeoutReaderWrapper, ctx := getTimeoutReaderWrapperContext(args.Timeout)
+
+	res, err := self.service.Files.Export(f.Id, exportMime).Context(ctx).Download()
+	if err != nil {
+		if isTimeoutError(err) {
+			return 0, fmt.Errorf("Failed to export file: timeout, no data was transferred for %v", args.Timeout)
+		}
+		if isRequestTimeoutError(err) {
+			return 0, fmt.Errorf("Failed to export file: timeout, no response within the http timeout")
+		}
+		return 0, wrapError("Failed to export file", err)
+	}
+
+	// Close body on function exit
+	defer res.Body.Close()
+
+	fmt.Fprintf(args.Out, "Exporting %s -> %s\n", f.Name, fpath)
+
+	bytes, _, err := self.saveFile(saveFileArgs{
+		out:           args.Out,
+		body:          timeoutReaderWrapper(res.Body),
+		contentLength: res.ContentLength,
+		fpath:         fpath,
+		force:         args.Force,
+		skip:          args.Skip,
+		progress:      progress,
+		modifiedTime:  modifiedTime(f, args.PreserveMtime),
+		decimalUnits:  args.DecimalUnits,
+	})
+
+	return bytes, err
+}
+
+// Compares like the modifiedTime > since query, both times are RFC 3339
+func modifiedAfter(modified, since string) bool {
+	m, err := time.Parse(time.RFC3339, modified)
+	if err != nil {
+		return true
+	}
+	t, err := time.Parse(time.RFC3339, since)
+	return err != nil || m.After(t)
+}
+
+func (self *downloadFolderSummary) addModified(modified string) {
+	t, err := time.Parse(time.RFC3339, modified)
+	if err == nil && t.After(self.newest) {
+		self.newest = t
+	}
+}
+
+// Returns a filename not used by any other file when flattening,
+// collisions are resolved by appending (2), (3), etc. to the name
+func (self *downloadFolderSummary) flatName(name string, out io.Writer) string {
+	unique := name
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+
+	for i := 2; self.names[unique]; i++ {
+		unique = fmt.Sprintf("%s (%d)%s", base, i, ext)
+	}
+
+	if unique != name {
+		fmt.Fprintf(out, "Renaming %s to %s to avoid name collision\n", name, unique)
+		self.renamed++
+	}
+
+	self.names[unique] = true
+	return unique
+}
+
+// Checks that the mime type matches one of the included mime types, if any,
+// and none of the excluded. Mime types ending with / or /* are matched as prefix,
+// i.e. image/ matches all images
+func matchesMimeFilter(mimeType string, include, exclude []string) bool {
+	for _, m := range exclude {
+		if matchesMime(mimeType, m) {
+			return false
+		}
+	}
+
+	if len(include) == 0 {
+		return true
+	}
+
+	for _, m := range include {
+		if matchesMime(mimeType, m) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchesMime(mimeType, pattern string) bool {
+	pattern = strings.TrimSuffix(pattern, "*")
+	if strings.HasSuffix(pattern, "/") {
+		return strings.HasPrefix(mimeType, pattern)
+	}
+	return mimeType == pattern
+}
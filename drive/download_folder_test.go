@@ -0,0 +1,69 @@
+package drive
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func singleChildFiles() []*fakeFile {
+	return []*fakeFile{
+		fakeFolder("top", "Top"),
+		fakeFolder("a", "a", "top"),
+		fakeBinary("fa", "fa", "1", "a"),
+		fakeFolder("b", "b/x", "a"),
+		fakeFolder("c", "c", "b"),
+		fakeBinary("fc", "fc", "2", "c"),
+	}
+}
+
+// Incremental downloads must collapse the same directories, a is not collapsed
+// even if its file is unchanged. No file is modified after the since time.
+// Every directory is listed once
+func TestDownloadFolderFlattenSingleChild(t *testing.T) {
+	for _, since := range []string{"", "2030-01-01"} {
+		dir, err := ioutil.TempDir("", "gdrive")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		d, fake := newFakeDrive(t, singleChildFiles()...)
+		err = d.DownloadFolder(DownloadFolderArgs{
+			Out:                &bytes.Buffer{},
+			Progress:           ioutil.Discard,
+			Id:                 "top",
+			Path:               dir,
+			Since:              since,
+			FlattenSingleChild: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, path := range []string{"Top_a", "Top_a/b_x_c"} {
+			if fi, err := os.Stat(filepath.Join(dir, path)); err != nil || !fi.IsDir() {
+				t.Errorf("since %q: expected directory %s", since, path)
+			}
+		}
+		if _, err := os.Stat(filepath.Join(dir, "Top")); err == nil {
+			t.Errorf("since %q: Top was not collapsed", since)
+		}
+
+		if n := len(fake.requestsTo("files")); n != 4 {
+			t.Errorf("since %q: got %d listings, want 4", since, n)
+		}
+
+		for _, path := range []string{"Top_a/fa", "Top_a/b_x_c/fc"} {
+			_, err := os.Stat(filepath.Join(dir, path))
+			if since == "" && err != nil {
+				t.Errorf("expected file %s", path)
+			}
+			if since != "" && err == nil {
+				t.Errorf("since %q: unchanged file %s was downloaded", since, path)
+			}
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package drive
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+var manifestHeader = []string{"path", "size", "md5", "sha256"}
+
+// Records the size and checksums of downloaded files in a csv file, so the files
+// can be verified later without drive. A nil manifest records nothing
+type downloadManifest struct {
+	mutex  sync.Mutex
+	file   *os.File
+	writer *csv.Writer
+}
+
+func newDownloadManifest(path string) (*downloadManifest, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to create manifest '%s': %s", path, err)
+	}
+
+	manifest := &downloadManifest{
+		file:   f,
+		writer: csv.NewWriter(f),
+	}
+	manifest.writer.Write(manifestHeader)
+	manifest.writer.Flush()
+	return manifest, manifest.writer.Error()
+}
+
+// Adds the local file to the manifest. The local md5 is compared with the remote
+// md5 if given, a mismatch is returned as an error and the file is not recorded
+func (self *downloadManifest) record(fpath, remoteMd5 string) error {
+	if self == nil {
+		return nil
+	}
+
+	size, md5sum, sha256sum, err := fileChecksums(fpath)
+	if err != nil {
+		return fmt.Errorf("Failed to compute checksums of '%s': %s", fpath, err)
+	}
+
+	if remoteMd5 != "" && md5sum != remoteMd5 {
+		return fmt.Errorf("Checksum mismatch, local md5 %s, remote md5 %s", md5sum, remoteMd5)
+	}
+
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	self.writer.Write([]string{fpath, strconv.FormatInt(size, 10), md5sum, sha256sum})
+
+	// Flush every record so the manifest is complete up to any interruption
+	self.writer.Flush()
+	return self.writer.Error()
+}
+
+func (self *downloadManifest) close() error {
+	if self == nil {
+		return nil
+	}
+	return self.file.Close()
+}
+
+// Both checksums are computed in a single read of the file
+func fileChecksums(fpath string) (int64, string, string, error) {
+	f, err := os.Open(fpath)
+	if err != nil {
+		return 0, "", "", err
+	}
+	defer f.Close()
+
+	md5Hash := md5.New()
+	sha256Hash := sha256.New()
+
+	size, err := io.Copy(io.MultiWriter(md5Hash, sha256Hash), f)
+	if err != nil {
+		return 0, "", "", err
+	}
+
+	return size, fmt.Sprintf("%x", md5Hash.Sum(nil)), fmt.Sprintf("%x", sha256Hash.Sum(nil)), nil
+}
+
+type VerifyManifestArgs struct {
+	Out  io.Writer
+	Path string
+	// Checksum to compare, md5 or sha256
+	Checksum string
+}
+
+// Compares the local files with the size and checksum recorded in the manifest,
+// an error is returned if any of the files are missing or differ
+func VerifyManifest(args VerifyManifestArgs) error {
+	column := 3
+	switch args.Checksum {
+	case "md5":
+		column = 2
+	case "", "sha256":
+	default:
+		return fmt.Errorf("Invalid checksum '%s', must be md5 or sha256", args.Checksum)
+	}
+
+	f, err := os.Open(args.Path)
+	if err != nil {
+		return fmt.Errorf("Unable to open manifest '%s': %s", args.Path, err)
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = len(manifestHeader)
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return fmt.Errorf("Invalid manifest '%s': %s", args.Path, err)
+	}
+
+	var discrepancies []string
+	checked := 0
+
+	for i, record := range records {
+		if i == 0 && record[0] == manifestHeader[0] {
+			continue
+		}
+		checked++
+
+		fpath := record[0]
+		if !fileExists(fpath) {
+			discrepancies = append(discrepancies, fmt.Sprintf("Missing: %s", fpath))
+			continue
+		}
+
+		size, md5sum, sha256sum, err := fileChecksums(fpath)
+		if err != nil {
+			discrepancies = append(discrepancies, fmt.Sprintf("Unreadable: %s: %s", fpath, err))
+			continue
+		}
+
+		checksums := []string{fpath, strconv.FormatInt(size, 10), md5sum, sha256sum}
+		if checksums[1] != record[1] || checksums[column] != record[column] {
+			discrepancies = append(discrepancies, fmt.Sprintf("Differs: %s", fpath))
+		}
+	}
+
+	if len(discrepancies) == 0 {
+		fmt.Fprintf(args.Out, "All %d files match\n", checked)
+		return nil
+	}
+
+	sort.Strings(discrepancies)
+	for _, d := range discrepancies {
+		fmt.Fprintln(args.Out, d)
+	}
+
+	return fmt.Errorf("Found %d discrepancies", len(discrepancies))
+}
@@ -0,0 +1,258 @@
+package drive
+
+import (
+	"encoding/json"
+	"fmt"
+	"golang.org/x/net/context"
+	"golang.org/x/net/context/ctxhttp"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const PartialFileSuffix = ".part"
+const PartialMetaSuffix = ".part.json"
+
+// Metadata about the remote file stored next to the partial file,
+// used to verify that the remote file has not changed before resuming
+type partialDownload struct {
+	Id  string `json:"id"`
+	Md5 string `json:"md5"`
+}
+
+// Downloads file to a .part file which is kept if the download is interrupted,
+// the download will continue from the end of the partial file on the next attempt
+func (self *Drive) downloadBinaryResumable(f *drive.File, args DownloadArgs) (int64, int64, error) {
+	// Path to file
+	fpath := filepath.Join(args.Path, f.Name)
+
+	// Check if file exists to force
+	if !args.Skip && !args.Force && fileExists(fpath) {
+		return 0, 0, fmt.Errorf("File '%s' already exists, use --force to overwrite or --skip to skip", fpath)
+	}
+
+	//Check if file exists to skip
+	if args.Skip && fileExists(fpath) {
+		fmt.Printf("File '%s' already exists, skipping\n", fpath)
+		return 0, 0, nil
+	}
+
+	// Ensure any parent directories exists
+	if err := mkdir(fpath); err != nil {
+		return 0, 0, err
+	}
+
+	fmt.Fprintf(args.Out, "Downloading %s -> %s\n", f.Name, fpath)
+	started := time.Now()
+
+	bytes, err := self.resumeDownload(f, fpath, args, 0)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Calculate average download rate
+	rate := calcRate(bytes, started, time.Now())
+
+	return bytes, rate, nil
+}
+
+func (self *Drive) resumeDownload(f *drive.File, fpath string, args DownloadArgs, try int) (int64, error) {
+	partPath := fpath + PartialFileSuffix
+	metaPath := fpath + PartialMetaSuffix
+
+	offset, ok := partialOffset(f, partPath, metaPath)
+	if !ok && fileExists(partPath) {
+		fmt.Fprintf(args.Out, "Remote file changed, restarting download of %s\n", f.Name)
+	}
+
+	err := writePartialMeta(metaPath, partialDownload{Id: f.Id, Md5: f.Md5Checksum})
+	if err != nil {
+		return 0, wrapError("Failed to save download metadata", err)
+	}
+
+	// Only request the remaining data if the partial file is incomplete
+	if offset < f.Size {
+		if offset > 0 {
+			fmt.Fprintf(args.Out, "Resuming download at %s\n", formatSize(offset, false, args.DecimalUnits))
+		}
+
+		// Get timeout reader wrapper and context
+		timeoutReaderWrapper, ctx := getTimeoutReaderWrapperContext(args.Timeout)
+
+		res, err := self.downloadRange(ctx, f.Id, offset)
+		if err != nil {
+			if isBackendOrRateLimitError(err) && try < MaxErrorRetries {
+				exponentialBackoffSleep(try)
+				return self.retryResumeDownload(f, fpath, args, try+1)
+			} else if isTimeoutError(err) {
+				return 0, fmt.Errorf("Failed to download file: timeout, no data was transferred for %v", args.Timeout)
+			} else if isRequestTimeoutError(err) {
+				return 0, fmt.Errorf("Failed to download file: timeout, no response within the http timeout")
+			} else {
+				return 0, wrapError("Failed to download file", err)
+			}
+		}
+
+		// Close body on function exit
+		defer res.Body.Close()
+
+		flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+
+		// Start from the beginning if the range was not honored
+		if res.StatusCode != http.StatusPartialContent {
+			flags = os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+			offset = 0
+		}
+
+		outFile, err := os.OpenFile(partPath, flags, 0666)
+		if err != nil {
+			return 0, fmt.Errorf("Unable to create new file: %s", err)
+		}
+
+		// Wrap response body in progress reader
+		body := &readErrorRecorder{reader: timeoutReaderWrapper(res.Body)}
+		srcReader := getProgressReader(body, args.Progress, res.ContentLength, args.DecimalUnits)
+
+		// Save data to disk, the partial data is kept on error
+		bytes, err := outFile.ReadFrom(srcReader)
+		outFile.Close()
+		if err != nil {
+			switch {
+			case body.err == nil:
+				// Writing to the partial file failed, retrying will not help
+				return 0, wrapError("Failed saving file", err)
+			case ctx.Err() != nil:
+				return 0, fmt.Errorf("Failed to download file: timeout, no data was transferred for %v, run the command again to resume", args.Timeout)
+			case isRetryableReadError(body.err) && try < MaxErrorRetries:
+				exponentialBackoffSleep(try)
+				return self.retryResumeDownload(f, fpath, args, try+1)
+			}
+			return 0, fmt.Errorf("Download was interrupted: %s, run the command again to resume", err)
+		}
+
+		offset += bytes
+	} else if f.Size == 0 {
+		// Nothing is downloaded for empty files, but the file must still be created
+		outFile, err := os.Create(partPath)
+		if err != nil {
+			return 0, fmt.Errorf("Unable to create new file: %s", err)
+		}
+		outFile.Close()
+	}
+
+	os.Remove(metaPath)
+
+	// Rename partial file to proper filename
+	if err := os.Rename(partPath, fpath); err != nil {
+		return 0, err
+	}
+
+	return offset, setModifiedTime(fpath, modifiedTime(f, args.PreserveMtime))
+}
+
+// Remembers the error of the response body, so that failed reads from the
+// network can be told apart from failed writes to the partial file
+type readErrorRecorder struct {
+	reader io.Reader
+	err    error
+}
+
+func (self *readErrorRecorder) Read(p []byte) (int, error) {
+	n, err := self.reader.Read(p)
+	if err != nil && err != io.EOF {
+		self.err = err
+	}
+	return n, err
+}
+
+// Backend and rate limit errors are retried like failed requests, as well
+// as connections that were dropped while reading the response body
+func isRetryableReadError(err error) bool {
+	if isBackendOrRateLimitError(err) || err == io.ErrUnexpectedEOF {
+		return true
+	}
+	_, ok := err.(net.Error)
+	return ok
+}
+
+// Metadata is fetched again before retrying,
+// so that the partial file is discarded if the remote file changed
+func (self *Drive) retryResumeDownload(f *drive.File, fpath string, args DownloadArgs, try int) (int64, error) {
+	f, err := self.service.Files.Get(f.Id).Fields(downloadFields(args)...).Do()
+	if err != nil {
+		return 0, wrapError("Failed to get file", err)
+	}
+
+	return self.resumeDownload(f, fpath, args, try)
+}
+
+// The files.get call does not support setting headers,
+// so the media request is made manually to be able to set the range
+func (self *Drive) downloadRange(ctx context.Context, id string, offset int64) (*http.Response, error) {
+	urls := googleapi.ResolveRelative(self.service.BasePath, "files/{fileId}") + "?alt=media"
+	req, _ := http.NewRequest("GET", urls, nil)
+	googleapi.Expand(req.URL, map[string]string{
+		"fileId": id,
+	})
+
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	res, err := ctxhttp.Do(ctx, self.client, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := googleapi.CheckResponse(res); err != nil {
+		res.Body.Close()
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Returns the size of the partial file and true if the partial file belongs
+// to the same version of the remote file, otherwise the download must start over
+func partialOffset(f *drive.File, partPath, metaPath string) (int64, bool) {
+	info, err := os.Stat(partPath)
+	if err != nil {
+		return 0, false
+	}
+
+	meta, err := readPartialMeta(metaPath)
+	if err != nil {
+		return 0, false
+	}
+
+	if meta.Id != f.Id || meta.Md5 != f.Md5Checksum || info.Size() > f.Size {
+		return 0, false
+	}
+
+	return info.Size(), true
+}
+
+func readPartialMeta(path string) (partialDownload, error) {
+	meta := partialDownload{}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return meta, err
+	}
+
+	return meta, json.Unmarshal(content, &meta)
+}
+
+func writePartialMeta(path string, meta partialDownload) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,111 @@
+package drive
+
+import (
+	"bytes"
+	"google.golang.org/api/drive/v3"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIsBinary(t *testing.T) {
+	cases := []struct {
+		name   string
+		file   drive.File
+		binary bool
+	}{
+		{"no mime type", drive.File{}, true},
+		{"checksum without mime type", drive.File{Md5Checksum: "abc"}, true},
+		{"octet stream", drive.File{MimeType: "application/octet-stream"}, true},
+		{"pdf", drive.File{MimeType: "application/pdf"}, true},
+		{"pdf with checksum", drive.File{MimeType: "application/pdf", Md5Checksum: "abc"}, true},
+		{"folder", drive.File{MimeType: DirectoryMimeType}, false},
+		{"document", drive.File{MimeType: "application/vnd.google-apps.document"}, false},
+		{"spreadsheet", drive.File{MimeType: "application/vnd.google-apps.spreadsheet"}, false},
+		{"shortcut", drive.File{MimeType: "application/vnd.google-apps.shortcut"}, false},
+		{"google apps mime type with checksum", drive.File{MimeType: "application/vnd.google-apps.unknown", Md5Checksum: "abc"}, true},
+	}
+
+	for _, c := range cases {
+		if binary := isBinary(&c.file); binary != c.binary {
+			t.Errorf("%s: got %v, want %v", c.name, binary, c.binary)
+		}
+	}
+}
+
+// Files without any export are only skipped with --continue-on-error
+func TestDownloadRecursiveUnexportable(t *testing.T) {
+	for _, continueOnError := range []bool{false, true} {
+		dir, err := ioutil.TempDir("", "gdrive")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		site := fakeFolder("site", "Site", "top")
+		site.MimeType = "application/vnd.google-apps.site"
+
+		d, _ := newFakeDrive(t, fakeFolder("top", "Top"), site, fakeBinary("f1", "f1", "1", "top"))
+
+		out := &bytes.Buffer{}
+		err = d.Download(DownloadArgs{
+			Out:             out,
+			Progress:        ioutil.Discard,
+			Id:              "top",
+			Path:            dir,
+			Recursive:       true,
+			ContinueOnError: continueOnError,
+		})
+
+		if !continueOnError {
+			if err == nil || !strings.Contains(err.Error(), "is a Google Site (application/vnd.google-apps.site)") {
+				t.Errorf("got error %v, want the site to fail the download", err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(out.String(), "Skipping "+filepath.Join(dir, "Top", "Site")) {
+			t.Errorf("the site was not skipped: %q", out.String())
+		}
+		if _, err := os.Stat(filepath.Join(dir, "Top", "f1")); err != nil {
+			t.Errorf("f1 was not downloaded")
+		}
+	}
+}
+
+// Forms are exported as zip like other google documents
+func TestDownloadFolderExportsForms(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gdrive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	form := fakeFolder("form", "Survey", "top")
+	form.MimeType = "application/vnd.google-apps.form"
+
+	d, _ := newFakeDrive(t, fakeFolder("top", "Top"), form)
+
+	err = d.DownloadFolder(DownloadFolderArgs{
+		Out:      &bytes.Buffer{},
+		Progress: ioutil.Discard,
+		Id:       "top",
+		Path:     dir,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "Top", "Survey.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "Survey exported as application/zip" {
+		t.Errorf("got %q, want the form exported as zip", content)
+	}
+}
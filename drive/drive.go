@@ -7,13 +7,23 @@ import (
 
 type Drive struct {
 	service *drive.Service
+	client  *http.Client
 }
 
+// Returns a Drive using the given authenticated client for all requests,
+// the client can be created with any oauth2 token source
 func New(client *http.Client) (*Drive, error) {
 	service, err := drive.New(client)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Drive{service}, nil
+	return NewWithService(service, client), nil
+}
+
+// Returns a Drive using an existing service, i.e. with a custom base path.
+// The client must be the authenticated client used by the service, it is
+// used for the requests that are not supported by the client library
+func NewWithService(service *drive.Service, client *http.Client) *Drive {
+	return &Drive{service, client}
 }
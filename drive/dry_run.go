@@ -0,0 +1,20 @@
+package drive
+
+import (
+	"fmt"
+	"io"
+)
+
+// Mutating commands print what they would do instead when DryRun is set,
+// after all paths and ids are resolved so a dry run still catches bad input
+func printDryRun(out io.Writer, format string, a ...interface{}) {
+	fmt.Fprintf(out, "Dry run, would "+format+"\n", a...)
+}
+
+// Files without parents are created in the root directory
+func dryRunParents(parents []string) string {
+	if len(parents) == 0 {
+		return "root"
+	}
+	return formatList(parents)
+}
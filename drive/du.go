@@ -0,0 +1,116 @@
+package drive
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+)
+
+type DiskUsageArgs struct {
+	Out          io.Writer
+	Id           string
+	MaxDepth     int
+	SizeInBytes  bool
+	DecimalUnits bool
+}
+
+type diskUsage struct {
+	path string
+	size int64
+}
+
+// Prints the total size of all files in the directory. With a max depth above 0
+// the size of each descendant down to the max depth is printed first, largest first.
+// Google documents do not use any quota and are counted as 0
+func (self *Drive) DiskUsage(args DiskUsageArgs) error {
+	f, err := self.service.Files.Get(args.Id).Fields("id", "name", "mimeType", "size", "md5Checksum").Do()
+	if err != nil {
+		return wrapError("Failed to get file", err)
+	}
+
+	if !isDir(f) {
+		return fmt.Errorf("'%s' is not a directory", f.Name)
+	}
+
+	walker := &treeWalker{
+		drive:   self,
+		sem:     make(chan struct{}, maxConcurrentTreeListings),
+		workers: make(chan struct{}, maxConcurrentTreeListings),
+	}
+
+	root, err := walker.walk(f, f.Name, 1)
+	if err != nil {
+		return err
+	}
+
+	var usages []diskUsage
+	total := sumFileSizes(collectDiskUsage(root, root.Name, 0, args.MaxDepth, &usages))
+
+	sort.Stable(bySizeDesc(usages))
+
+	w := new(tabwriter.Writer)
+	w.Init(args.Out, 0, 0, 3, ' ', 0)
+
+	for _, u := range usages {
+		fmt.Fprintf(w, "%s\t%s\n", formatUsage(u.size, args.SizeInBytes, args.DecimalUnits), u.path)
+	}
+	fmt.Fprintf(w, "%s\t%s\n", formatUsage(total, args.SizeInBytes, args.DecimalUnits), root.Name)
+
+	w.Flush()
+	return nil
+}
+
+// Returns the sizes of the files below the node by id, nodes below the root
+// down to the max depth are added to usages. Like tree --sizes, files and
+// directories with several parents below a directory are only counted once
+func collectDiskUsage(node *treeNode, path string, depth, maxDepth int, usages *[]diskUsage) map[string]int64 {
+	files := map[string]int64{node.Id: node.Size}
+	for _, child := range node.Children {
+		for id, size := range collectDiskUsage(child, filepath.Join(path, child.Name), depth+1, maxDepth, usages) {
+			files[id] = size
+		}
+	}
+
+	if depth > 0 && depth <= maxDepth {
+		*usages = append(*usages, diskUsage{path, sumFileSizes(files)})
+	}
+
+	return files
+}
+
+func sumFileSizes(files map[string]int64) int64 {
+	var total int64
+	for _, size := range files {
+		total += size
+	}
+	return total
+}
+
+// Unlike formatSize empty sizes are shown as 0
+func formatUsage(size int64, forceBytes, decimal bool) string {
+	if size == 0 {
+		return "0 B"
+	}
+	return formatSize(size, forceBytes, decimal)
+}
+
+type bySizeDesc []diskUsage
+
+func (self bySizeDesc) Len() int {
+	return len(self)
+}
+
+func (self bySizeDesc) Swap(i, j int) {
+	self[i], self[j] = self[j], self[i]
+}
+
+// Directories are walked concurrently, ties are broken by path so the
+// order is the same on every run
+func (self bySizeDesc) Less(i, j int) bool {
+	if self[i].size != self[j].size {
+		return self[i].size > self[j].size
+	}
+	return self[i].path < self[j].path
+}
@@ -0,0 +1,45 @@
+package drive
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// The shared directory is below both a and b, its files must be counted once
+// in every directory above it
+func TestDiskUsageMultipleParents(t *testing.T) {
+	d, _ := newFakeDrive(t,
+		fakeFolder("top", "Top"),
+		fakeFolder("a", "a", "top"),
+		fakeFolder("b", "b", "top"),
+		fakeFolder("shared", "shared", "a", "b"),
+		fakeBinary("f1", "f1", "12345", "shared"),
+		fakeBinary("f2", "f2", "123", "shared", "b"),
+		fakeBinary("f3", "f3", "1", "a"),
+	)
+
+	out := &bytes.Buffer{}
+	if err := d.DiskUsage(DiskUsageArgs{Out: out, Id: "top", MaxDepth: 2, SizeInBytes: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	sizes := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		fields := strings.Fields(line)
+		sizes[fields[len(fields)-1]] = fields[0]
+	}
+
+	expected := map[string]string{
+		"Top":          "9",
+		"Top/a":        "9",
+		"Top/a/shared": "8",
+		"Top/b":        "8",
+		"Top/b/shared": "8",
+	}
+	for path, size := range expected {
+		if sizes[path] != size {
+			t.Errorf("%s: got size %q, want %q", path, sizes[path], size)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package drive
+
+import (
+	"encoding/json"
+	"fmt"
+	"google.golang.org/api/googleapi"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type EmptyTrashArgs struct {
+	Out io.Writer
+	// Only files trashed longer ago than min age are deleted, all files if 0
+	MinAge       time.Duration
+	Confirm      ConfirmFunc
+	DecimalUnits bool
+}
+
+// Trashed file as returned by the files.list call, the client library
+// in use does not include the trashedTime field
+type trashedFile struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Size        int64  `json:"size,string"`
+	TrashedTime string `json:"trashedTime"`
+}
+
+type trashedFileList struct {
+	Files         []*trashedFile `json:"files"`
+	NextPageToken string         `json:"nextPageToken"`
+}
+
+func (self *Drive) EmptyTrash(args EmptyTrashArgs) error {
+	if args.MinAge <= 0 {
+		if err := confirm(args.Confirm, "Permanently delete all files in trash"); err != nil {
+			return err
+		}
+
+		if err := self.service.Files.EmptyTrash().Do(); err != nil {
+			return wrapError("Failed to empty trash", err)
+		}
+
+		fmt.Fprintln(args.Out, "Trash emptied")
+		return nil
+	}
+
+	files, err := self.listTrashedFiles()
+	if err != nil {
+		return err
+	}
+
+	cutoff := time.Now().Add(-args.MinAge)
+	before := formatDatetime(cutoff.Format(time.RFC3339))
+
+	var expired []*trashedFile
+	var size int64
+
+	// Files without a trashed time are kept, as their age is unknown
+	for _, f := range files {
+		trashed, err := time.Parse(time.RFC3339, f.TrashedTime)
+		if err != nil || trashed.After(cutoff) {
+			fmt.Fprintf(args.Out, "Keeping '%s', trashed %s\n", f.Name, formatTrashedTime(f.TrashedTime))
+			continue
+		}
+		expired = append(expired, f)
+		size += f.Size
+	}
+
+	if len(expired) == 0 {
+		fmt.Fprintf(args.Out, "No files trashed before %s\n", before)
+		return nil
+	}
+
+	message := fmt.Sprintf("Permanently delete %d files trashed before %s", len(expired), before)
+	if size > 0 {
+		message += fmt.Sprintf(", %s", formatSize(size, false, args.DecimalUnits))
+	}
+
+	if err := confirm(args.Confirm, message); err != nil {
+		return err
+	}
+
+	failed := 0
+
+	for _, f := range expired {
+		// Files in trashed directories are deleted with the directory
+		err := self.deleteFile(f.Id)
+		if err != nil && !IsNotFound(err) {
+			fmt.Fprintf(args.Out, "Failed to delete '%s': %s\n", f.Name, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(args.Out, "Deleted '%s'\n", f.Name)
+	}
+
+	fmt.Fprintf(args.Out, "Deleted %d files, kept %d\n", len(expired)-failed, len(files)-len(expired))
+
+	if failed > 0 {
+		return fmt.Errorf("Failed to delete %d of %d files", failed, len(expired))
+	}
+
+	return nil
+}
+
+func formatTrashedTime(trashedTime string) string {
+	if trashedTime == "" {
+		return "at an unknown time"
+	}
+	return formatDatetime(trashedTime)
+}
+
+func (self *Drive) listTrashedFiles() ([]*trashedFile, error) {
+	var files []*trashedFile
+	var pageToken string
+
+	for {
+		params := url.Values{}
+		params.Set("q", "trashed = true and 'me' in owners")
+		params.Set("pageSize", "1000")
+		params.Set("fields", "nextPageToken,files(id,name,size,trashedTime)")
+		if pageToken != "" {
+			params.Set("pageToken", pageToken)
+		}
+
+		urls := googleapi.ResolveRelative(self.service.BasePath, "files") + "?" + params.Encode()
+		req, _ := http.NewRequest("GET", urls, nil)
+
+		res, err := self.client.Do(req)
+		if err != nil {
+			return nil, wrapError("Failed to list trashed files", err)
+		}
+
+		list := &trashedFileList{}
+		err = googleapi.CheckResponse(res)
+		if err == nil {
+			err = json.NewDecoder(res.Body).Decode(list)
+		}
+		res.Body.Close()
+
+		if err != nil {
+			return nil, wrapError("Failed to list trashed files", err)
+		}
+
+		files = append(files, list.Files...)
+
+		if list.NextPageToken == "" {
+			return files, nil
+		}
+
+		pageToken = list.NextPageToken
+	}
+}
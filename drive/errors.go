@@ -1,15 +1,87 @@
 package drive
 
 import (
+	"errors"
+	"fmt"
 	"golang.org/x/net/context"
 	"google.golang.org/api/googleapi"
+	"net"
 	"time"
 )
 
 const MaxErrorRetries = 5
 
+// ErrNoFiles is returned by List when FailIfEmpty is set and no files matched
+var ErrNoFiles = errors.New("No files found")
+
+// Error describes the operation that failed, the underlying error,
+// i.e. a *googleapi.Error, is kept so it can be checked with errors.As
+type Error struct {
+	Message string
+	Err     error
+}
+
+func (self *Error) Error() string {
+	return fmt.Sprintf("%s: %s", self.Message, self.Err)
+}
+
+func (self *Error) Unwrap() error {
+	return self.Err
+}
+
+func wrapError(message string, err error) error {
+	return &Error{message, err}
+}
+
+// PathNotFoundError is returned when no file exists at an absolute path
+type PathNotFoundError struct {
+	Path string
+}
+
+func (self *PathNotFoundError) Error() string {
+	return fmt.Sprintf("Path '%s' not found", self.Path)
+}
+
+// Returns the api error wrapped in err, if any
+func apiError(err error) (*googleapi.Error, bool) {
+	var ae *googleapi.Error
+	ok := errors.As(err, &ae)
+	return ae, ok
+}
+
+func hasReason(ae *googleapi.Error, reasons ...string) bool {
+	for _, item := range ae.Errors {
+		for _, reason := range reasons {
+			if item.Reason == reason {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsNotFound returns true if the file, path or resource does not exist
+func IsNotFound(err error) bool {
+	var pathErr *PathNotFoundError
+	return isNotFoundError(err) || errors.As(err, &pathErr)
+}
+
+// IsRateLimit returns true if the request was rejected because too many
+// requests were made, the request can be retried after a while
+func IsRateLimit(err error) bool {
+	ae, ok := apiError(err)
+	return ok && (ae.Code == 429 || ae.Code == 403 && hasReason(ae, "rateLimitExceeded", "userRateLimitExceeded"))
+}
+
+// IsPermission returns true if the user is not allowed to access the file or resource
+func IsPermission(err error) bool {
+	ae, ok := apiError(err)
+	return ok && (ae.Code == 401 || ae.Code == 403 && !IsRateLimit(err))
+}
+
+// Other 403 errors are permission denials, which fail the same way when retried
 func isBackendOrRateLimitError(err error) bool {
-	return isBackendError(err) || isRateLimitError(err)
+	return isBackendError(err) || IsRateLimit(err)
 }
 
 func isBackendError(err error) bool {
@@ -17,21 +89,26 @@ func isBackendError(err error) bool {
 		return false
 	}
 
-	ae, ok := err.(*googleapi.Error)
+	ae, ok := apiError(err)
 	return ok && ae.Code >= 500 && ae.Code <= 599
 }
 
-func isRateLimitError(err error) bool {
+func isNotFoundError(err error) bool {
 	if err == nil {
 		return false
 	}
 
-	ae, ok := err.(*googleapi.Error)
-	return ok && ae.Code == 403
+	ae, ok := apiError(err)
+	return ok && ae.Code == 404
 }
 
 func isTimeoutError(err error) bool {
-	return err == context.Canceled
+	return errors.Is(err, context.Canceled)
+}
+
+func isRequestTimeoutError(err error) bool {
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
 }
 
 func exponentialBackoffSleep(try int) {
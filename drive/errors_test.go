@@ -0,0 +1,87 @@
+package drive
+
+import (
+	"errors"
+	"fmt"
+	"golang.org/x/net/context"
+	"google.golang.org/api/googleapi"
+	"net"
+	"testing"
+)
+
+func TestWrappedSentinelErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+	}{
+		{"unwrapped", ErrNoFiles},
+		{"wrapError", wrapError("Failed listing files", ErrNoFiles)},
+		{"fmt.Errorf", fmt.Errorf("list: %w", ErrNoFiles)},
+		{"wrapped twice", fmt.Errorf("list: %w", wrapError("Failed listing files", ErrNoFiles))},
+	}
+
+	for _, c := range cases {
+		if !errors.Is(c.err, ErrNoFiles) {
+			t.Errorf("%s: %v does not match ErrNoFiles", c.name, c.err)
+		}
+	}
+
+	if errors.Is(wrapError("Failed listing files", errors.New("No files found")), ErrNoFiles) {
+		t.Error("an error with the same message must not match ErrNoFiles")
+	}
+}
+
+func TestWrappedErrorClassification(t *testing.T) {
+	canceled := wrapError("Failed to download file", fmt.Errorf("read: %w", context.Canceled))
+	if !isTimeoutError(canceled) {
+		t.Errorf("wrapped context.Canceled is not a timeout error")
+	}
+
+	timeout := wrapError("Failed to get file", &net.OpError{Op: "dial", Err: timeoutError{}})
+	if !isRequestTimeoutError(timeout) {
+		t.Errorf("wrapped net timeout is not a request timeout error")
+	}
+	if isTimeoutError(timeout) {
+		t.Errorf("net timeout must not be a no data timeout")
+	}
+
+	backend := wrapError("Failed to get file", &googleapi.Error{Code: 503})
+	if !isBackendOrRateLimitError(backend) || IsNotFound(backend) {
+		t.Errorf("wrapped 503 is not classified as a backend error")
+	}
+
+	notFound := fmt.Errorf("info: %w", wrapError("Failed to get file", &googleapi.Error{Code: 404}))
+	if !IsNotFound(notFound) || isBackendOrRateLimitError(notFound) {
+		t.Errorf("wrapped 404 is not classified as not found")
+	}
+}
+
+func TestRetriedErrors(t *testing.T) {
+	cases := []struct {
+		name    string
+		err     *googleapi.Error
+		retried bool
+	}{
+		{"backend error", &googleapi.Error{Code: 500}, true},
+		{"unavailable", &googleapi.Error{Code: 503}, true},
+		{"too many requests", &googleapi.Error{Code: 429}, true},
+		{"rate limit", &googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{{Reason: "rateLimitExceeded"}}}, true},
+		{"user rate limit", &googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{{Reason: "userRateLimitExceeded"}}}, true},
+		{"forbidden", &googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{{Reason: "forbidden"}}}, false},
+		{"forbidden without reason", &googleapi.Error{Code: 403}, false},
+		{"not found", &googleapi.Error{Code: 404}, false},
+	}
+
+	for _, c := range cases {
+		err := wrapError("Failed to share file", c.err)
+		if retried := isBackendOrRateLimitError(err); retried != c.retried {
+			t.Errorf("%s: got retried %v, want %v", c.name, retried, c.retried)
+		}
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
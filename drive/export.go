@@ -2,9 +2,12 @@ package drive
 
 import (
 	"fmt"
+	"google.golang.org/api/drive/v3"
 	"io"
-	"mime"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 )
 
 var DefaultExportMime = map[string]string{
@@ -16,39 +19,94 @@ var DefaultExportMime = map[string]string{
 	"application/vnd.google-apps.presentation": "application/pdf",
 }
 
+// Google apps types without content of their own and without any export formats
+var unexportableTypes = map[string]string{
+	"application/vnd.google-apps.site":        "Google Site",
+	"application/vnd.google-apps.map":         "Google My Maps map",
+	"application/vnd.google-apps.fusiontable": "Google Fusion Table",
+	"application/vnd.google-apps.shortcut":    "shortcut",
+}
+
+// Returns an error naming the type if the file can be neither downloaded nor exported
+func unexportableError(f *drive.File) error {
+	kind, ok := unexportableTypes[f.MimeType]
+	if !ok {
+		return nil
+	}
+	return fmt.Errorf("'%s' is a %s (%s) and cannot be downloaded or exported", f.Name, kind, f.MimeType)
+}
+
 type ExportArgs struct {
 	Out        io.Writer
 	Id         string
 	PrintMimes bool
 	Mime       string
 	Force      bool
+	// Write the exported file to Out, messages are written to Err instead
+	Stdout bool
+	Err    io.Writer
+	// Export each sheet of a spreadsheet to a separate csv file
+	PerSheet bool
+	// Write the exported file to this path instead of the name of the file
+	// with the extension of the export format
+	OutputPath string
+	// Extension of the export format, i.e. pdf, or auto to use the extension
+	// of the output path. Defaults to auto when the output path has a known extension
+	ExportFormat string
 }
 
 func (self *Drive) Export(args ExportArgs) error {
 	f, err := self.service.Files.Get(args.Id).Fields("name", "mimeType").Do()
 	if err != nil {
-		return fmt.Errorf("Failed to get file: %s", err)
+		return wrapError("Failed to get file", err)
 	}
 
 	if args.PrintMimes {
 		return self.printMimes(args.Out, f.MimeType)
 	}
 
-	exportMime, err := getExportMime(args.Mime, f.MimeType)
+	if err := unexportableError(f); err != nil {
+		return err
+	}
+
+	if args.PerSheet {
+		if args.OutputPath != "" {
+			return fmt.Errorf("--output can not be used with --per-sheet")
+		}
+		return self.exportSheets(f, args)
+	}
+
+	exportMime, err := self.resolveExportMime(args, f)
 	if err != nil {
 		return err
 	}
 
-	filename := getExportFilename(f.Name, exportMime)
+	filename := args.OutputPath
+	if !args.Stdout && filename == "" {
+		filename, err = getExportFilename(f.Name, exportMime)
+		if err != nil {
+			return err
+		}
+	}
 
 	res, err := self.service.Files.Export(args.Id, exportMime).Download()
 	if err != nil {
-		return fmt.Errorf("Failed to download file: %s", err)
+		return wrapError("Failed to download file", err)
 	}
 
 	// Close body on function exit
 	defer res.Body.Close()
 
+	if args.Stdout {
+		_, err = io.Copy(args.Out, res.Body)
+		if err != nil {
+			return wrapError("Failed writing to stdout", err)
+		}
+
+		fmt.Fprintf(args.Err, "Exported '%s' with mime type: '%s'\n", f.Name, exportMime)
+		return nil
+	}
+
 	// Check if file exists
 	if !args.Force && fileExists(filename) {
 		return fmt.Errorf("File '%s' already exists, use --force to overwrite", filename)
@@ -66,7 +124,7 @@ func (self *Drive) Export(args ExportArgs) error {
 	// Save file to disk
 	_, err = io.Copy(outFile, res.Body)
 	if err != nil {
-		return fmt.Errorf("Failed saving file: %s", err)
+		return wrapError("Failed saving file", err)
 	}
 
 	fmt.Fprintf(args.Out, "Exported '%s' with mime type: '%s'\n", filename, exportMime)
@@ -76,7 +134,7 @@ func (self *Drive) Export(args ExportArgs) error {
 func (self *Drive) printMimes(out io.Writer, mimeType string) error {
 	about, err := self.service.About.Get().Fields("exportFormats").Do()
 	if err != nil {
-		return fmt.Errorf("Failed to get about: %s", err)
+		return wrapError("Failed to get about", err)
 	}
 
 	mimes, ok := about.ExportFormats[mimeType]
@@ -101,11 +159,97 @@ func getExportMime(userMime, fileMime string) (string, error) {
 	return defaultMime, nil
 }
 
-func getExportFilename(name, mimeType string) string {
-	extensions, err := mime.ExtensionsByType(mimeType)
-	if err != nil || len(extensions) == 0 {
-		return name
+// The export format is given either as a mime type or as an extension,
+// and the extension is taken from the output path with auto
+func (self *Drive) resolveExportMime(args ExportArgs, f *drive.File) (string, error) {
+	format := strings.TrimPrefix(strings.ToLower(args.ExportFormat), ".")
+	if args.Mime != "" && format != "" {
+		return "", fmt.Errorf("--mime and --export-format can not be used together")
+	}
+
+	if format == "" && args.Mime == "" && exportExtensionKnown(filepath.Ext(args.OutputPath)) {
+		format = "auto"
+	}
+
+	if format == "auto" {
+		if args.OutputPath == "" {
+			return "", fmt.Errorf("--export-format auto requires --output")
+		}
+
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(args.OutputPath)), ".")
+		if format == "" {
+			return "", fmt.Errorf("Cannot infer the export format, '%s' has no extension", args.OutputPath)
+		}
 	}
 
-	return name + extensions[0]
+	if format == "" {
+		return getExportMime(args.Mime, f.MimeType)
+	}
+
+	about, err := self.service.About.Get().Fields("exportFormats").Do()
+	if err != nil {
+		return "", wrapError("Failed to get about", err)
+	}
+
+	mimes := about.ExportFormats[f.MimeType]
+	for _, mime := range mimes {
+		if exportExtensions[mime] == "."+format {
+			return mime, nil
+		}
+	}
+
+	var available []string
+	for _, mime := range mimes {
+		if extension, ok := exportExtensions[mime]; ok && !containsString(available, extension[1:]) {
+			available = append(available, extension[1:])
+		}
+	}
+	sort.Strings(available)
+
+	return "", fmt.Errorf("'%s' (%s) can not be exported as %s, available formats: %s", f.Name, f.MimeType, format, formatList(available))
+}
+
+func exportExtensionKnown(extension string) bool {
+	extension = strings.ToLower(extension)
+	for _, known := range exportExtensions {
+		if extension == known {
+			return true
+		}
+	}
+	return false
+}
+
+// File extensions of the export formats, the system mime types can not be used
+// as they differ between systems and list several extensions in no particular order
+var exportExtensions = map[string]string{
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document":   ".docx",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":         ".xlsx",
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation": ".pptx",
+
+	"application/vnd.oasis.opendocument.text":          ".odt",
+	"application/vnd.oasis.opendocument.spreadsheet":   ".ods",
+	"application/x-vnd.oasis.opendocument.spreadsheet": ".ods",
+	"application/vnd.oasis.opendocument.presentation":  ".odp",
+
+	"application/pdf":                         ".pdf",
+	"application/rtf":                         ".rtf",
+	"application/epub+zip":                    ".epub",
+	"application/zip":                         ".zip",
+	"application/vnd.google-apps.script+json": ".json",
+	"text/plain":                              ".txt",
+	"text/html":                               ".html",
+	"text/markdown":                           ".md",
+	"text/csv":                                ".csv",
+	"text/tab-separated-values":               ".tsv",
+	"image/jpeg":                              ".jpg",
+	"image/png":                               ".png",
+	"image/svg+xml":                           ".svg",
+}
+
+func getExportFilename(name, mimeType string) (string, error) {
+	extension, ok := exportExtensions[mimeType]
+	if !ok {
+		return "", fmt.Errorf("Unknown file extension for export mime type '%s'", mimeType)
+	}
+	return name + extension, nil
 }
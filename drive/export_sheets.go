@@ -0,0 +1,151 @@
+package drive
+
+import (
+	"encoding/json"
+	"fmt"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const spreadsheetMimeType = "application/vnd.google-apps.spreadsheet"
+
+// Drive only exports the first sheet of a spreadsheet to csv, so the sheets are
+// listed with the sheets api and exported one by one by their gid instead.
+// The sheets api accepts the drive scope, but it must be enabled separately
+// in the project of the oauth client
+const sheetsApiUrl = "https://sheets.googleapis.com/v4/spreadsheets/"
+const sheetExportUrl = "https://docs.google.com/spreadsheets/d/"
+
+type sheetProperties struct {
+	SheetId int64  `json:"sheetId"`
+	Title   string `json:"title"`
+}
+
+type spreadsheetSheets struct {
+	Sheets []struct {
+		Properties sheetProperties `json:"properties"`
+	} `json:"sheets"`
+}
+
+// Writes each sheet of the spreadsheet to <name>-<sheet title>.csv,
+// no files are written if any of them already exists and force is not given
+func (self *Drive) exportSheets(f *drive.File, args ExportArgs) error {
+	if f.MimeType != spreadsheetMimeType {
+		return fmt.Errorf("Only spreadsheets can be exported per sheet, '%s' has type '%s'", f.Name, f.MimeType)
+	}
+
+	sheets, err := self.listSheets(args.Id)
+	if err != nil {
+		return err
+	}
+
+	filenames := make([]string, len(sheets))
+	for i, sheet := range sheets {
+		// Both the file name and the sheet title may contain path separators
+		filenames[i] = localFileName(fmt.Sprintf("%s-%s.csv", f.Name, sheet.Title))
+
+		if !args.Force && fileExists(filenames[i]) {
+			return fmt.Errorf("File '%s' already exists, use --force to overwrite", filenames[i])
+		}
+	}
+
+	for i, sheet := range sheets {
+		if err := self.exportSheet(args.Id, sheet, filenames[i]); err != nil {
+			return err
+		}
+		fmt.Fprintf(args.Out, "Exported sheet '%s' to '%s'\n", sheet.Title, filenames[i])
+	}
+
+	return nil
+}
+
+func (self *Drive) listSheets(id string) ([]sheetProperties, error) {
+	params := url.Values{}
+	params.Set("fields", "sheets.properties(sheetId,title)")
+
+	res, err := self.client.Get(sheetsApiUrl + url.PathEscape(id) + "?" + params.Encode())
+	if err != nil {
+		return nil, wrapError("Failed to list sheets", err)
+	}
+	defer res.Body.Close()
+
+	if err := googleapi.CheckResponse(res); err != nil {
+		if isApiDisabled(err) {
+			return nil, fmt.Errorf("Failed to list sheets: the Google Sheets API is not enabled in the project of the oauth client, enable it in the Google Cloud console to export per sheet")
+		}
+		if isScopeInsufficient(err) {
+			return nil, fmt.Errorf("Failed to list sheets: the access token has no scope allowing the Google Sheets API, i.e. https://www.googleapis.com/auth/drive")
+		}
+		return nil, wrapError("Failed to list sheets", err)
+	}
+
+	spreadsheet := &spreadsheetSheets{}
+	if err := json.NewDecoder(res.Body).Decode(spreadsheet); err != nil {
+		return nil, wrapError("Failed to list sheets", err)
+	}
+
+	var sheets []sheetProperties
+	for _, sheet := range spreadsheet.Sheets {
+		sheets = append(sheets, sheet.Properties)
+	}
+	return sheets, nil
+}
+
+func (self *Drive) exportSheet(id string, sheet sheetProperties, filename string) error {
+	params := url.Values{}
+	params.Set("format", "csv")
+	params.Set("gid", fmt.Sprintf("%d", sheet.SheetId))
+
+	res, err := self.client.Get(sheetExportUrl + url.PathEscape(id) + "/export?" + params.Encode())
+	if err != nil {
+		return wrapError(fmt.Sprintf("Failed to export sheet '%s'", sheet.Title), err)
+	}
+	defer res.Body.Close()
+
+	if err := checkExportResponse(res); err != nil {
+		return wrapError(fmt.Sprintf("Failed to export sheet '%s'", sheet.Title), err)
+	}
+
+	outFile, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("Unable to create new file '%s': %s", filename, err)
+	}
+	defer outFile.Close()
+
+	if _, err := io.Copy(outFile, res.Body); err != nil {
+		return wrapError("Failed saving file", err)
+	}
+
+	return nil
+}
+
+// Newer apis only give the reason in the error details,
+// which are not parsed by the client library in use
+func isApiDisabled(err error) bool {
+	ae, ok := apiError(err)
+	if !ok || ae.Code != 403 {
+		return false
+	}
+	return hasReason(ae, "accessNotConfigured") || strings.Contains(ae.Body, "SERVICE_DISABLED")
+}
+
+func isScopeInsufficient(err error) bool {
+	ae, ok := apiError(err)
+	if !ok || ae.Code != 403 {
+		return false
+	}
+	return hasReason(ae, "insufficientPermissions") || strings.Contains(ae.Body, "ACCESS_TOKEN_SCOPE_INSUFFICIENT")
+}
+
+// The export url is not part of the api and does not return api errors
+func checkExportResponse(res *http.Response) error {
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("%s", res.Status)
+	}
+	return nil
+}
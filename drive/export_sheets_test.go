@@ -0,0 +1,66 @@
+package drive
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// Sends the requests to the sheets api to the test server instead
+type testServerTransport struct {
+	url *url.URL
+}
+
+func (self *testServerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = self.url.Scheme
+	req.URL.Host = self.url.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// A disabled sheets api or a missing scope is reported as such,
+// other errors are kept as is
+func TestListSheetsErrors(t *testing.T) {
+	cases := []struct {
+		body     string
+		expected string
+	}{
+		{
+			`{"error":{"code":403,"message":"Google Sheets API has not been used in project 123 before or it is disabled.","status":"PERMISSION_DENIED","details":[{"@type":"type.googleapis.com/google.rpc.ErrorInfo","reason":"SERVICE_DISABLED"}]}}`,
+			"the Google Sheets API is not enabled",
+		},
+		{
+			`{"error":{"code":403,"message":"Access Not Configured.","errors":[{"reason":"accessNotConfigured"}]}}`,
+			"the Google Sheets API is not enabled",
+		},
+		{
+			`{"error":{"code":403,"message":"Request had insufficient authentication scopes.","status":"PERMISSION_DENIED","details":[{"@type":"type.googleapis.com/google.rpc.ErrorInfo","reason":"ACCESS_TOKEN_SCOPE_INSUFFICIENT"}]}}`,
+			"the access token has no scope allowing the Google Sheets API",
+		},
+		{
+			`{"error":{"code":403,"message":"The caller does not have permission","status":"PERMISSION_DENIED"}}`,
+			"The caller does not have permission",
+		},
+	}
+
+	for _, c := range cases {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, c.body)
+		}))
+
+		u, _ := url.Parse(srv.URL)
+		d, err := New(&http.Client{Transport: &testServerTransport{u}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = d.listSheets("spreadsheet")
+		if err == nil || !strings.Contains(err.Error(), c.expected) {
+			t.Errorf("got error %v, want %q", err, c.expected)
+		}
+		srv.Close()
+	}
+}
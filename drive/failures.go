@@ -0,0 +1,62 @@
+package drive
+
+import (
+	"fmt"
+	"io"
+)
+
+type failedPath struct {
+	path string
+	err  error
+}
+
+// Keeps track of failed paths when recursive operations continue on error.
+// A nil summary means that the operation should stop on the first error
+type failureSummary struct {
+	succeeded int
+	failed    []failedPath
+}
+
+func newFailureSummary(continueOnError bool) *failureSummary {
+	if !continueOnError {
+		return nil
+	}
+	return &failureSummary{}
+}
+
+// Records the result of the operation on path. The error is returned as is
+// when not continuing on error, otherwise it is logged and nil is returned
+func (self *failureSummary) record(out io.Writer, path string, err error) error {
+	if self == nil {
+		return err
+	}
+
+	if err == nil {
+		self.succeeded++
+		return nil
+	}
+
+	fmt.Fprintf(out, "Failed %s: %s\n", path, err)
+	self.failed = append(self.failed, failedPath{path, err})
+	return nil
+}
+
+// Prints the summary and returns an error if any of the paths failed
+func (self *failureSummary) print(out io.Writer) error {
+	if self == nil {
+		return nil
+	}
+
+	fmt.Fprintf(out, "%d succeeded, %d failed\n", self.succeeded, len(self.failed))
+
+	if len(self.failed) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(out, "Failed paths:")
+	for _, f := range self.failed {
+		fmt.Fprintf(out, "  %s: %s\n", f.path, f.err)
+	}
+
+	return fmt.Errorf("%d of %d failed", len(self.failed), self.succeeded+len(self.failed))
+}
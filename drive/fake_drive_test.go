@@ -0,0 +1,381 @@
+package drive
+
+import (
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"google.golang.org/api/drive/v3"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var parentQueryRegexp = regexp.MustCompile(`'([^']+)' in parents`)
+var nameQueryRegexp = regexp.MustCompile(`name = '((?:[^'\\]|\\.)*)'`)
+var mimeQueryRegexp = regexp.MustCompile(`mimeType (!?=) '([^']+)'`)
+
+type fakeFile struct {
+	drive.File
+	content string
+}
+
+// A minimal drive api server for tests. Files are listed in the order they
+// were added, only the 'id' in parents, name = 'name', mimeType = or != 'type'
+// and trashed = false query terms are applied and the page token is the
+// offset of the page
+type fakeDrive struct {
+	files    map[string]*fakeFile
+	order    []string
+	sessions []*fakeUploadSession
+	mutex    sync.Mutex
+	requests []*http.Request
+	// Number of upload chunks answered with a backend error,
+	// every chunk fails once until none are left
+	chunkErrors int
+}
+
+// A resumable upload creating a new file, or replacing the content of the
+// existing file if given
+type fakeUploadSession struct {
+	file     *fakeFile
+	existing string
+	size     int64
+	failed   bool
+}
+
+func newFakeDrive(t *testing.T, files ...*fakeFile) (*Drive, *fakeDrive) {
+	fake := &fakeDrive{files: map[string]*fakeFile{}}
+	for _, f := range files {
+		fake.add(f)
+	}
+
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	d, err := New(srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.service.BasePath = srv.URL + "/"
+	return d, fake
+}
+
+func (self *fakeDrive) add(f *fakeFile) {
+	self.files[f.Id] = f
+	self.order = append(self.order, f.Id)
+}
+
+// Returns the requests for the given path, i.e. "files"
+func (self *fakeDrive) requestsTo(path string) []*url.URL {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	var urls []*url.URL
+	for _, r := range self.requests {
+		if strings.TrimPrefix(r.URL.Path, "/") == path {
+			urls = append(urls, r.URL)
+		}
+	}
+	return urls
+}
+
+func (self *fakeDrive) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	self.requests = append(self.requests, r)
+	// Resumable uploads are sent to the upload endpoint
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	upload := strings.HasPrefix(path, "upload/drive/v3/")
+	parts := strings.Split(strings.TrimPrefix(path, "upload/drive/v3/"), "/")
+
+	switch {
+	case len(parts) == 1 && parts[0] == "files" && r.Method == "GET":
+		self.listFiles(w, r.URL.Query())
+	case upload && len(parts) == 1 && parts[0] == "files" && r.Method == "POST" && r.URL.Query().Get("uploadType") == "resumable":
+		self.createUploadSession(w, r, "")
+	case len(parts) == 1 && parts[0] == "files" && r.Method == "POST":
+		f, err := readFakeFile(r)
+		if err != nil {
+			fakeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		self.createFile(w, f)
+	case len(parts) == 2 && parts[0] == "sessions" && r.Method == "PUT":
+		n, _ := strconv.Atoi(parts[1])
+		if n < 1 || n > len(self.sessions) {
+			fakeError(w, http.StatusNotFound, "Session not found")
+			return
+		}
+		self.uploadChunk(w, r, self.sessions[n-1])
+	case upload && len(parts) == 2 && parts[0] == "files" && r.Method == "PATCH" && r.URL.Query().Get("uploadType") == "resumable":
+		if _, ok := self.files[parts[1]]; !ok {
+			fakeError(w, http.StatusNotFound, "File not found")
+			return
+		}
+		self.createUploadSession(w, r, parts[1])
+	case len(parts) >= 2 && parts[0] == "files":
+		f, ok := self.files[parts[1]]
+		if !ok {
+			fakeError(w, http.StatusNotFound, "File not found")
+			return
+		}
+		self.serveFile(w, r, f, parts[2:])
+	default:
+		fakeError(w, http.StatusNotImplemented, "Not implemented: "+r.URL.Path)
+	}
+}
+
+func (self *fakeDrive) listFiles(w http.ResponseWriter, params url.Values) {
+	query := params.Get("q")
+	parent := parentQueryRegexp.FindStringSubmatch(query)
+	name := nameQueryRegexp.FindStringSubmatch(query)
+	mimeTypes := mimeQueryRegexp.FindAllStringSubmatch(query, -1)
+
+	var files []*drive.File
+	for _, id := range self.order {
+		f, ok := self.files[id]
+		if !ok {
+			continue
+		}
+		if parent != nil && !hasParent(f, parent[1]) {
+			continue
+		}
+		if name != nil && f.Name != unescapeQueryValue(name[1]) {
+			continue
+		}
+		if !matchesMimeTerms(f, mimeTypes) {
+			continue
+		}
+		if f.Trashed && strings.Contains(query, "trashed = false") {
+			continue
+		}
+		file := f.File
+		files = append(files, &file)
+	}
+
+	start, _ := strconv.Atoi(params.Get("pageToken"))
+	end := len(files)
+	if pageSize, _ := strconv.Atoi(params.Get("pageSize")); pageSize > 0 && start+pageSize < end {
+		end = start + pageSize
+	}
+
+	fl := &drive.FileList{Files: files[start:end]}
+	if end < len(files) {
+		fl.NextPageToken = strconv.Itoa(end)
+	}
+	json.NewEncoder(w).Encode(fl)
+}
+
+func (self *fakeDrive) createFile(w http.ResponseWriter, f *fakeFile) {
+	f.Id = fmt.Sprintf("created%d", len(self.order)+1)
+	if f.MimeType != DirectoryMimeType {
+		setFakeContent(f, f.content)
+	}
+	self.add(f)
+
+	json.NewEncoder(w).Encode(&f.File)
+}
+
+// Only the metadata fields set by the tests are updated
+func (self *fakeDrive) updateFile(w http.ResponseWriter, r *http.Request, f *fakeFile) {
+	update, err := readFakeFile(r)
+	if err != nil {
+		fakeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if update.Name != "" {
+		f.Name = update.Name
+	}
+	if update.ModifiedTime != "" {
+		f.ModifiedTime = update.ModifiedTime
+	}
+	if update.Trashed {
+		f.Trashed = true
+	}
+	if r.URL.Query().Get("uploadType") != "" {
+		setFakeContent(f, update.content)
+	}
+
+	json.NewEncoder(w).Encode(&f.File)
+}
+
+func (self *fakeDrive) createUploadSession(w http.ResponseWriter, r *http.Request, existing string) {
+	f := &fakeFile{}
+	if err := json.NewDecoder(r.Body).Decode(&f.File); err != nil {
+		fakeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	size, _ := strconv.ParseInt(r.Header.Get("X-Upload-Content-Length"), 10, 64)
+	self.sessions = append(self.sessions, &fakeUploadSession{file: f, existing: existing, size: size})
+
+	w.Header().Set("Location", fmt.Sprintf("http://%s/sessions/%d", r.Host, len(self.sessions)))
+}
+
+// Chunks are appended to the content, the file is created or updated when
+// all bytes are received
+func (self *fakeDrive) uploadChunk(w http.ResponseWriter, r *http.Request, session *fakeUploadSession) {
+	chunk, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		fakeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Status requests have no content and never fail
+	if len(chunk) > 0 {
+		if self.chunkErrors > 0 && !session.failed {
+			self.chunkErrors--
+			session.failed = true
+			fakeError(w, http.StatusServiceUnavailable, "Backend Error")
+			return
+		}
+		session.failed = false
+	}
+	session.file.content += string(chunk)
+
+	received := int64(len(session.file.content))
+	if received < session.size {
+		if received > 0 {
+			w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", received-1))
+		}
+		w.WriteHeader(uploadResumeIncomplete)
+		return
+	}
+
+	if session.existing == "" {
+		self.createFile(w, session.file)
+		return
+	}
+
+	f := self.files[session.existing]
+	setFakeContent(f, session.file.content)
+	json.NewEncoder(w).Encode(&f.File)
+}
+
+// Reads the metadata and content of create and update requests,
+// only multipart uploads are supported
+func readFakeFile(r *http.Request) (*fakeFile, error) {
+	f := &fakeFile{}
+	if r.URL.Query().Get("uploadType") == "" {
+		return f, json.NewDecoder(r.Body).Decode(&f.File)
+	}
+
+	_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, err
+	}
+
+	// The metadata is the first part, it must be read before the next part
+	reader := multipart.NewReader(r.Body, params["boundary"])
+	metadata, err := reader.NextPart()
+	if err != nil {
+		return nil, err
+	}
+	if err := json.NewDecoder(metadata).Decode(&f.File); err != nil {
+		return nil, err
+	}
+
+	part, err := reader.NextPart()
+	if err != nil {
+		return nil, err
+	}
+	content, err := ioutil.ReadAll(part)
+	f.content = string(content)
+	return f, err
+}
+
+func setFakeContent(f *fakeFile, content string) {
+	f.content = content
+	f.Size = int64(len(content))
+	f.Md5Checksum = fmt.Sprintf("%x", md5.Sum([]byte(content)))
+}
+
+func (self *fakeDrive) serveFile(w http.ResponseWriter, r *http.Request, f *fakeFile, rest []string) {
+	switch {
+	case len(rest) == 1 && rest[0] == "export":
+		fmt.Fprintf(w, "%s exported as %s", f.Name, r.URL.Query().Get("mimeType"))
+	case r.Method == "GET" && r.URL.Query().Get("alt") == "media":
+		content := f.content
+		if rangeHeader := r.Header.Get("Range"); rangeHeader != "" {
+			offset, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rangeHeader, "bytes="), "-"))
+			content = content[offset:]
+			w.WriteHeader(http.StatusPartialContent)
+		}
+		fmt.Fprint(w, content)
+	case r.Method == "PATCH":
+		self.updateFile(w, r, f)
+	case r.Method == "DELETE":
+		delete(self.files, f.Id)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == "GET":
+		json.NewEncoder(w).Encode(&f.File)
+	default:
+		fakeError(w, http.StatusNotImplemented, "Not implemented: "+r.Method+" "+r.URL.Path)
+	}
+}
+
+func fakeError(w http.ResponseWriter, code int, message string) {
+	w.WriteHeader(code)
+	fmt.Fprintf(w, `{"error":{"code":%d,"message":%q}}`, code, message)
+}
+
+func unescapeQueryValue(value string) string {
+	return strings.NewReplacer(`\'`, `'`, `\\`, `\`).Replace(value)
+}
+
+func matchesMimeTerms(f *fakeFile, terms [][]string) bool {
+	for _, term := range terms {
+		if (f.MimeType == term[2]) != (term[1] == "=") {
+			return false
+		}
+	}
+	return true
+}
+
+func hasParent(f *fakeFile, parent string) bool {
+	for _, p := range f.Parents {
+		if p == parent {
+			return true
+		}
+	}
+	return false
+}
+
+func fakeFolder(id, name string, parents ...string) *fakeFile {
+	return &fakeFile{File: drive.File{Id: id, Name: name, MimeType: DirectoryMimeType, Parents: parents}}
+}
+
+func fakeBinary(id, name, content string, parents ...string) *fakeFile {
+	return &fakeFile{
+		File: drive.File{
+			Id:           id,
+			Name:         name,
+			MimeType:     "application/octet-stream",
+			Md5Checksum:  fmt.Sprintf("%x", md5.Sum([]byte(content))),
+			Size:         int64(len(content)),
+			Parents:      parents,
+			CreatedTime:  "2020-01-01T10:00:00Z",
+			ModifiedTime: "2020-05-01T10:00:00Z",
+		},
+		content: content,
+	}
+}
+
+// Binary files named file1 to fileN in the root folder
+func fakeBinaries(n int) []*fakeFile {
+	files := make([]*fakeFile, n)
+	for i := range files {
+		files[i] = fakeBinary(fmt.Sprintf("id%d", i+1), fmt.Sprintf("file%d", i+1), "", "root")
+	}
+	return files
+}
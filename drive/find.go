@@ -0,0 +1,127 @@
+package drive
+
+import (
+	"fmt"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+	"io"
+	"path"
+	"strings"
+)
+
+type FindArgs struct {
+	Out io.Writer
+	Id  string
+	// Substring of the name, or a glob if it contains any of *?[
+	Pattern string
+	// Only include files of the type, dir or file
+	Type     string
+	MaxDepth int
+}
+
+// Recursively searches the directory for files with a matching name and prints
+// their absolute paths, a max depth <= 0 means no limit. Names are matched case insensitively
+func (self *Drive) Find(args FindArgs) error {
+	if args.Type != "" && args.Type != "dir" && args.Type != "file" {
+		return fmt.Errorf("Invalid type '%s', must be dir or file", args.Type)
+	}
+
+	match, err := nameMatcher(args.Pattern)
+	if err != nil {
+		return err
+	}
+
+	id, err := self.resolvePathToId(args.Id)
+	if err != nil {
+		return err
+	}
+
+	f, err := self.service.Files.Get(id).Fields("id", "name", "mimeType", "parents").Do()
+	if err != nil {
+		return wrapError("Failed to get file", err)
+	}
+
+	if !isDir(f) {
+		return fmt.Errorf("'%s' is not a directory", f.Name)
+	}
+
+	finder := &fileFinder{
+		drive:      self,
+		pathfinder: self.newPathfinder(),
+		args:       args,
+		match:      match,
+	}
+
+	return finder.find(f, 1)
+}
+
+type fileFinder struct {
+	drive      *Drive
+	pathfinder *remotePathfinder
+	args       FindArgs
+	match      func(string) bool
+}
+
+// Matches are printed as they are found
+func (self *fileFinder) find(parent *drive.File, depth int) error {
+	// Directories are the parents of the files below them,
+	// adding them to the cache saves the pathfinder from fetching them again
+	self.pathfinder.files[parent.Id] = parent
+
+	files, err := self.drive.listAllFiles(listAllFilesArgs{
+		query:     fmt.Sprintf("'%s' in parents and trashed = false", parent.Id),
+		fields:    []googleapi.Field{"nextPageToken", "files(id,name,mimeType,parents)"},
+		sortOrder: "folder,name",
+	})
+	if err != nil {
+		return wrapError("Failed listing files", err)
+	}
+
+	for _, f := range files {
+		if self.match(f.Name) && self.isType(f) {
+			absPath, err := self.pathfinder.absPath(f)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(self.args.Out, absPath)
+		}
+
+		if isDir(f) && (self.args.MaxDepth <= 0 || depth < self.args.MaxDepth) {
+			if err := self.find(f, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (self *fileFinder) isType(f *drive.File) bool {
+	switch self.args.Type {
+	case "dir":
+		return isDir(f)
+	case "file":
+		return !isDir(f)
+	}
+	return true
+}
+
+func nameMatcher(pattern string) (func(string) bool, error) {
+	original := pattern
+	pattern = strings.ToLower(pattern)
+
+	if !strings.ContainsAny(pattern, "*?[") {
+		return func(name string) bool {
+			return strings.Contains(strings.ToLower(name), pattern)
+		}, nil
+	}
+
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("Invalid pattern '%s': %s", original, err)
+	}
+
+	return func(name string) bool {
+		matched, _ := path.Match(pattern, strings.ToLower(name))
+		return matched
+	}, nil
+}
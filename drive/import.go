@@ -10,11 +10,12 @@ import (
 )
 
 type ImportArgs struct {
-	Out      io.Writer
-	Mime     string
-	Progress io.Writer
-	Path     string
-	Parents  []string
+	Out          io.Writer
+	Mime         string
+	Progress     io.Writer
+	Path         string
+	Parents      []string
+	DecimalUnits bool
 }
 
 func (self *Drive) Import(args ImportArgs) error {
@@ -26,9 +27,13 @@ func (self *Drive) Import(args ImportArgs) error {
 		return fmt.Errorf("Could not determine mime type of file, use --mime")
 	}
 
+	if _, err := self.getParents(args.Parents); err != nil {
+		return err
+	}
+
 	about, err := self.service.About.Get().Fields("importFormats").Do()
 	if err != nil {
-		return fmt.Errorf("Failed to get about: %s", err)
+		return wrapError("Failed to get about", err)
 	}
 
 	toMimes, ok := about.ImportFormats[fromMime]
@@ -37,11 +42,12 @@ func (self *Drive) Import(args ImportArgs) error {
 	}
 
 	f, _, err := self.uploadFile(UploadArgs{
-		Out:      ioutil.Discard,
-		Progress: args.Progress,
-		Path:     args.Path,
-		Parents:  args.Parents,
-		Mime:     toMimes[0],
+		Out:          ioutil.Discard,
+		Progress:     args.Progress,
+		Path:         args.Path,
+		Parents:      args.Parents,
+		Mime:         toMimes[0],
+		DecimalUnits: args.DecimalUnits,
 	})
 	if err != nil {
 		return err
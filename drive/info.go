@@ -3,19 +3,45 @@ package drive
 import (
 	"fmt"
 	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
 	"io"
+	"net/url"
+	"sort"
 )
 
 type FileInfoArgs struct {
-	Out         io.Writer
-	Id          string
-	SizeInBytes bool
+	Out          io.Writer
+	Id           string
+	SizeInBytes  bool
+	DecimalUnits bool
+	Labels       []string
+	DownloadUrl  bool
+	// Only print whether the file is published to the web
+	Published bool
+	// Only request and print the given fields, i.e. md5Checksum,owners(emailAddress)
+	Fields string
 }
 
 func (self *Drive) Info(args FileInfoArgs) error {
+	if args.Fields != "" && (args.Published || args.DownloadUrl) {
+		return fmt.Errorf("--fields can not be used with --published or --download-url")
+	}
+
+	if args.Fields != "" {
+		return self.infoFields(args)
+	}
+
 	f, err := self.service.Files.Get(args.Id).Fields("id", "name", "size", "createdTime", "modifiedTime", "md5Checksum", "mimeType", "parents", "shared", "description", "webContentLink", "webViewLink").Do()
 	if err != nil {
-		return fmt.Errorf("Failed to get file: %s", err)
+		return wrapError("Failed to get file", err)
+	}
+
+	if args.DownloadUrl {
+		return self.printDownloadUrls(args.Out, f)
+	}
+
+	if args.Published {
+		return self.printPublished(args.Out, f)
 	}
 
 	pathfinder := self.newPathfinder()
@@ -24,21 +50,48 @@ func (self *Drive) Info(args FileInfoArgs) error {
 		return err
 	}
 
+	// Labels are only fetched when asked for, as drive requires the label ids
+	var labels []string
+	if len(args.Labels) > 0 {
+		labels, err = self.getAppliedLabels(f.Id, args.Labels)
+		if err != nil {
+			return err
+		}
+	}
+
 	PrintFileInfo(PrintFileInfoArgs{
-		Out:         args.Out,
-		File:        f,
-		Path:        absPath,
-		SizeInBytes: args.SizeInBytes,
+		Out:          args.Out,
+		File:         f,
+		Path:         absPath,
+		SizeInBytes:  args.SizeInBytes,
+		DecimalUnits: args.DecimalUnits,
+		Labels:       labels,
 	})
 
 	return nil
 }
 
+func (self *Drive) infoFields(args FileInfoArgs) error {
+	fields, err := parseInfoFields(args.Fields)
+	if err != nil {
+		return err
+	}
+
+	f, err := self.service.Files.Get(args.Id).Fields(infoFieldsSelection(fields)...).Do()
+	if err != nil {
+		return wrapError("Failed to get file", err)
+	}
+
+	return printInfoFields(args.Out, f, fields)
+}
+
 type PrintFileInfoArgs struct {
-	Out         io.Writer
-	File        *drive.File
-	Path        string
-	SizeInBytes bool
+	Out          io.Writer
+	File         *drive.File
+	Path         string
+	SizeInBytes  bool
+	DecimalUnits bool
+	Labels       []string
 }
 
 func PrintFileInfo(args PrintFileInfoArgs) {
@@ -50,7 +103,7 @@ func PrintFileInfo(args PrintFileInfoArgs) {
 		kv{"Path", args.Path},
 		kv{"Description", f.Description},
 		kv{"Mime", f.MimeType},
-		kv{"Size", formatSize(f.Size, args.SizeInBytes)},
+		kv{"Size", formatSize(f.Size, args.SizeInBytes, args.DecimalUnits)},
 		kv{"Created", formatDatetime(f.CreatedTime)},
 		kv{"Modified", formatDatetime(f.ModifiedTime)},
 		kv{"Md5sum", f.Md5Checksum},
@@ -58,6 +111,7 @@ func PrintFileInfo(args PrintFileInfoArgs) {
 		kv{"Parents", formatList(f.Parents)},
 		kv{"ViewUrl", f.WebViewLink},
 		kv{"DownloadUrl", f.WebContentLink},
+		kv{"Labels", formatList(args.Labels)},
 	}
 
 	for _, item := range items {
@@ -66,3 +120,45 @@ func PrintFileInfo(args PrintFileInfoArgs) {
 		}
 	}
 }
+
+// Prints the api urls the file content can be fetched from. The api urls
+// require an authorization header with the user's credentials. Google documents
+// can not be downloaded, so the export url for each available mime type is printed instead
+func (self *Drive) printDownloadUrls(out io.Writer, f *drive.File) error {
+	if isDir(f) {
+		return fmt.Errorf("'%s' is a directory and can not be downloaded", f.Name)
+	}
+
+	if isBinary(f) {
+		fmt.Fprintf(out, "MediaUrl: %s\n", self.fileUrl(f.Id, "files/{fileId}", url.Values{"alt": {"media"}}))
+		if f.WebContentLink != "" {
+			fmt.Fprintf(out, "DownloadUrl: %s\n", f.WebContentLink)
+		}
+		return nil
+	}
+
+	about, err := self.service.About.Get().Fields("exportFormats").Do()
+	if err != nil {
+		return wrapError("Failed to get about", err)
+	}
+
+	mimes, ok := about.ExportFormats[f.MimeType]
+	if !ok {
+		return fmt.Errorf("File with type '%s' cannot be exported", f.MimeType)
+	}
+
+	sort.Strings(mimes)
+	for _, mime := range mimes {
+		fmt.Fprintf(out, "ExportUrl (%s): %s\n", mime, self.fileUrl(f.Id, "files/{fileId}/export", url.Values{"mimeType": {mime}}))
+	}
+
+	return nil
+}
+
+func (self *Drive) fileUrl(id, path string, params url.Values) string {
+	u, _ := url.Parse(googleapi.ResolveRelative(self.service.BasePath, path) + "?" + params.Encode())
+	googleapi.Expand(u, map[string]string{
+		"fileId": id,
+	})
+	return u.String()
+}
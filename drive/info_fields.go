@@ -0,0 +1,130 @@
+package drive
+
+import (
+	"encoding/json"
+	"fmt"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// A field name, optionally followed by a sub selection in parentheses
+var infoFieldPattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9]*(/[a-zA-Z][a-zA-Z0-9]*)*(\(.+\))?$`)
+
+// Splits a field selection like md5Checksum,owners(emailAddress) into its top level
+// fields, id and name are always included. An error is returned for invalid syntax
+func parseInfoFields(s string) ([]string, error) {
+	fields := []string{"id", "name"}
+	seen := map[string]bool{"id": true, "name": true}
+
+	for _, field := range splitTopLevel(s) {
+		field = strings.TrimSpace(field)
+		if !validFieldSelection(field) {
+			return nil, fmt.Errorf("Invalid field '%s' in '%s'", field, s)
+		}
+
+		if !seen[field] {
+			seen[field] = true
+			fields = append(fields, field)
+		}
+	}
+
+	return fields, nil
+}
+
+// Splits on commas that are not inside parentheses
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth := 0
+	start := 0
+
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(parts, s[start:])
+}
+
+func validFieldSelection(field string) bool {
+	if !infoFieldPattern.MatchString(field) {
+		return false
+	}
+
+	open := strings.Index(field, "(")
+	if open < 0 {
+		return true
+	}
+
+	// The sub selection must be balanced and valid itself
+	depth := 0
+	for _, c := range field {
+		if c == '(' {
+			depth++
+		} else if c == ')' {
+			depth--
+		}
+		if depth < 0 {
+			return false
+		}
+	}
+	if depth != 0 {
+		return false
+	}
+
+	for _, sub := range splitTopLevel(field[open+1 : len(field)-1]) {
+		if !validFieldSelection(strings.TrimSpace(sub)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Prints the requested fields in the given order, strings are printed as is and
+// other values as json. Fields without a value are skipped
+func printInfoFields(out io.Writer, f *drive.File, fields []string) error {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+
+	values := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		name := strings.SplitN(strings.SplitN(field, "(", 2)[0], "/", 2)[0]
+		value, ok := values[name]
+		if !ok {
+			continue
+		}
+
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			s = string(value)
+		}
+		fmt.Fprintf(out, "%s: %s\n", name, s)
+	}
+
+	return nil
+}
+
+func infoFieldsSelection(fields []string) []googleapi.Field {
+	var selection []googleapi.Field
+	for _, field := range fields {
+		selection = append(selection, googleapi.Field(field))
+	}
+	return selection
+}
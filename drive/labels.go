@@ -0,0 +1,53 @@
+package drive
+
+import (
+	"encoding/json"
+	"google.golang.org/api/googleapi"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type fileLabels struct {
+	LabelInfo struct {
+		Labels []struct {
+			Id string `json:"id"`
+		} `json:"labels"`
+	} `json:"labelInfo"`
+}
+
+// Returns the ids of the given labels that are applied to the file. The drive
+// client library in use does not know about labels, so the request is made manually.
+// Drive only includes the labels asked for in the response
+func (self *Drive) getAppliedLabels(fileId string, labelIds []string) ([]string, error) {
+	params := url.Values{}
+	params.Set("fields", "labelInfo(labels(id))")
+	params.Set("includeLabels", strings.Join(labelIds, ","))
+
+	urls := googleapi.ResolveRelative(self.service.BasePath, "files/{fileId}") + "?" + params.Encode()
+	req, _ := http.NewRequest("GET", urls, nil)
+	googleapi.Expand(req.URL, map[string]string{
+		"fileId": fileId,
+	})
+
+	res, err := self.client.Do(req)
+	if err != nil {
+		return nil, wrapError("Failed to get labels", err)
+	}
+	defer res.Body.Close()
+
+	if err := googleapi.CheckResponse(res); err != nil {
+		return nil, wrapError("Failed to get labels", err)
+	}
+
+	labels := &fileLabels{}
+	if err := json.NewDecoder(res.Body).Decode(labels); err != nil {
+		return nil, wrapError("Failed to get labels", err)
+	}
+
+	var ids []string
+	for _, label := range labels.LabelInfo.Labels {
+		ids = append(ids, label.Id)
+	}
+	return ids, nil
+}
@@ -1,40 +1,211 @@
 package drive
 
 import (
+	"encoding/csv"
 	"fmt"
-	"golang.org/x/net/context"
 	"google.golang.org/api/drive/v3"
 	"google.golang.org/api/googleapi"
 	"io"
-	"text/tabwriter"
 	"os"
-	"encoding/csv"	
+	"strings"
+	"text/tabwriter"
+	"text/template"
 )
 
 type ListFilesArgs struct {
-	Out         io.Writer
-	MaxFiles    int64
-	NameWidth   int64
-	Query       string
-	SortOrder   string
-	SkipHeader  bool
-	SizeInBytes bool
-	AbsPath     bool
-	UseCsv		bool
-	UseExtended	bool
+	Out            io.Writer
+	MaxFiles       int64
+	NameWidth      int64
+	Query          string
+	SortOrder      string
+	SkipHeader     bool
+	SizeInBytes    bool
+	DecimalUnits   bool
+	AbsPath        bool
+	UseCsv         bool
+	UseExtended    bool
+	Columns        []string
+	CountOnly      bool
+	Mime           string
+	Parent         string
+	ModifiedAfter  string
+	ModifiedBefore string
+	FormatTemplate string
+	SharedWithMe   bool
+	DetailedType   bool
+	Label          string
+	ModifiedByMe   bool
+	ViewedAfter    string
+	FoldersFirst   bool
+	Format         string
+	TimeFormat     string
+	FailIfEmpty    bool
+	HideFolders    bool
+	Space          string
+	// Only list files owned by the email address, or me
+	Owner string
+	// List the given number of most recently used files, see recentPreset
+	Recent int64
+	// The number of fetched files is shown here while listing many pages
+	Progress io.Writer
+	// Mark files with the same checksum as another listed file
+	MarkDuplicates bool
+	// List the single page starting at the token, a page has at most MaxFiles files
+	PageToken string
+	// The token of the next page is written here if there are more files,
+	// only a single page is listed when given
+	NextPageToken io.Writer
+	Layout        TableLayout
+	// Write the listing to the file instead of Out, required for xlsx
+	OutputPath string
+	// Sort the listed files by name, see sortFiles
+	SortBy string
+	// Compare numbers in names by value when sorting by name
+	Natural bool
+}
+
+var fileSpaces = []string{"drive", "appDataFolder", "photos"}
+
+func checkFileSpace(space string) error {
+	if space == "" || containsString(fileSpaces, space) {
+		return nil
+	}
+	return fmt.Errorf("Unknown space '%s', available spaces: %s", space, formatList(fileSpaces))
+}
+
+// There is no query term for files modified by me, so the files are
+// filtered after listing. Files modified by me are listed first by default.
+// Hidden folders are filtered after listing as well
+func listFilter(args ListFilesArgs) (func(*drive.File) bool, string) {
+	sortOrder := args.SortOrder
+	if args.ModifiedByMe && sortOrder == "" {
+		sortOrder = "modifiedByMeTime desc"
+	}
+
+	if !args.ModifiedByMe && !args.HideFolders {
+		return nil, sortOrder
+	}
+
+	return func(f *drive.File) bool {
+		if args.ModifiedByMe && f.ModifiedByMeTime == "" {
+			return false
+		}
+		return !(args.HideFolders && isDir(f))
+	}, sortOrder
+}
+
+// Lists the most recently used files first with the modified column,
+// a given sort order or columns are kept
+func recentPreset(args ListFilesArgs) ListFilesArgs {
+	args.MaxFiles = args.Recent
+
+	if args.SortOrder == "" {
+		args.SortOrder = "recency desc"
+	}
+
+	if len(args.Columns) == 0 {
+		args.Columns = append(append([]string{}, defaultFileColumns...), "modified")
+		if args.SharedWithMe {
+			args.Columns = append(args.Columns, "sharedby")
+		}
+		if args.UseExtended {
+			args.Columns = append(args.Columns, extendedFileColumns...)
+		}
+	}
+
+	return args
 }
 
 func (self *Drive) List(args ListFilesArgs) (err error) {
+	switch args.SortBy {
+	case "", "name":
+	default:
+		return fmt.Errorf("Invalid sort '%s', must be name", args.SortBy)
+	}
+
+	if args.Natural && args.SortBy == "" {
+		return fmt.Errorf("--natural requires --sort name")
+	}
+
+	args = listSharedParent(args)
+
+	args.Parent, err = self.resolvePathToId(args.Parent)
+	if err != nil {
+		return err
+	}
+
+	query, err := listQuery(args)
+	if err != nil {
+		return err
+	}
+	args.Query = query
+
+	if err := checkFileSpace(args.Space); err != nil {
+		return err
+	}
+
+	if args.Recent > 0 {
+		args = recentPreset(args)
+	}
+
+	if args.CountOnly {
+		return self.countFiles(args)
+	}
+
+	// Show who shared the files if no columns are given
+	if args.SharedWithMe && len(args.Columns) == 0 {
+		args.Columns = append(append([]string{}, defaultFileColumns...), "sharedby")
+		if args.UseExtended {
+			args.Columns = append(args.Columns, extendedFileColumns...)
+		}
+	}
+
+	columns, err := getFileColumns(args.Columns, args.UseExtended)
+	if err != nil {
+		return err
+	}
+
+	if err := checkFileListFormat(args.Format); err != nil {
+		return err
+	}
+
+	if args.Format == "xlsx" && args.OutputPath == "" {
+		return fmt.Errorf("The xlsx format requires --output")
+	}
+
+	timeLayout, err := parseTimeFormat(args.TimeFormat)
+	if err != nil {
+		return err
+	}
+
+	// Validate template before listing files
+	var tmpl *template.Template
+	if args.FormatTemplate != "" {
+		tmpl, err = parseFileTemplate(args.FormatTemplate, timeLayout, args.DecimalUnits)
+		if err != nil {
+			return err
+		}
+	}
+
+	filter, sortOrder := listFilter(args)
+
 	listArgs := listAllFilesArgs{
 		query:     args.Query,
-		fields:    []googleapi.Field{"nextPageToken", "files(id, name, md5Checksum, mimeType, size, createdTime, parents, headRevisionId)"},
-		sortOrder: args.SortOrder,
+		fields:    listProjection(args, columns).Fields(),
+		sortOrder: sortOrder,
 		maxFiles:  args.MaxFiles,
+		filter:    filter,
+		space:     args.Space,
+		progress:  args.Progress,
 	}
-	
-	files, err := self.listAllFiles(listArgs)
+
+	files, err := self.listFilesOrPage(args, listArgs)
 	if err != nil {
-		return fmt.Errorf("Failed to list files: %s", err)
+		return wrapError("Failed to list files", err)
+	}
+
+	if args.FailIfEmpty && len(files) == 0 {
+		return ErrNoFiles
 	}
 
 	pathfinder := self.newPathfinder()
@@ -49,16 +220,66 @@ func (self *Drive) List(args ListFilesArgs) (err error) {
 		}
 	}
 
+	// Sorted after the names are replaced, so absolute paths are sorted
+	if args.SortBy == "name" {
+		sortFiles(files, args.Natural)
+	}
+
+	// Applied last as the outermost key, the stable sort keeps the order
+	// within folders and files
+	if args.FoldersFirst {
+		sortFoldersFirst(files)
+	}
+
+	if args.OutputPath != "" {
+		outFile, err := os.Create(args.OutputPath)
+		if err != nil {
+			return fmt.Errorf("Unable to create new file '%s': %s", args.OutputPath, err)
+		}
+
+		// Close file on function exit
+		defer outFile.Close()
+		args.Out = outFile
+	}
+
+	if tmpl != nil {
+		return printTemplateFileList(args.Out, tmpl, files)
+	}
+
+	// Json times are only changed if a time format is given
+	if args.TimeFormat != "" && (args.Format == "json" || args.Format == "gron") {
+		files = withTimeLayout(files, timeLayout)
+	}
+
+	switch args.Format {
+	case "json":
+		return printJsonFileList(args.Out, files)
+	case "gron":
+		return printGronFileList(args.Out, files)
+	case "csv":
+		args.UseCsv = true
+	}
+
 	printArgs := PrintFileListArgs{
-			Out:         args.Out,
-			Files:       files,
-			NameWidth:   int(args.NameWidth),
-			SkipHeader:  args.SkipHeader,
-			SizeInBytes: args.SizeInBytes,
-			Delimiter:   '|',
-			UseExtended: args.UseExtended,
-		}
-	
+		Out:            args.Out,
+		Files:          files,
+		NameWidth:      int(args.NameWidth),
+		SkipHeader:     args.SkipHeader,
+		SizeInBytes:    args.SizeInBytes,
+		DecimalUnits:   args.DecimalUnits,
+		Delimiter:      '|',
+		UseExtended:    args.UseExtended,
+		Columns:        args.Columns,
+		DetailedType:   args.DetailedType,
+		TimeFormat:     args.TimeFormat,
+		MarkDuplicates: args.MarkDuplicates,
+		Layout:         args.Layout,
+	}
+
+	if args.Format == "xlsx" {
+		return printXlsxFileList(args.Out, printArgs)
+	}
+
 	if args.UseCsv {
 		PrintFileList(printArgs)
 	} else {
@@ -68,117 +289,442 @@ func (self *Drive) List(args ListFilesArgs) (err error) {
 	return
 }
 
+// Prints the number of matching files, only the file ids are fetched
+func (self *Drive) countFiles(args ListFilesArgs) error {
+	filter, sortOrder := listFilter(args)
+
+	listArgs := listAllFilesArgs{
+		query:     args.Query,
+		fields:    filterProjection(args).Fields(),
+		sortOrder: sortOrder,
+		maxFiles:  args.MaxFiles,
+		filter:    filter,
+		space:     args.Space,
+		progress:  args.Progress,
+	}
+
+	files, err := self.listFilesOrPage(args, listArgs)
+	if err != nil {
+		return wrapError("Failed to list files", err)
+	}
+
+	fmt.Fprintln(args.Out, len(files))
+
+	if args.FailIfEmpty && len(files) == 0 {
+		return ErrNoFiles
+	}
+	return nil
+}
+
 type listAllFilesArgs struct {
 	query     string
 	fields    []googleapi.Field
 	sortOrder string
 	maxFiles  int64
+	// Only files matching the filter are included if given
+	filter func(*drive.File) bool
+	// Drive space to list, the drive space is used by default
+	space string
+	// Optional writer for the number of fetched files
+	progress io.Writer
 }
 
+// Max page size allowed by the files.list call
+const maxListPageSize = 1000
+
+// Lists files until maxFiles files are found, maxFiles <= 0 lists all files.
+// With a filter maxFiles limits the files matching the filter, and pages are
+// fetched until enough files match or all files are listed. Without a filter the
+// page size of each request is limited to the number of remaining files
+// so that no more files than needed are fetched
 func (self *Drive) listAllFiles(args listAllFilesArgs) ([]*drive.File, error) {
 	var files []*drive.File
+	var pageToken string
 
-	var pageSize int64
-	if args.maxFiles > 0 && args.maxFiles < 1000 {
-		pageSize = args.maxFiles
-	} else {
-		pageSize = 1000
-	}
+	progress := &listProgress{writer: args.progress, maxFiles: args.maxFiles}
+	defer progress.clear()
 
-	controlledStop := fmt.Errorf("Controlled stop")
+	for {
+		// Full pages are fetched when filtering, as most files may not match
+		pageSize := int64(maxListPageSize)
+		if args.maxFiles > 0 && args.filter == nil {
+			pageSize = min64(pageSize, args.maxFiles-int64(len(files)))
+		}
 
-	err := self.service.Files.List().Q(args.query).Fields(args.fields...).OrderBy(args.sortOrder).PageSize(pageSize).Pages(context.TODO(), func(fl *drive.FileList) error {
-		files = append(files, fl.Files...)
+		fl, err := self.filesListCall(args, pageToken, pageSize).Do()
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, filterFiles(fl.Files, args.filter)...)
 
 		// Stop when we have all the files we need
-		if args.maxFiles > 0 && len(files) >= int(args.maxFiles) {
-			return controlledStop
+		if args.maxFiles > 0 && int64(len(files)) >= args.maxFiles {
+			return files[:args.maxFiles], nil
 		}
 
-		return nil
-	})
+		if fl.NextPageToken == "" {
+			return files, nil
+		}
 
-	if err != nil && err != controlledStop {
-		return nil, err
+		progress.draw(int64(len(files)))
+		pageToken = fl.NextPageToken
 	}
+}
 
+// Lists the single page starting at the page token, the first page if the token is empty.
+// The page size is maxFiles, the page may have fewer files matching the filter.
+// Returns the token of the next page, which is empty for the last page
+func (self *Drive) listFilePage(args listAllFilesArgs, pageToken string) ([]*drive.File, string, error) {
+	pageSize := int64(maxListPageSize)
 	if args.maxFiles > 0 {
-		n := min(len(files), int(args.maxFiles))
-		return files[:n], nil
+		pageSize = min64(pageSize, args.maxFiles)
 	}
 
+	fl, err := self.filesListCall(args, pageToken, pageSize).Do()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return filterFiles(fl.Files, args.filter), fl.NextPageToken, nil
+}
+
+func (self *Drive) filesListCall(args listAllFilesArgs, pageToken string, pageSize int64) *drive.FilesListCall {
+	call := self.service.Files.List().Q(args.query).Fields(args.fields...).PageSize(pageSize).PageToken(pageToken)
+
+	// Only send orderBy when a sort order is given
+	if sortOrder := strings.TrimSpace(args.sortOrder); sortOrder != "" {
+		call = call.OrderBy(sortOrder)
+	}
+
+	if args.space != "" {
+		call = call.Spaces(args.space)
+	}
+
+	return call
+}
+
+func filterFiles(files []*drive.File, filter func(*drive.File) bool) []*drive.File {
+	if filter == nil {
+		return files
+	}
+
+	var filtered []*drive.File
+	for _, f := range files {
+		if filter(f) {
+			filtered = append(filtered, f)
+		}
+	}
+	return filtered
+}
+
+// Lists a single page when paging manually, otherwise all pages up to max files
+func (self *Drive) listFilesOrPage(args ListFilesArgs, listArgs listAllFilesArgs) ([]*drive.File, error) {
+	if args.PageToken == "" && args.NextPageToken == nil {
+		return self.listAllFiles(listArgs)
+	}
+
+	files, nextPageToken, err := self.listFilePage(listArgs, args.PageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if args.NextPageToken != nil && nextPageToken != "" {
+		fmt.Fprintln(args.NextPageToken, nextPageToken)
+	}
 	return files, nil
 }
 
+// Shows the number of files fetched so far when listing more than one page,
+// the line is cleared before the files are printed
+type listProgress struct {
+	writer   io.Writer
+	maxFiles int64
+	drawn    bool
+}
+
+func (self *listProgress) draw(fetched int64) {
+	if self.writer == nil {
+		return
+	}
+
+	if self.maxFiles > 0 {
+		fmt.Fprintf(self.writer, "\rFetched %d of max %d files...", fetched, self.maxFiles)
+	} else {
+		fmt.Fprintf(self.writer, "\rFetched %d files...", fetched)
+	}
+	self.drawn = true
+}
+
+func (self *listProgress) clear() {
+	if self.drawn {
+		fmt.Fprintf(self.writer, "\r%50s\r", "")
+	}
+}
+
 type PrintFileListArgs struct {
-	Out         io.Writer
-	Files       []*drive.File
-	NameWidth   int
-	SkipHeader  bool
-	SizeInBytes bool
-	Delimiter	rune
-	UseExtended bool
+	Out          io.Writer
+	Files        []*drive.File
+	NameWidth    int
+	SkipHeader   bool
+	SizeInBytes  bool
+	DecimalUnits bool
+	Delimiter    rune
+	UseExtended  bool
+	Columns      []string
+	DetailedType bool
+	TimeFormat   string
+	// Add a column with the duplicate group of files sharing a checksum
+	MarkDuplicates bool
+	Layout         TableLayout
+}
+
+// Tabwriter settings of the listed columns, the zero value is the default layout
+type TableLayout struct {
+	MinWidth int
+	Padding  int
+	PadChar  byte
+}
+
+var defaultTableLayout = TableLayout{MinWidth: 0, Padding: 3, PadChar: ' '}
+
+// Pad char is space or tab
+func NewTableLayout(minWidth, padding int64, padChar string) (TableLayout, error) {
+	if minWidth < 0 || padding < 0 {
+		return TableLayout{}, fmt.Errorf("Min width and padding can not be negative")
+	}
+
+	layout := TableLayout{MinWidth: int(minWidth), Padding: int(padding)}
+	switch padChar {
+	case "space":
+		layout.PadChar = ' '
+	case "tab":
+		layout.PadChar = '\t'
+	default:
+		return TableLayout{}, fmt.Errorf("Invalid pad char '%s', must be space or tab", padChar)
+	}
+	return layout, nil
 }
 
+// Tabs are assumed to be 8 wide when padding with tabs
+func (self TableLayout) newWriter(out io.Writer) *tabwriter.Writer {
+	if self.PadChar == 0 {
+		self = defaultTableLayout
+	}
+
+	tabWidth := 0
+	if self.PadChar == '\t' {
+		tabWidth = 8
+	}
+
+	return tabwriter.NewWriter(out, self.MinWidth, tabWidth, self.Padding, self.PadChar, 0)
+}
+
+// Times are shown with the default layout if the time format is invalid,
+// the format is validated before listing
+func formatFileTime(iso string, args PrintFileListArgs) string {
+	layout, err := parseTimeFormat(args.TimeFormat)
+	if err != nil {
+		layout = defaultTimeLayout
+	}
+	return formatTime(iso, layout)
+}
+
+// The fields are always requested when the column is shown
+type fileColumn struct {
+	name   string
+	header string
+	fields Projection
+	value  func(*drive.File, PrintFileListArgs) string
+}
+
+var fileColumns = []fileColumn{
+	{"id", "Id", Projection{}, func(f *drive.File, args PrintFileListArgs) string {
+		return f.Id
+	}},
+	{"name", "Name", Projection{Name: true}, func(f *drive.File, args PrintFileListArgs) string {
+		return truncateString(f.Name, args.NameWidth)
+	}},
+	{"type", "Type", Projection{MimeType: true}, func(f *drive.File, args PrintFileListArgs) string {
+		if args.DetailedType {
+			return detailedFiletype(f)
+		}
+		return filetype(f)
+	}},
+	{"size", "Size", Projection{Size: true}, func(f *drive.File, args PrintFileListArgs) string {
+		return formatSize(f.Size, args.SizeInBytes, args.DecimalUnits)
+	}},
+	{"created", "Created", Projection{CreatedTime: true}, func(f *drive.File, args PrintFileListArgs) string {
+		return formatFileTime(f.CreatedTime, args)
+	}},
+	{"modified", "Modified", Projection{ModifiedTime: true}, func(f *drive.File, args PrintFileListArgs) string {
+		return formatFileTime(f.ModifiedTime, args)
+	}},
+	{"md5", "Checksum", Projection{Md5Checksum: true}, func(f *drive.File, args PrintFileListArgs) string {
+		return f.Md5Checksum
+	}},
+	{"revision", "HeadRevisionId", Projection{HeadRevisionId: true}, func(f *drive.File, args PrintFileListArgs) string {
+		return f.HeadRevisionId
+	}},
+	{"modifiedbyme", "Modified by me", Projection{ModifiedByMeTime: true}, func(f *drive.File, args PrintFileListArgs) string {
+		return formatFileTime(f.ModifiedByMeTime, args)
+	}},
+	{"viewed", "Viewed by me", Projection{ViewedByMeTime: true}, func(f *drive.File, args PrintFileListArgs) string {
+		return formatFileTime(f.ViewedByMeTime, args)
+	}},
+	{"sharedby", "Shared by", Projection{SharingUser: true}, func(f *drive.File, args PrintFileListArgs) string {
+		if f.SharingUser == nil {
+			return ""
+		}
+		return f.SharingUser.EmailAddress
+	}},
+	{"owner", "Owner", Projection{Owners: true}, func(f *drive.File, args PrintFileListArgs) string {
+		var emails []string
+		for _, owner := range f.Owners {
+			emails = append(emails, owner.EmailAddress)
+		}
+		return strings.Join(emails, ", ")
+	}},
+}
+
+var defaultFileColumns = []string{"id", "name", "type", "size", "created"}
+var extendedFileColumns = []string{"md5", "revision"}
+
+// Returns the column definitions for the given column names,
+// the default columns are used if no names are given
+func getFileColumns(names []string, extended bool) ([]fileColumn, error) {
+	if len(names) == 0 {
+		names = append([]string{}, defaultFileColumns...)
+		if extended {
+			names = append(names, extendedFileColumns...)
+		}
+	}
+
+	var columns []fileColumn
+
+	for _, name := range names {
+		column, ok := findFileColumn(strings.ToLower(strings.TrimSpace(name)))
+		if !ok {
+			return nil, fmt.Errorf("Unknown column '%s', available columns: %s", name, formatList(fileColumnNames()))
+		}
+		columns = append(columns, column)
+	}
+
+	return columns, nil
+}
+
+func findFileColumn(name string) (fileColumn, bool) {
+	for _, column := range fileColumns {
+		if column.name == name {
+			return column, true
+		}
+	}
+	return fileColumn{}, false
+}
+
+func fileColumnNames() []string {
+	var names []string
+	for _, column := range fileColumns {
+		names = append(names, column.name)
+	}
+	return names
+}
+
+// Headers and values are both built from the column definitions,
+// so the header always has the same number of fields as the records
+func fileColumnHeaders(columns []fileColumn) []string {
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = column.header
+	}
+	return headers
+}
+
+func fileColumnValues(f *drive.File, columns []fileColumn, args PrintFileListArgs) []string {
+	values := make([]string, len(columns))
+	for i, column := range columns {
+		values[i] = column.value(f, args)
+	}
+	return values
+}
+
+// The columns to print, including the duplicate column if files are marked
+func printFileColumns(args PrintFileListArgs) []fileColumn {
+	columns, _ := getFileColumns(args.Columns, args.UseExtended)
+	if args.MarkDuplicates {
+		columns = append(columns, duplicateColumn(args.Files))
+	}
+	return columns
+}
+
+const csvFlushInterval = 1000
+
 func PrintFileList(args PrintFileListArgs) {
-	w := csv.NewWriter(os.Stdout)
+	columns := printFileColumns(args)
+
+	w := csv.NewWriter(args.Out)
 	w.Comma = args.Delimiter
 
 	if !args.SkipHeader {
-	
-		headers := []string{"Id", "Name", "Type", "Size", "Created"}
-	
-		if args.UseExtended {
-			headers = append(headers, []string{"Checksum", "HeadRevisionId"}...)
-		}
-		
-		w.Write(headers)
+		w.Write(fileColumnHeaders(columns))
 	}
 
-	var records [][]string
-	
-	for _, f := range args.Files {
-		
-		record := []string{
-			f.Id,
-			truncateString(f.Name, args.NameWidth),
-			filetype(f),
-			formatSize(f.Size, args.SizeInBytes),
-			formatDatetime(f.CreatedTime),
-		}
-		
-		if args.UseExtended {
-			record = append(record, []string{f.Md5Checksum, f.HeadRevisionId}...)
+	// Records are written as they are produced, the writer is flushed
+	// periodically to avoid buffering large listings
+	for i, f := range args.Files {
+		writeCsvRecord(w, args.Out, fileColumnValues(f, columns, args))
+
+		if (i+1)%csvFlushInterval == 0 {
+			w.Flush()
 		}
-		
-		records = append(records, record)
 	}
-	
-	w.WriteAll(records)
+
 	w.Flush()
 }
 
+// A record of a single empty field is written as an empty line, which csv
+// readers skip, so the field is quoted instead
+func writeCsvRecord(w *csv.Writer, out io.Writer, record []string) {
+	if len(record) == 1 && record[0] == "" {
+		w.Flush()
+		fmt.Fprintln(out, `""`)
+		return
+	}
+	w.Write(record)
+}
+
 func PrintTabbedFileList(args PrintFileListArgs) {
-	w := new(tabwriter.Writer)
-	w.Init(args.Out, 0, 0, 3, ' ', 0)
+	columns := printFileColumns(args)
+
+	w := args.Layout.newWriter(args.Out)
 
 	if !args.SkipHeader {
-		fmt.Fprintln(w, "Id\tName\tType\tSize\tCreated")
+		fmt.Fprintln(w, strings.Join(fileColumnHeaders(columns), "\t"))
 	}
 
 	for _, f := range args.Files {
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
-			f.Id,
-			truncateString(f.Name, args.NameWidth),
-			filetype(f),
-			formatSize(f.Size, args.SizeInBytes),
-			formatDatetime(f.CreatedTime),
-		)
+		fmt.Fprintln(w, strings.Join(fileColumnValues(f, columns, args), "\t"))
 	}
 
 	w.Flush()
 }
 
+var googleAppsFiletypes = map[string]string{
+	"application/vnd.google-apps.document":     "gdoc",
+	"application/vnd.google-apps.spreadsheet":  "gsheet",
+	"application/vnd.google-apps.presentation": "gslides",
+	"application/vnd.google-apps.form":         "gform",
+	"application/vnd.google-apps.drawing":      "gdrawing",
+}
+
+// Same as filetype, but google documents are shown with the type of editor
+func detailedFiletype(f *drive.File) string {
+	if t, ok := googleAppsFiletypes[f.MimeType]; ok {
+		return t
+	}
+	return filetype(f)
+}
+
 func filetype(f *drive.File) string {
 	if isDir(f) {
 		return "dir"
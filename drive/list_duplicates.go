@@ -0,0 +1,22 @@
+package drive
+
+import (
+	"fmt"
+	"google.golang.org/api/drive/v3"
+)
+
+// Column marking the files with the same checksum as another file in the listing
+// with the number of their group, groups are numbered in the order they first appear
+func duplicateColumn(files []*drive.File) fileColumn {
+	groupNumbers := make(map[string]int)
+	for i, group := range duplicateGroups(files) {
+		groupNumbers[group[0].Md5Checksum] = i + 1
+	}
+
+	return fileColumn{"duplicate", "Duplicate", Projection{Md5Checksum: true}, func(f *drive.File, args PrintFileListArgs) string {
+		if n, ok := groupNumbers[f.Md5Checksum]; ok {
+			return fmt.Sprintf("dup %d", n)
+		}
+		return ""
+	}}
+}
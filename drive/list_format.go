@@ -0,0 +1,101 @@
+package drive
+
+import (
+	"encoding/json"
+	"fmt"
+	"google.golang.org/api/drive/v3"
+	"io"
+	"sort"
+	"strings"
+)
+
+var fileListFormats = []string{"table", "csv", "json", "gron", "xlsx"}
+
+func checkFileListFormat(format string) error {
+	if format == "" || containsString(fileListFormats, format) {
+		return nil
+	}
+	return fmt.Errorf("Unknown format '%s', available formats: %s", format, formatList(fileListFormats))
+}
+
+type fileListJson struct {
+	Files []*drive.File `json:"files"`
+}
+
+// Returns copies of the files with times formatted with the layout
+func withTimeLayout(files []*drive.File, layout string) []*drive.File {
+	formatted := make([]*drive.File, len(files))
+	for i, f := range files {
+		c := *f
+		c.CreatedTime = formatTime(f.CreatedTime, layout)
+		c.ModifiedTime = formatTime(f.ModifiedTime, layout)
+		c.ModifiedByMeTime = formatTime(f.ModifiedByMeTime, layout)
+		c.ViewedByMeTime = formatTime(f.ViewedByMeTime, layout)
+		formatted[i] = &c
+	}
+	return formatted
+}
+
+func printJsonFileList(out io.Writer, files []*drive.File) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(fileListJson{files})
+}
+
+// Prints the same fields as the json output as one assignment per line,
+// i.e. files[0].name = "report.pdf". Object keys are sorted
+func printGronFileList(out io.Writer, files []*drive.File) error {
+	data, err := json.Marshal(fileListJson{files})
+	if err != nil {
+		return wrapError("Failed to encode files", err)
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return wrapError("Failed to encode files", err)
+	}
+
+	var lines []string
+	gronValue("", value, &lines)
+
+	for _, line := range lines {
+		fmt.Fprintln(out, line)
+	}
+	return nil
+}
+
+// Only values and empty objects and arrays are printed, as all other
+// objects and arrays are given by the paths of their values
+func gronValue(path string, value interface{}, lines *[]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			*lines = append(*lines, fmt.Sprintf("%s = {}", path))
+			return
+		}
+
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			gronValue(strings.TrimPrefix(path+"."+key, "."), v[key], lines)
+		}
+
+	case []interface{}:
+		if len(v) == 0 {
+			*lines = append(*lines, fmt.Sprintf("%s = []", path))
+			return
+		}
+
+		for i, item := range v {
+			gronValue(fmt.Sprintf("%s[%d]", path, i), item, lines)
+		}
+
+	default:
+		encoded, _ := json.Marshal(v)
+		*lines = append(*lines, fmt.Sprintf("%s = %s", path, encoded))
+	}
+}
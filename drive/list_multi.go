@@ -0,0 +1,90 @@
+package drive
+
+import (
+	"fmt"
+	"google.golang.org/api/drive/v3"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Max number of queries listed at the same time by ListMulti
+const MaxConcurrentListings = 4
+
+// Max random delay before a listing starts, spreads out the
+// requests to avoid hitting the rate limit
+const maxListJitter = 250 * time.Millisecond
+
+// Lists the files of each query concurrently, the results are returned in the
+// same order as the queries. A failing query does not stop the other queries,
+// its result is nil and the error is included in the returned error
+func (self *Drive) ListMulti(queries []ListFilesArgs) ([][]*drive.File, error) {
+	results := make([][]*drive.File, len(queries))
+	errs := make([]error, len(queries))
+
+	// Limit the number of concurrent listings
+	sem := make(chan struct{}, MaxConcurrentListings)
+	wg := &sync.WaitGroup{}
+
+	for i, args := range queries {
+		wg.Add(1)
+		go func(i int, args ListFilesArgs) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			time.Sleep(time.Duration(rand.Int63n(int64(maxListJitter))))
+			results[i], errs[i] = self.listFiles(args)
+		}(i, args)
+	}
+
+	wg.Wait()
+
+	var messages []string
+	for i, err := range errs {
+		if err != nil {
+			messages = append(messages, fmt.Sprintf("query %d: %s", i+1, err))
+		}
+	}
+
+	if len(messages) > 0 {
+		return results, fmt.Errorf("Failed to list %d of %d queries: %s", len(messages), len(queries), strings.Join(messages, "; "))
+	}
+
+	return results, nil
+}
+
+func (self *Drive) listFiles(args ListFilesArgs) ([]*drive.File, error) {
+	args = listSharedParent(args)
+
+	parent, err := self.resolvePathToId(args.Parent)
+	if err != nil {
+		return nil, err
+	}
+	args.Parent = parent
+
+	query, err := listQuery(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkFileSpace(args.Space); err != nil {
+		return nil, err
+	}
+
+	// Unknown columns are left to the caller to report
+	columns, _ := getFileColumns(args.Columns, args.UseExtended)
+
+	filter, sortOrder := listFilter(args)
+
+	return self.listAllFiles(listAllFilesArgs{
+		query:     query,
+		fields:    listProjection(args, columns).Fields(),
+		sortOrder: sortOrder,
+		maxFiles:  args.MaxFiles,
+		filter:    filter,
+		space:     args.Space,
+	})
+}
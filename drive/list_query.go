@@ -0,0 +1,131 @@
+package drive
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+)
+
+// Builds the query for the list call. The raw query and each of the
+// structured filters are wrapped in parentheses and joined with 'and',
+// i.e. --query "name contains 'report'" --mime pdf --modified-after 2024-01-01 gives
+// (name contains 'report') and (mimeType = 'application/pdf') and (modifiedTime > '2024-01-01T00:00:00Z')
+func listQuery(args ListFilesArgs) (string, error) {
+	var clauses []string
+
+	if args.Query != "" {
+		clauses = append(clauses, args.Query)
+	}
+
+	if args.Mime != "" {
+		mimeType, err := parseMimeFilter(args.Mime)
+		if err != nil {
+			return "", err
+		}
+		clauses = append(clauses, fmt.Sprintf("mimeType = '%s'", escapeQueryValue(mimeType)))
+	}
+
+	if args.Parent != "" {
+		clauses = append(clauses, fmt.Sprintf("'%s' in parents", escapeQueryValue(args.Parent)))
+	}
+
+	if args.Label != "" {
+		clauses = append(clauses, fmt.Sprintf("'labels/%s' in labels", escapeQueryValue(args.Label)))
+	}
+
+	if args.Owner != "" {
+		if !isOwnerFilter(args.Owner) {
+			return "", fmt.Errorf("Invalid owner '%s', must be an email address or me", args.Owner)
+		}
+		clauses = append(clauses, fmt.Sprintf("'%s' in owners", escapeQueryValue(args.Owner)))
+	}
+
+	if args.SharedWithMe {
+		clauses = append(clauses, "sharedWithMe = true")
+	}
+
+	if args.ModifiedAfter != "" {
+		t, err := parseQueryTime(args.ModifiedAfter)
+		if err != nil {
+			return "", err
+		}
+		clauses = append(clauses, fmt.Sprintf("modifiedTime > '%s'", t))
+	}
+
+	if args.ModifiedBefore != "" {
+		t, err := parseQueryTime(args.ModifiedBefore)
+		if err != nil {
+			return "", err
+		}
+		clauses = append(clauses, fmt.Sprintf("modifiedTime < '%s'", t))
+	}
+
+	if args.ViewedAfter != "" {
+		t, err := parseQueryTime(args.ViewedAfter)
+		if err != nil {
+			return "", err
+		}
+		clauses = append(clauses, fmt.Sprintf("viewedByMeTime > '%s'", t))
+	}
+
+	// Keep the raw query untouched if no filters are given
+	if len(clauses) < 2 {
+		return strings.Join(clauses, ""), nil
+	}
+
+	for i, clause := range clauses {
+		clauses[i] = "(" + clause + ")"
+	}
+
+	return strings.Join(clauses, " and "), nil
+}
+
+// The owner is me or anything looking like a single email address
+func isOwnerFilter(owner string) bool {
+	if owner == "me" {
+		return true
+	}
+
+	at := strings.Index(owner, "@")
+	return at > 0 && at == strings.LastIndex(owner, "@") && at < len(owner)-1 && !strings.ContainsAny(owner, " ,")
+}
+
+// Accepts a mime type or a file extension like pdf
+func parseMimeFilter(value string) (string, error) {
+	if strings.Contains(value, "/") {
+		return value, nil
+	}
+
+	mimeType := mime.TypeByExtension("." + strings.TrimPrefix(value, "."))
+	if mimeType == "" {
+		return "", fmt.Errorf("Unknown mime type for extension '%s', use the full mime type instead", value)
+	}
+
+	// Remove parameters like charset
+	mimeType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return "", fmt.Errorf("Invalid mime type for extension '%s': %s", value, err)
+	}
+
+	return mimeType, nil
+}
+
+// Accepts a date (2006-01-02) or a RFC 3339 timestamp,
+// dates are interpreted as midnight UTC. Fractions of seconds are kept
+func parseQueryTime(value string) (string, error) {
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		t, err = time.Parse(time.RFC3339, value)
+	}
+	if err != nil {
+		return "", fmt.Errorf("Invalid date '%s', expected a date like 2006-01-02 or a RFC 3339 timestamp", value)
+	}
+
+	return t.UTC().Format(time.RFC3339Nano), nil
+}
+
+func escapeQueryValue(value string) string {
+	value = strings.Replace(value, `\`, `\\`, -1)
+	return strings.Replace(value, `'`, `\'`, -1)
+}
@@ -0,0 +1,68 @@
+package drive
+
+import "testing"
+
+func TestListQuery(t *testing.T) {
+	cases := []struct {
+		name  string
+		args  ListFilesArgs
+		query string
+	}{
+		{
+			"no filters",
+			ListFilesArgs{},
+			"",
+		},
+		{
+			"raw query only",
+			ListFilesArgs{Query: "trashed = false"},
+			"trashed = false",
+		},
+		{
+			"single filter",
+			ListFilesArgs{Mime: "pdf"},
+			"mimeType = 'application/pdf'",
+		},
+		{
+			"raw query with filters",
+			ListFilesArgs{Query: "name contains 'report'", Mime: "pdf", ModifiedAfter: "2024-01-01"},
+			"(name contains 'report') and (mimeType = 'application/pdf') and (modifiedTime > '2024-01-01T00:00:00Z')",
+		},
+		{
+			"raw query with or",
+			ListFilesArgs{Query: "name contains 'a' or name contains 'b'", Parent: "abc"},
+			"(name contains 'a' or name contains 'b') and ('abc' in parents)",
+		},
+		{
+			"escaped values",
+			ListFilesArgs{Mime: "text/plain", Owner: "o'neil@example.com"},
+			`(mimeType = 'text/plain') and ('o\'neil@example.com' in owners)`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			query, err := listQuery(c.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if query != c.query {
+				t.Errorf("got %q, want %q", query, c.query)
+			}
+		})
+	}
+}
+
+func TestListQueryInvalid(t *testing.T) {
+	cases := []ListFilesArgs{
+		{Query: "name contains 'report'", ModifiedAfter: "last week"},
+		{Query: "name contains 'report'", Mime: "notanextension"},
+		{Owner: "not an email"},
+	}
+
+	for _, args := range cases {
+		if query, err := listQuery(args); err == nil {
+			t.Errorf("expected an error for %+v, got %q", args, query)
+		}
+	}
+}
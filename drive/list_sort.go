@@ -0,0 +1,135 @@
+package drive
+
+import (
+	"google.golang.org/api/drive/v3"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Sorts the listed files by name after listing, numbers in the names are
+// compared by value if natural. Names equal except for case or leading zeros
+// are sorted by the exact name, then by id
+func sortFiles(files []*drive.File, natural bool) {
+	if natural {
+		sort.Stable(byNaturalName(files))
+	} else {
+		sort.Stable(byName(files))
+	}
+}
+
+type byName []*drive.File
+
+func (self byName) Len() int {
+	return len(self)
+}
+
+func (self byName) Swap(i, j int) {
+	self[i], self[j] = self[j], self[i]
+}
+
+func (self byName) Less(i, j int) bool {
+	return nameLess(self[i], self[j])
+}
+
+type byNaturalName []*drive.File
+
+func (self byNaturalName) Len() int {
+	return len(self)
+}
+
+func (self byNaturalName) Swap(i, j int) {
+	self[i], self[j] = self[j], self[i]
+}
+
+func (self byNaturalName) Less(i, j int) bool {
+	return naturalNameLess(self[i], self[j])
+}
+
+// Folders are sorted before files. Only the folder flag is compared, files
+// of the same kind keep their order, given by --order or --sort
+func sortFoldersFirst(files []*drive.File) {
+	sort.SliceStable(files, func(i, j int) bool {
+		return isDir(files[i]) && !isDir(files[j])
+	})
+}
+
+func nameLess(a, b *drive.File) bool {
+	na, nb := strings.ToLower(a.Name), strings.ToLower(b.Name)
+	if na != nb {
+		return na < nb
+	}
+	return exactNameLess(a, b)
+}
+
+func naturalNameLess(a, b *drive.File) bool {
+	if c := naturalCompare(a.Name, b.Name); c != 0 {
+		return c < 0
+	}
+	return exactNameLess(a, b)
+}
+
+func exactNameLess(a, b *drive.File) bool {
+	if a.Name != b.Name {
+		return a.Name < b.Name
+	}
+	return a.Id < b.Id
+}
+
+// Compares the strings with runs of digits compared by their numeric value,
+// so file2 is before file10 and file007 equals file7. Other characters are
+// compared case-insensitively. Returns -1, 0 or 1
+func naturalCompare(a, b string) int {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+
+	for a != "" && b != "" {
+		if isDigit(a[0]) && isDigit(b[0]) {
+			var na, nb string
+			na, a = splitDigits(a)
+			nb, b = splitDigits(b)
+
+			// Without leading zeros the longer number is the larger one
+			na, nb = strings.TrimLeft(na, "0"), strings.TrimLeft(nb, "0")
+			if len(na) != len(nb) {
+				return compareInts(len(na), len(nb))
+			}
+			if na != nb {
+				return strings.Compare(na, nb)
+			}
+			continue
+		}
+
+		ra, sa := utf8.DecodeRuneInString(a)
+		rb, sb := utf8.DecodeRuneInString(b)
+		if ra != rb {
+			return compareInts(int(ra), int(rb))
+		}
+		a, b = a[sa:], b[sb:]
+	}
+
+	// The shorter string is a prefix of the other
+	return compareInts(len(a), len(b))
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// Returns the leading digits and the rest of the string
+func splitDigits(s string) (string, string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,155 @@
+package drive
+
+import (
+	"bytes"
+	"google.golang.org/api/drive/v3"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSortFoldersFirstIsDeterministic(t *testing.T) {
+	files := []*drive.File{
+		{Id: "f3", Name: "b.txt"},
+		{Id: "d2", Name: "Photos", MimeType: DirectoryMimeType},
+		{Id: "f1", Name: "a.txt"},
+		{Id: "d1", Name: "photos", MimeType: DirectoryMimeType},
+		{Id: "f2", Name: "a.txt"},
+		{Id: "d3", Name: "archive", MimeType: DirectoryMimeType},
+		{Id: "f4", Name: "A.txt"},
+		{Id: "d4", Name: "photos", MimeType: DirectoryMimeType},
+	}
+
+	expected := []string{"d3", "d2", "d1", "d4", "f4", "f1", "f2", "f3"}
+
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		shuffled := append([]*drive.File{}, files...)
+		random.Shuffle(len(shuffled), func(i, j int) {
+			shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+		})
+
+		sortFiles(shuffled, false)
+		sortFoldersFirst(shuffled)
+
+		if ids := fileIds(shuffled); !reflect.DeepEqual(ids, expected) {
+			t.Fatalf("run %d: got %v, want %v", i, ids, expected)
+		}
+	}
+}
+
+func TestSortFoldersFirstNatural(t *testing.T) {
+	files := []*drive.File{
+		{Id: "f10", Name: "file10"},
+		{Id: "d10", Name: "dir10", MimeType: DirectoryMimeType},
+		{Id: "f2", Name: "file2"},
+		{Id: "d2", Name: "dir2", MimeType: DirectoryMimeType},
+	}
+
+	sortFiles(files, true)
+	sortFoldersFirst(files)
+
+	expected := []string{"d2", "d10", "f2", "f10"}
+	if ids := fileIds(files); !reflect.DeepEqual(ids, expected) {
+		t.Errorf("got %v, want %v", ids, expected)
+	}
+}
+
+// Without --sort the server order given by --order is kept within folders and files
+func TestListFoldersFirstKeepsServerOrder(t *testing.T) {
+	d, fake := newFakeDrive(t,
+		fakeBinary("f2", "b.txt", "", "root"),
+		fakeFolder("d2", "photos", "root"),
+		fakeBinary("f1", "a.txt", "", "root"),
+		fakeBinary("f3", "c.txt", "", "root"),
+		fakeFolder("d1", "archive", "root"),
+	)
+
+	out := &bytes.Buffer{}
+	err := d.List(ListFilesArgs{
+		Out:          out,
+		SortOrder:    "modifiedTime desc",
+		FoldersFirst: true,
+		UseCsv:       true,
+		SkipHeader:   true,
+		Columns:      []string{"id"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if orderBy := fake.requestsTo("files")[0].Query().Get("orderBy"); orderBy != "modifiedTime desc" {
+		t.Errorf("got orderBy %q, want modifiedTime desc", orderBy)
+	}
+
+	expected := []string{"d2", "d1", "f2", "f1", "f3"}
+	if ids := strings.Fields(out.String()); !reflect.DeepEqual(ids, expected) {
+		t.Errorf("got %v, want %v", ids, expected)
+	}
+}
+
+func TestNaturalCompare(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"file2", "file10", -1},
+		{"file10", "file2", 1},
+		{"file007", "file7", 0},
+		{"file007", "file8", -1},
+		{"file010", "file9", 1},
+		{"file0", "file00", 0},
+		{"File2", "file10", -1},
+		{"a1b2", "a1b10", -1},
+		{"a10b1", "a2b10", 1},
+		{"a1b", "a1", 1},
+		{"1a", "a", -1},
+		{"photo 9.jpg", "photo 10.jpg", -1},
+		{"s01e09", "s01e10", -1},
+		{"s2e01", "s10e01", -1},
+		{"abc", "abd", -1},
+		{"", "", 0},
+		{"", "1", -1},
+		{"99999999999999999999999", "100000000000000000000000", -1},
+	}
+
+	for _, c := range cases {
+		if result := naturalCompare(c.a, c.b); result != c.expected {
+			t.Errorf("naturalCompare(%q, %q): got %d, want %d", c.a, c.b, result, c.expected)
+		}
+	}
+}
+
+// Names equal except for leading zeros are sorted by the exact name, then by id
+func TestSortFilesNatural(t *testing.T) {
+	files := []*drive.File{
+		{Id: "a", Name: "file10"},
+		{Id: "b", Name: "file7"},
+		{Id: "c", Name: "file007"},
+		{Id: "d", Name: "file2"},
+		{Id: "e", Name: "file7"},
+	}
+
+	sortFiles(files, true)
+
+	expected := []string{"d", "c", "b", "e", "a"}
+	if ids := fileIds(files); !reflect.DeepEqual(ids, expected) {
+		t.Errorf("natural: got %v, want %v", ids, expected)
+	}
+
+	sortFiles(files, false)
+
+	expected = []string{"c", "a", "d", "b", "e"}
+	if ids := fileIds(files); !reflect.DeepEqual(ids, expected) {
+		t.Errorf("name: got %v, want %v", ids, expected)
+	}
+}
+
+func fileIds(files []*drive.File) []string {
+	ids := make([]string, len(files))
+	for i, f := range files {
+		ids[i] = f.Id
+	}
+	return ids
+}
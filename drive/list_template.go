@@ -0,0 +1,48 @@
+package drive
+
+import (
+	"fmt"
+	"google.golang.org/api/drive/v3"
+	"io"
+	"text/template"
+)
+
+// Functions available in list templates
+var fileTemplateFuncs = template.FuncMap{
+	"size": func(bytes int64) string {
+		return formatSize(bytes, false, false)
+	},
+	"date": formatDatetime,
+	"type": filetype,
+}
+
+// Parses a list template, the template is executed with a *drive.File,
+// i.e. '{{.Id}} {{.Name}} {{size .Size}} {{date .ModifiedTime}} {{type .}}'.
+// Dates are formatted with the given layout, sizes in decimal units if decimalUnits
+func parseFileTemplate(text, timeLayout string, decimalUnits bool) (*template.Template, error) {
+	funcs := template.FuncMap{
+		"date": func(iso string) string {
+			return formatTime(iso, timeLayout)
+		},
+		"size": func(bytes int64) string {
+			return formatSize(bytes, false, decimalUnits)
+		},
+	}
+
+	tmpl, err := template.New("file").Funcs(fileTemplateFuncs).Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid format template: %s", err)
+	}
+	return tmpl, nil
+}
+
+// Renders each file with the template, one line per file
+func printTemplateFileList(out io.Writer, tmpl *template.Template, files []*drive.File) error {
+	for _, f := range files {
+		if err := tmpl.Execute(out, f); err != nil {
+			return wrapError("Failed to render format template", err)
+		}
+		fmt.Fprintln(out)
+	}
+	return nil
+}
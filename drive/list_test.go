@@ -0,0 +1,252 @@
+package drive
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"google.golang.org/api/drive/v3"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestListAllFilesPagination(t *testing.T) {
+	cases := []struct {
+		name      string
+		total     int
+		maxFiles  int64
+		filter    func(*drive.File) bool
+		files     int
+		pageSizes []string
+	}{
+		{"empty", 0, 0, nil, 0, []string{"1000"}},
+		{"single page", 10, 0, nil, 10, []string{"1000"}},
+		{"unlimited", 2500, 0, nil, 2500, []string{"1000", "1000", "1000"}},
+		{"max 1", 2500, 1, nil, 1, []string{"1"}},
+		{"max equal to page size", 2500, 1000, nil, 1000, []string{"1000"}},
+		{"max between pages", 2500, 1500, nil, 1500, []string{"1000", "500"}},
+		{"max equal to total", 2500, 2500, nil, 2500, []string{"1000", "1000", "500"}},
+		{"max larger than total", 2500, 3000, nil, 2500, []string{"1000", "1000", "1000"}},
+		{"empty filtered page", 2500, 0, inLastPage, 500, []string{"1000", "1000", "1000"}},
+		{"filtered max", 2500, 10, inLastPage, 10, []string{"1000", "1000", "1000"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d, fake := newFakeDrive(t, fakeBinaries(c.total)...)
+
+			files, err := d.listAllFiles(listAllFilesArgs{
+				query:    "trashed = false",
+				maxFiles: c.maxFiles,
+				filter:   c.filter,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(files) != c.files {
+				t.Errorf("got %d files, want %d", len(files), c.files)
+			}
+
+			var pageSizes []string
+			for _, u := range fake.requestsTo("files") {
+				pageSizes = append(pageSizes, u.Query().Get("pageSize"))
+			}
+			if !reflect.DeepEqual(pageSizes, c.pageSizes) {
+				t.Errorf("got page sizes %v, want %v", pageSizes, c.pageSizes)
+			}
+		})
+	}
+}
+
+// Matches the files after the first 2000 files of fakeBinaries
+func inLastPage(f *drive.File) bool {
+	n, _ := strconv.Atoi(strings.TrimPrefix(f.Id, "id"))
+	return n > 2000
+}
+
+func TestListAllFilesOrderBy(t *testing.T) {
+	cases := map[string]string{
+		"":                   "",
+		"   ":                "",
+		"name":               "name",
+		" folder,name desc ": "folder,name desc",
+	}
+
+	for sortOrder, orderBy := range cases {
+		d, fake := newFakeDrive(t, fakeBinaries(3)...)
+
+		_, err := d.listAllFiles(listAllFilesArgs{sortOrder: sortOrder})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		params := fake.requestsTo("files")[0].Query()
+		if _, sent := params["orderBy"]; sent != (orderBy != "") {
+			t.Errorf("sort order %q: orderBy sent is %v, want %v", sortOrder, sent, orderBy != "")
+		}
+		if params.Get("orderBy") != orderBy {
+			t.Errorf("sort order %q: got orderBy %q, want %q", sortOrder, params.Get("orderBy"), orderBy)
+		}
+	}
+}
+
+func TestPrintFileListHeaderMatchesRecords(t *testing.T) {
+	files := []*drive.File{
+		{Id: "a", Name: "report, final.pdf", Md5Checksum: "1", Size: 10},
+		{Id: "b", Name: "copy", Md5Checksum: "1", Size: 10},
+		{Id: "c", Name: "folder", MimeType: DirectoryMimeType},
+	}
+
+	// No columns is the default columns, then every column alone and all columns
+	columnSets := [][]string{nil, fileColumnNames()}
+	for _, name := range fileColumnNames() {
+		columnSets = append(columnSets, []string{name})
+	}
+
+	for _, columns := range columnSets {
+		for _, extended := range []bool{false, true} {
+			for _, duplicates := range []bool{false, true} {
+				out := &bytes.Buffer{}
+				PrintFileList(PrintFileListArgs{
+					Out:            out,
+					Files:          files,
+					Delimiter:      ',',
+					Columns:        columns,
+					UseExtended:    extended,
+					MarkDuplicates: duplicates,
+				})
+
+				// Every record must have as many fields as the header
+				records, err := csv.NewReader(out).ReadAll()
+				if err != nil {
+					t.Fatalf("columns %v, extended %v, duplicates %v: %s", columns, extended, duplicates, err)
+				}
+				if len(records) != len(files)+1 {
+					t.Fatalf("columns %v: got %d records, want %d", columns, len(records), len(files)+1)
+				}
+
+				expected := len(printFileColumns(PrintFileListArgs{Columns: columns, UseExtended: extended, MarkDuplicates: duplicates}))
+				if len(records[0]) != expected {
+					t.Errorf("columns %v, extended %v, duplicates %v: got %d header fields, want %d", columns, extended, duplicates, len(records[0]), expected)
+				}
+			}
+		}
+	}
+}
+
+// Decimal units are taken from the print args, listings right after each
+// other may use different units
+func TestPrintFileListDecimalUnits(t *testing.T) {
+	files := []*drive.File{{Id: "a", Name: "file", Size: 1500}}
+
+	for _, c := range []struct {
+		decimal  bool
+		expected string
+	}{
+		{false, "1.5 KiB"},
+		{true, "1.5 KB"},
+		{false, "1.5 KiB"},
+	} {
+		out := &bytes.Buffer{}
+		PrintFileList(PrintFileListArgs{
+			Out:          out,
+			Files:        files,
+			Delimiter:    ',',
+			Columns:      []string{"size"},
+			DecimalUnits: c.decimal,
+		})
+
+		records, err := csv.NewReader(out).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if records[1][0] != c.expected {
+			t.Errorf("decimal %v: got %q, want %q", c.decimal, records[1][0], c.expected)
+		}
+	}
+}
+
+// Max files above the range of a 32 bit int must not be truncated,
+// i.e. 1<<32 + 1 would become 1
+func TestListAllFilesLargeMaxFiles(t *testing.T) {
+	for _, maxFiles := range []int64{1<<31 - 1, 1 << 31, 1<<32 + 1, 1 << 40, math.MaxInt64} {
+		d, fake := newFakeDrive(t, fakeBinaries(1500)...)
+
+		files, err := d.listAllFiles(listAllFilesArgs{maxFiles: maxFiles})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(files) != 1500 {
+			t.Errorf("max files %d: got %d files, want 1500", maxFiles, len(files))
+		}
+
+		for _, u := range fake.requestsTo("files") {
+			if pageSize := u.Query().Get("pageSize"); pageSize != "1000" {
+				t.Errorf("max files %d: got page size %s, want 1000", maxFiles, pageSize)
+			}
+		}
+	}
+}
+
+// Max files limits the files matching the client side filter, so pages are
+// fetched until enough files match even if most listed files do not
+func TestListMaxFilesWithFilter(t *testing.T) {
+	var files []*fakeFile
+	for i := 1; i <= 2500; i++ {
+		files = append(files, fakeFolder(fmt.Sprintf("dir%d", i), fmt.Sprintf("dir%d", i), "root"))
+	}
+
+	// Files in the first, second and third page of folders
+	for _, i := range []int{10, 1200, 2300, 2400, 2450} {
+		files[i-1] = fakeBinary(fmt.Sprintf("file%d", i), fmt.Sprintf("file%d", i), "", "root")
+	}
+
+	cases := []struct {
+		maxFiles int64
+		ids      []string
+		requests int
+	}{
+		{1, []string{"file10"}, 1},
+		{2, []string{"file10", "file1200"}, 2},
+		{3, []string{"file10", "file1200", "file2300"}, 3},
+		{5, []string{"file10", "file1200", "file2300", "file2400", "file2450"}, 3},
+		{100, []string{"file10", "file1200", "file2300", "file2400", "file2450"}, 3},
+		{0, []string{"file10", "file1200", "file2300", "file2400", "file2450"}, 3},
+	}
+
+	for _, c := range cases {
+		d, fake := newFakeDrive(t, files...)
+
+		out := &bytes.Buffer{}
+		err := d.List(ListFilesArgs{
+			Out:         out,
+			MaxFiles:    c.maxFiles,
+			HideFolders: true,
+			UseCsv:      true,
+			SkipHeader:  true,
+			Columns:     []string{"id"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ids := strings.Fields(out.String())
+		if !reflect.DeepEqual(ids, c.ids) {
+			t.Errorf("max files %d: got %v, want %v", c.maxFiles, ids, c.ids)
+		}
+
+		requests := fake.requestsTo("files")
+		if len(requests) != c.requests {
+			t.Errorf("max files %d: got %d requests, want %d", c.maxFiles, len(requests), c.requests)
+		}
+		for _, u := range requests {
+			if pageSize := u.Query().Get("pageSize"); pageSize != "1000" {
+				t.Errorf("max files %d: got page size %s, want full pages", c.maxFiles, pageSize)
+			}
+		}
+	}
+}
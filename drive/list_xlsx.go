@@ -0,0 +1,147 @@
+package drive
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"google.golang.org/api/drive/v3"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Columns written as dates instead of text
+var xlsxTimeColumns = map[string]func(*drive.File) string{
+	"created":      func(f *drive.File) string { return f.CreatedTime },
+	"modified":     func(f *drive.File) string { return f.ModifiedTime },
+	"modifiedbyme": func(f *drive.File) string { return f.ModifiedByMeTime },
+	"viewed":       func(f *drive.File) string { return f.ViewedByMeTime },
+}
+
+// Indexes of the cell styles in xl/styles.xml
+const (
+	xlsxHeaderStyle = 1
+	xlsxDateStyle   = 2
+)
+
+// Workbook parts other than the sheet, the client library has no xlsx writer
+// so the minimal parts of a workbook with a single sheet are written here
+var xlsxParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/><Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/></Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Files" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/></Relationships>`},
+	{"xl/styles.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy-mm-dd hh:mm:ss"/></numFmts><fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts><fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills><borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders><cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs><cellXfs count="3"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/><xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/></cellXfs></styleSheet>`},
+}
+
+// Writes the listed files as a workbook with a header row. Sizes are written
+// as numbers in bytes and times as dates, all other columns as text
+func printXlsxFileList(out io.Writer, args PrintFileListArgs) error {
+	columns := printFileColumns(args)
+
+	// Names are never truncated in a workbook
+	args.NameWidth = 0
+
+	zw := zip.NewWriter(out)
+
+	for _, part := range xlsxParts {
+		w, err := zw.Create(part.name)
+		if err != nil {
+			return wrapError("Failed to write xlsx", err)
+		}
+		if _, err := io.WriteString(w, part.content); err != nil {
+			return wrapError("Failed to write xlsx", err)
+		}
+	}
+
+	w, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return wrapError("Failed to write xlsx", err)
+	}
+
+	sheet := &strings.Builder{}
+	sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	sheet.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	sheet.WriteString(`<row r="1">`)
+	for i, header := range fileColumnHeaders(columns) {
+		writeXlsxString(sheet, i, 1, header, xlsxHeaderStyle)
+	}
+	sheet.WriteString(`</row>`)
+
+	for n, f := range args.Files {
+		row := n + 2
+		fmt.Fprintf(sheet, `<row r="%d">`, row)
+		for i, column := range columns {
+			writeXlsxCell(sheet, i, row, f, column, args)
+		}
+		sheet.WriteString(`</row>`)
+	}
+
+	sheet.WriteString(`</sheetData></worksheet>`)
+
+	if _, err := io.WriteString(w, sheet.String()); err != nil {
+		return wrapError("Failed to write xlsx", err)
+	}
+
+	if err := zw.Close(); err != nil {
+		return wrapError("Failed to write xlsx", err)
+	}
+	return nil
+}
+
+// Directories have no size and are written like the other columns, as text
+func writeXlsxCell(sheet *strings.Builder, col, row int, f *drive.File, column fileColumn, args PrintFileListArgs) {
+	if column.name == "size" && !isDir(f) {
+		fmt.Fprintf(sheet, `<c r="%s"><v>%d</v></c>`, xlsxCellRef(col, row), f.Size)
+		return
+	}
+
+	if value, ok := xlsxTimeColumns[column.name]; ok {
+		if t, err := time.Parse(time.RFC3339, value(f)); err == nil {
+			fmt.Fprintf(sheet, `<c r="%s" s="%d"><v>%s</v></c>`, xlsxCellRef(col, row), xlsxDateStyle, xlsxDate(t))
+			return
+		}
+	}
+
+	// Empty cells are left out
+	if value := column.value(f, args); value != "" {
+		writeXlsxString(sheet, col, row, value, 0)
+	}
+}
+
+func writeXlsxString(sheet *strings.Builder, col, row int, value string, style int) {
+	fmt.Fprintf(sheet, `<c r="%s" t="inlineStr"`, xlsxCellRef(col, row))
+	if style > 0 {
+		fmt.Fprintf(sheet, ` s="%d"`, style)
+	}
+	sheet.WriteString(`><is><t xml:space="preserve">`)
+	xml.EscapeText(sheet, []byte(value))
+	sheet.WriteString(`</t></is></c>`)
+}
+
+// Returns the reference of the cell, i.e. A1 or AB12, the column is zero based
+func xlsxCellRef(col, row int) string {
+	name := ""
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = string(rune('A'+(col-1)%26)) + name
+	}
+	return name + strconv.Itoa(row)
+}
+
+// Spreadsheet dates are the number of days since 1899-12-30 in local time
+func xlsxDate(t time.Time) string {
+	t = t.Local()
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	return strconv.FormatFloat(wall.Sub(epoch).Hours()/24, 'f', -1, 64)
+}
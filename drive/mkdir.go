@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"google.golang.org/api/drive/v3"
 	"io"
+	"regexp"
+	"strings"
 )
 
 const DirectoryMimeType = "application/vnd.google-apps.folder"
@@ -13,9 +15,45 @@ type MkdirArgs struct {
 	Name        string
 	Description string
 	Parents     []string
+	ColorRgb    string
+	NoDuplicate bool
+	// Only print the directory that would be created
+	DryRun bool
 }
 
 func (self *Drive) Mkdir(args MkdirArgs) error {
+	// Parents starting with / are paths
+	var err error
+	args.Parents, err = self.resolvePathsToIds(args.Parents)
+	if err != nil {
+		return err
+	}
+
+	if args.ColorRgb != "" {
+		color, err := parseColorRgb(args.ColorRgb)
+		if err != nil {
+			return err
+		}
+		args.ColorRgb = color
+	}
+
+	if args.NoDuplicate {
+		existing, err := self.findDirectory(args.Name, args.Parents)
+		if err != nil {
+			return err
+		}
+
+		if existing != nil {
+			fmt.Fprintf(args.Out, "Directory %s already exists\n", existing.Id)
+			return nil
+		}
+	}
+
+	if args.DryRun {
+		printDryRun(args.Out, "create directory '%s' in %s", args.Name, dryRunParents(args.Parents))
+		return nil
+	}
+
 	f, err := self.mkdir(args)
 	if err != nil {
 		return err
@@ -26,9 +64,10 @@ func (self *Drive) Mkdir(args MkdirArgs) error {
 
 func (self *Drive) mkdir(args MkdirArgs) (*drive.File, error) {
 	dstFile := &drive.File{
-		Name:        args.Name,
-		Description: args.Description,
-		MimeType:    DirectoryMimeType,
+		Name:           args.Name,
+		Description:    args.Description,
+		MimeType:       DirectoryMimeType,
+		FolderColorRgb: args.ColorRgb,
 	}
 
 	// Set parent folders
@@ -37,8 +76,49 @@ func (self *Drive) mkdir(args MkdirArgs) (*drive.File, error) {
 	// Create directory
 	f, err := self.service.Files.Create(dstFile).Do()
 	if err != nil {
-		return nil, fmt.Errorf("Failed to create directory: %s", err)
+		return nil, wrapError("Failed to create directory", err)
 	}
 
 	return f, nil
 }
+
+// Returns the directory with the given name in the first parent, or root
+// if no parents are given. Returns nil if the directory does not exist
+// and an error if there are several directories with the same name
+func (self *Drive) findDirectory(name string, parents []string) (*drive.File, error) {
+	parent := "root"
+	if len(parents) > 0 {
+		parent = parents[0]
+	}
+
+	query := fmt.Sprintf("name = '%s' and mimeType = '%s' and '%s' in parents and trashed = false", escapeQueryValue(name), DirectoryMimeType, escapeQueryValue(parent))
+
+	fileList, err := self.service.Files.List().Q(query).Fields("files(id,name)").Do()
+	if err != nil {
+		return nil, wrapError("Failed to list directories", err)
+	}
+
+	switch len(fileList.Files) {
+	case 0:
+		return nil, nil
+	case 1:
+		return fileList.Files[0], nil
+	}
+
+	var ids []string
+	for _, f := range fileList.Files {
+		ids = append(ids, f.Id)
+	}
+	return nil, fmt.Errorf("Ambiguous directory name '%s', found %d directories: %s", name, len(ids), formatList(ids))
+}
+
+var colorRgbPattern = regexp.MustCompile(`^#?[0-9a-fA-F]{6}$`)
+
+// Returns the color as a hex string in the #rrggbb format used by drive,
+// the leading # is optional in the given color
+func parseColorRgb(color string) (string, error) {
+	if !colorRgbPattern.MatchString(color) {
+		return "", fmt.Errorf("Invalid color '%s', expected a hex rgb color like #4986e7", color)
+	}
+	return "#" + strings.ToLower(strings.TrimPrefix(color, "#")), nil
+}
@@ -0,0 +1,173 @@
+package drive
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"google.golang.org/api/googleapi"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Reasons the api gives for disallowed moves to or from shared drives
+var moveErrorReasons = map[string]string{
+	"teamDrivesFolderMoveInNotSupported":  "folders can not be moved into a shared drive, move the files inside it instead",
+	"teamDrivesParentLimit":               "files in a shared drive can only have a single parent",
+	"teamDriveHierarchyTooDeep":           "the folder hierarchy of the shared drive would be too deep",
+	"cannotMoveTrashedItemIntoTeamDrive":  "trashed files can not be moved into a shared drive",
+	"cannotMoveTrashedItemOutOfTeamDrive": "trashed files can not be moved out of a shared drive",
+	"teamDrivesShortcutFileNotSupported":  "shortcuts can not be moved into a shared drive",
+	"teamDriveFileLimitExceeded":          "the shared drive has reached its file limit",
+	"fileOwnerNotMemberOfTeamDrive":       "the owner of the file is not a member of the shared drive",
+	"fileWriterTeamDriveMoveInDisabled":   "the domain does not allow writers to move files into a shared drive",
+	"crossDomainMoveRestriction":          "files can not be moved to a shared drive of another domain",
+	"teamDriveMembershipRequired":         "you must be a member of the shared drive",
+	"insufficientFilePermissions":         "you are not allowed to move the file",
+}
+
+type MoveArgs struct {
+	Out    io.Writer
+	Id     string
+	Parent string
+	// Only print where the file would be moved
+	DryRun bool
+}
+
+// File fields needed for moving, the client library in use does not know the driveId
+type moveFile struct {
+	Id       string   `json:"id"`
+	Name     string   `json:"name"`
+	MimeType string   `json:"mimeType"`
+	Parents  []string `json:"parents"`
+	DriveId  string   `json:"driveId"`
+}
+
+// Moves the file from all its current parents to the given parent, which may be
+// in My Drive or a shared drive. The drive of the file is read back after the move
+func (self *Drive) Move(args MoveArgs) error {
+	id, err := self.resolvePathToId(args.Id)
+	if err != nil {
+		return err
+	}
+
+	parentId, err := self.resolvePathToId(args.Parent)
+	if err != nil {
+		return err
+	}
+
+	parent, err := self.getMoveFile(parentId)
+	if err != nil {
+		return wrapError("Failed to get destination", err)
+	}
+
+	if parent.MimeType != DirectoryMimeType {
+		return fmt.Errorf("Destination '%s' is not a directory", parent.Name)
+	}
+
+	if args.DryRun {
+		f, err := self.getMoveFile(id)
+		if err != nil {
+			return wrapError("Failed to get file", err)
+		}
+		printDryRun(args.Out, "move '%s' (%s) from %s to '%s' (%s) in %s", f.Name, f.Id, dryRunParents(f.Parents), parent.Name, parent.Id, driveName(parent.DriveId))
+		return nil
+	}
+
+	f, err := self.moveToParent(id, parent.Id)
+	if err != nil {
+		return err
+	}
+
+	// Verify that the file ended up in the drive of the destination
+	f, err = self.getMoveFile(f.Id)
+	if err != nil {
+		return wrapError("Failed to get moved file", err)
+	}
+
+	if f.DriveId != parent.DriveId {
+		return fmt.Errorf("Moved '%s' but it is in %s, expected %s", f.Name, driveName(f.DriveId), driveName(parent.DriveId))
+	}
+
+	fmt.Fprintf(args.Out, "Moved '%s' to '%s' in %s\n", f.Name, parent.Name, driveName(f.DriveId))
+	return nil
+}
+
+// Files in shared drives are only found and updated with supportsAllDrives
+func (self *Drive) moveToParent(id, parentId string) (*moveFile, error) {
+	f, err := self.getMoveFile(id)
+	if err != nil {
+		return nil, wrapError("Failed to get file", err)
+	}
+
+	params := url.Values{}
+	params.Set("supportsAllDrives", "true")
+	params.Set("fields", "id,name,mimeType,parents,driveId")
+	params.Set("addParents", parentId)
+	if len(f.Parents) > 0 {
+		params.Set("removeParents", strings.Join(f.Parents, ","))
+	}
+
+	moved := &moveFile{}
+	err = self.doMoveRequest("PATCH", id, params, bytes.NewBufferString("{}"), moved)
+	if err != nil {
+		return nil, moveError(err)
+	}
+
+	return moved, nil
+}
+
+func (self *Drive) getMoveFile(id string) (*moveFile, error) {
+	params := url.Values{}
+	params.Set("supportsAllDrives", "true")
+	params.Set("fields", "id,name,mimeType,parents,driveId")
+
+	f := &moveFile{}
+	if err := self.doMoveRequest("GET", id, params, nil, f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (self *Drive) doMoveRequest(method, id string, params url.Values, body io.Reader, v interface{}) error {
+	urls := googleapi.ResolveRelative(self.service.BasePath, "files/{fileId}") + "?" + params.Encode()
+	req, _ := http.NewRequest(method, urls, body)
+	googleapi.Expand(req.URL, map[string]string{
+		"fileId": id,
+	})
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := self.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if err := googleapi.CheckResponse(res); err != nil {
+		return err
+	}
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+// Disallowed moves are explained by their reason when known
+func moveError(err error) error {
+	if ae, ok := apiError(err); ok {
+		for _, item := range ae.Errors {
+			if msg, ok := moveErrorReasons[item.Reason]; ok {
+				return wrapError("Failed to move file, "+msg, err)
+			}
+		}
+	}
+	return wrapError("Failed to move file", err)
+}
+
+func driveName(driveId string) string {
+	if driveId == "" {
+		return "My Drive"
+	}
+	return fmt.Sprintf("shared drive %s", driveId)
+}
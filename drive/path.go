@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"google.golang.org/api/drive/v3"
 	"path/filepath"
+	"strings"
 )
 
 func (self *Drive) newPathfinder() *remotePathfinder {
@@ -55,7 +56,7 @@ func (self *remotePathfinder) getParent(id string) (*drive.File, error) {
 	// Fetch file from drive
 	f, err := self.service.Get(id).Fields("id", "name", "parents").Do()
 	if err != nil {
-		return nil, fmt.Errorf("Failed to get file: %s", err)
+		return nil, wrapError("Failed to get file", err)
 	}
 
 	// Save in cache
@@ -63,3 +64,104 @@ func (self *remotePathfinder) getParent(id string) (*drive.File, error) {
 
 	return f, nil
 }
+
+// Tokens accepted in place of an id, either alone or as the first element
+// of a path, i.e. me/photos or shared/Reports. Ids never contain a /
+const (
+	rootToken   = "root"
+	meToken     = "me"
+	sharedToken = "shared"
+)
+
+// Returns the id of the file at the absolute path, i.e. /photos/2020, or the
+// path below one of the tokens. Other values are ids and are returned as is.
+// The shared token is the virtual folder of files shared with me, which only
+// files below it resolve to
+func (self *Drive) resolvePathToId(path string) (string, error) {
+	id, names, ok := splitPathBase(path)
+	if !ok {
+		return path, nil
+	}
+
+	for _, name := range names {
+		query := fmt.Sprintf("name = '%s' and '%s' in parents and trashed = false", escapeQueryValue(name), id)
+		if id == sharedToken {
+			query = fmt.Sprintf("name = '%s' and sharedWithMe = true and trashed = false", escapeQueryValue(name))
+		}
+
+		fileList, err := self.service.Files.List().Q(query).Fields("files(id)").Do()
+		if err != nil {
+			return "", wrapError("Failed to resolve path", err)
+		}
+
+		switch len(fileList.Files) {
+		case 0:
+			return "", &PathNotFoundError{path}
+		case 1:
+			id = fileList.Files[0].Id
+			continue
+		}
+
+		var ids []string
+		for _, f := range fileList.Files {
+			ids = append(ids, f.Id)
+		}
+		return "", fmt.Errorf("Ambiguous path '%s', found %d files named '%s': %s", path, len(ids), name, formatList(ids))
+	}
+
+	if id == sharedToken {
+		return "", fmt.Errorf("'%s' is the virtual folder of files shared with you, it can only be listed", sharedToken)
+	}
+
+	return id, nil
+}
+
+// Splits the path into the id it starts from and the names below it,
+// ok is false if the path is an id. Me is the same as the root
+func splitPathBase(path string) (base string, names []string, ok bool) {
+	if path == "" {
+		return "", nil, false
+	}
+
+	first, rest := path, ""
+	if i := strings.Index(path, "/"); i >= 0 {
+		first, rest = path[:i], path[i:]
+	}
+
+	switch first {
+	case "", rootToken, meToken:
+		base = rootToken
+	case sharedToken:
+		base = sharedToken
+	default:
+		return "", nil, false
+	}
+
+	for _, name := range strings.Split(rest, "/") {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return base, names, true
+}
+
+// Listing the shared token lists the files shared with me
+func listSharedParent(args ListFilesArgs) ListFilesArgs {
+	if args.Parent == sharedToken {
+		args.Parent = ""
+		args.SharedWithMe = true
+	}
+	return args
+}
+
+func (self *Drive) resolvePathsToIds(paths []string) ([]string, error) {
+	var ids []string
+	for _, path := range paths {
+		id, err := self.resolvePathToId(path)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -10,16 +10,17 @@ import (
 const MaxDrawInterval = time.Second * 1
 const MaxRateInterval = time.Second * 3
 
-func getProgressReader(r io.Reader, w io.Writer, size int64) io.Reader {
+func getProgressReader(r io.Reader, w io.Writer, size int64, decimalUnits bool) io.Reader {
 	// Don't wrap reader if output is discarded or size is too small
 	if w == ioutil.Discard || (size > 0 && size < 1024*1024) {
 		return r
 	}
 
 	return &Progress{
-		Reader: r,
-		Writer: w,
-		Size:   size,
+		Reader:       r,
+		Writer:       w,
+		Size:         size,
+		DecimalUnits: decimalUnits,
 	}
 }
 
@@ -27,6 +28,7 @@ type Progress struct {
 	Writer       io.Writer
 	Reader       io.Reader
 	Size         int64
+	DecimalUnits bool
 	progress     int64
 	rate         int64
 	rateProgress int64
@@ -79,16 +81,16 @@ func (self *Progress) draw(isLast bool) {
 	self.clear()
 
 	// Print progress
-	fmt.Fprintf(self.Writer, "%s", formatSize(self.progress, false))
+	fmt.Fprintf(self.Writer, "%s", formatSize(self.progress, false, self.DecimalUnits))
 
 	// Print total size
 	if self.Size > 0 {
-		fmt.Fprintf(self.Writer, "/%s", formatSize(self.Size, false))
+		fmt.Fprintf(self.Writer, "/%s", formatSize(self.Size, false, self.DecimalUnits))
 	}
 
 	// Print rate
 	if self.rate > 0 {
-		fmt.Fprintf(self.Writer, ", Rate: %s/s", formatSize(self.rate, false))
+		fmt.Fprintf(self.Writer, ", Rate: %s/s", formatSize(self.rate, false, self.DecimalUnits))
 	}
 
 	if isLast {
@@ -0,0 +1,124 @@
+package drive
+
+import (
+	"fmt"
+	"google.golang.org/api/googleapi"
+	"strings"
+)
+
+// Projection selects the file fields requested when listing files.
+// The id is always requested
+type Projection struct {
+	Name             bool
+	MimeType         bool
+	Size             bool
+	Md5Checksum      bool
+	CreatedTime      bool
+	ModifiedTime     bool
+	ModifiedByMeTime bool
+	ViewedByMeTime   bool
+	Parents          bool
+	HeadRevisionId   bool
+	SharingUser      bool
+	Owners           bool
+}
+
+// Fields listed by default, which are the fields included in the json output
+var listFileProjection = Projection{
+	Name:           true,
+	MimeType:       true,
+	Size:           true,
+	Md5Checksum:    true,
+	CreatedTime:    true,
+	ModifiedTime:   true,
+	Parents:        true,
+	HeadRevisionId: true,
+	SharingUser:    true,
+}
+
+// Returns a projection with the fields of both projections
+func (self Projection) Union(other Projection) Projection {
+	return Projection{
+		Name:             self.Name || other.Name,
+		MimeType:         self.MimeType || other.MimeType,
+		Size:             self.Size || other.Size,
+		Md5Checksum:      self.Md5Checksum || other.Md5Checksum,
+		CreatedTime:      self.CreatedTime || other.CreatedTime,
+		ModifiedTime:     self.ModifiedTime || other.ModifiedTime,
+		ModifiedByMeTime: self.ModifiedByMeTime || other.ModifiedByMeTime,
+		ViewedByMeTime:   self.ViewedByMeTime || other.ViewedByMeTime,
+		Parents:          self.Parents || other.Parents,
+		HeadRevisionId:   self.HeadRevisionId || other.HeadRevisionId,
+		SharingUser:      self.SharingUser || other.SharingUser,
+		Owners:           self.Owners || other.Owners,
+	}
+}
+
+// Returns the fields for a files.list call, including the next page token
+func (self Projection) Fields() []googleapi.Field {
+	fields := []string{"id"}
+
+	if self.Name {
+		fields = append(fields, "name")
+	}
+	if self.Md5Checksum {
+		fields = append(fields, "md5Checksum")
+	}
+	if self.MimeType {
+		fields = append(fields, "mimeType")
+	}
+	if self.Size {
+		fields = append(fields, "size")
+	}
+	if self.CreatedTime {
+		fields = append(fields, "createdTime")
+	}
+	if self.ModifiedTime {
+		fields = append(fields, "modifiedTime")
+	}
+	if self.Parents {
+		fields = append(fields, "parents")
+	}
+	if self.HeadRevisionId {
+		fields = append(fields, "headRevisionId")
+	}
+	if self.SharingUser {
+		fields = append(fields, "sharingUser(displayName, emailAddress)")
+	}
+	if self.ModifiedByMeTime {
+		fields = append(fields, "modifiedByMeTime")
+	}
+	if self.ViewedByMeTime {
+		fields = append(fields, "viewedByMeTime")
+	}
+	if self.Owners {
+		fields = append(fields, "owners(emailAddress)")
+	}
+
+	return []googleapi.Field{"nextPageToken", googleapi.Field(fmt.Sprintf("files(%s)", strings.Join(fields, ", ")))}
+}
+
+// The default fields with the fields needed by the filters and columns
+func listProjection(args ListFilesArgs, columns []fileColumn) Projection {
+	return listFileProjection.Union(filterProjection(args)).Union(columnsProjection(columns))
+}
+
+// Fields used by the client side filters, sorting and marking after listing
+func filterProjection(args ListFilesArgs) Projection {
+	return Projection{
+		MimeType:         args.HideFolders || args.FoldersFirst,
+		Md5Checksum:      args.MarkDuplicates,
+		ModifiedByMeTime: args.ModifiedByMe,
+		ViewedByMeTime:   args.ViewedAfter != "",
+		Parents:          args.AbsPath,
+	}
+}
+
+// Fields needed to print the columns
+func columnsProjection(columns []fileColumn) Projection {
+	projection := Projection{}
+	for _, column := range columns {
+		projection = projection.Union(column.fields)
+	}
+	return projection
+}
@@ -0,0 +1,240 @@
+package drive
+
+import (
+	"encoding/json"
+	"fmt"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// Google documents that can be published to the web, by the path of their published url.
+// Other files are published by giving anyone with the link read access
+var publishedUrlPaths = map[string]string{
+	"application/vnd.google-apps.document":     "document/d/%s/pub",
+	"application/vnd.google-apps.spreadsheet":  "spreadsheets/d/%s/pubhtml",
+	"application/vnd.google-apps.presentation": "presentation/d/%s/pub",
+	"application/vnd.google-apps.drawing":      "drawings/d/%s/pub",
+}
+
+const publishedUrlBase = "https://docs.google.com/"
+
+type PublishArgs struct {
+	Out io.Writer
+	Id  string
+}
+
+// Permission including the view it applies to, which the client library in use does not know
+type viewPermission struct {
+	Id   string `json:"id"`
+	Type string `json:"type"`
+	Role string `json:"role"`
+	View string `json:"view"`
+}
+
+func (self *Drive) Publish(args PublishArgs) error {
+	f, err := self.service.Files.Get(args.Id).Fields("id", "name", "mimeType", "webViewLink").Do()
+	if err != nil {
+		return wrapError("Failed to get file", err)
+	}
+
+	if !isPublishableDocument(f) {
+		if err := self.shareAnyoneReader(f.Id); err != nil {
+			return err
+		}
+		fmt.Fprintf(args.Out, "Published '%s', anyone with the link can view it\n", f.Name)
+		fmt.Fprintf(args.Out, "PublishedUrl: %s\n", f.WebViewLink)
+		return nil
+	}
+
+	err = self.setPublished(f.Id, &drive.Revision{
+		Published:              true,
+		PublishAuto:            true,
+		PublishedOutsideDomain: true,
+	})
+	if err != nil {
+		return wrapError("Failed to publish file", err)
+	}
+
+	fmt.Fprintf(args.Out, "Published '%s' to the web\n", f.Name)
+	fmt.Fprintf(args.Out, "PublishedUrl: %s\n", publishedUrl(f))
+	return nil
+}
+
+// Unpublishes google documents, anyone permissions are removed from other files
+func (self *Drive) Unpublish(args PublishArgs) error {
+	f, err := self.service.Files.Get(args.Id).Fields("id", "name", "mimeType").Do()
+	if err != nil {
+		return wrapError("Failed to get file", err)
+	}
+
+	if isPublishableDocument(f) {
+		err = self.setPublished(f.Id, &drive.Revision{
+			Published:       false,
+			ForceSendFields: []string{"Published"},
+		})
+		if err != nil {
+			return wrapError("Failed to unpublish file", err)
+		}
+
+		fmt.Fprintf(args.Out, "Unpublished '%s'\n", f.Name)
+		return nil
+	}
+
+	permList, err := self.service.Permissions.List(f.Id).Fields("permissions(id,type)").Do()
+	if err != nil {
+		return wrapError("Failed to list permissions", err)
+	}
+
+	removed := 0
+	for _, p := range permList.Permissions {
+		if p.Type != "anyone" {
+			continue
+		}
+
+		if err := self.service.Permissions.Delete(f.Id, p.Id).Do(); err != nil {
+			return wrapError("Failed to revoke permission", err)
+		}
+		removed++
+	}
+
+	if removed == 0 {
+		fmt.Fprintf(args.Out, "'%s' is not published\n", f.Name)
+		return nil
+	}
+
+	fmt.Fprintf(args.Out, "Unpublished '%s', removed %d anyone permissions\n", f.Name, removed)
+	return nil
+}
+
+// Documents are published per revision, publishing the head revision with
+// publishAuto keeps the published version up to date with later edits
+func (self *Drive) setPublished(id string, rev *drive.Revision) error {
+	head, err := self.headRevision(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = self.service.Revisions.Update(id, head.Id, rev).Fields("id", "published").Do()
+	return err
+}
+
+// The head revision id of files is only given for binary files,
+// so all revisions of the document are listed instead
+func (self *Drive) headRevision(id string) (*drive.Revision, error) {
+	revisions, err := self.listAllRevisions(id, "id,published,publishAuto,publishedOutsideDomain")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(revisions) == 0 {
+		return nil, fmt.Errorf("File %s has no revisions", id)
+	}
+
+	// Revisions are listed oldest first
+	return revisions[len(revisions)-1], nil
+}
+
+// Prints whether the file is published and the permissions given to anyone or
+// for the published view, which are requested with includePermissionsForView
+func (self *Drive) printPublished(out io.Writer, f *drive.File) error {
+	permissions, err := self.listViewPermissions(f.Id)
+	if err != nil {
+		return err
+	}
+
+	published := false
+	publicUrl := f.WebViewLink
+	var public []string
+
+	for _, p := range permissions {
+		if p.Type != "anyone" && p.View != "published" {
+			continue
+		}
+
+		published = true
+		if p.View != "" {
+			public = append(public, fmt.Sprintf("%s:%s (%s view)", p.Type, p.Role, p.View))
+		} else {
+			public = append(public, fmt.Sprintf("%s:%s", p.Type, p.Role))
+		}
+	}
+
+	items := []kv{
+		kv{"Id", f.Id},
+		kv{"Name", f.Name},
+	}
+
+	if isPublishableDocument(f) {
+		head, err := self.headRevision(f.Id)
+		if err != nil {
+			return err
+		}
+
+		published = head.Published
+		publicUrl = publishedUrl(f)
+		items = append(items,
+			kv{"PublishAuto", formatBool(head.PublishAuto)},
+			kv{"PublishedOutsideDomain", formatBool(head.PublishedOutsideDomain)},
+		)
+	}
+
+	items = append(items,
+		kv{"Published", formatBool(published)},
+		kv{"PublicPermissions", formatList(public)},
+	)
+
+	if published {
+		items = append(items, kv{"PublishedUrl", publicUrl})
+	}
+
+	for _, item := range items {
+		if item.value != "" {
+			fmt.Fprintf(out, "%s: %s\n", item.key, item.value)
+		}
+	}
+
+	return nil
+}
+
+func (self *Drive) listViewPermissions(id string) ([]*viewPermission, error) {
+	params := url.Values{}
+	params.Set("fields", "permissions(id,type,role,view)")
+	params.Set("includePermissionsForView", "published")
+
+	urls := googleapi.ResolveRelative(self.service.BasePath, "files/{fileId}") + "?" + params.Encode()
+	req, _ := http.NewRequest("GET", urls, nil)
+	googleapi.Expand(req.URL, map[string]string{
+		"fileId": id,
+	})
+
+	res, err := self.client.Do(req)
+	if err != nil {
+		return nil, wrapError("Failed to get permissions", err)
+	}
+	defer res.Body.Close()
+
+	if err := googleapi.CheckResponse(res); err != nil {
+		return nil, wrapError("Failed to get permissions", err)
+	}
+
+	f := &struct {
+		Permissions []*viewPermission `json:"permissions"`
+	}{}
+	if err := json.NewDecoder(res.Body).Decode(f); err != nil {
+		return nil, wrapError("Failed to get permissions", err)
+	}
+
+	return f.Permissions, nil
+}
+
+func isPublishableDocument(f *drive.File) bool {
+	_, ok := publishedUrlPaths[f.MimeType]
+	return ok
+}
+
+func publishedUrl(f *drive.File) string {
+	return publishedUrlBase + fmt.Sprintf(publishedUrlPaths[f.MimeType], f.Id)
+}
@@ -14,7 +14,7 @@ type DeleteRevisionArgs struct {
 func (self *Drive) DeleteRevision(args DeleteRevisionArgs) (err error) {
 	rev, err := self.service.Revisions.Get(args.FileId, args.RevisionId).Fields("originalFilename").Do()
 	if err != nil {
-		return fmt.Errorf("Failed to get revision: %s", err)
+		return wrapError("Failed to get revision", err)
 	}
 
 	if rev.OriginalFilename == "" {
@@ -23,7 +23,7 @@ func (self *Drive) DeleteRevision(args DeleteRevisionArgs) (err error) {
 
 	err = self.service.Revisions.Delete(args.FileId, args.RevisionId).Do()
 	if err != nil {
-		return fmt.Errorf("Failed to delete revision", err)
+		return wrapError("Failed to delete revision", err)
 	}
 
 	fmt.Fprintf(args.Out, "Deleted revision '%s'\n", args.RevisionId)
@@ -9,14 +9,15 @@ import (
 )
 
 type DownloadRevisionArgs struct {
-	Out        io.Writer
-	Progress   io.Writer
-	FileId     string
-	RevisionId string
-	Path       string
-	Force      bool
-	Stdout     bool
-	Timeout    time.Duration
+	Out          io.Writer
+	Progress     io.Writer
+	FileId       string
+	RevisionId   string
+	Path         string
+	Force        bool
+	Stdout       bool
+	Timeout      time.Duration
+	DecimalUnits bool
 }
 
 func (self *Drive) DownloadRevision(args DownloadRevisionArgs) (err error) {
@@ -24,7 +25,7 @@ func (self *Drive) DownloadRevision(args DownloadRevisionArgs) (err error) {
 
 	rev, err := getRev.Fields("originalFilename").Do()
 	if err != nil {
-		return fmt.Errorf("Failed to get file: %s", err)
+		return wrapError("Failed to get file", err)
 	}
 
 	if rev.OriginalFilename == "" {
@@ -39,7 +40,10 @@ func (self *Drive) DownloadRevision(args DownloadRevisionArgs) (err error) {
 		if isTimeoutError(err) {
 			return fmt.Errorf("Failed to download file: timeout, no data was transferred for %v", args.Timeout)
 		}
-		return fmt.Errorf("Failed to download file: %s", err)
+		if isRequestTimeoutError(err) {
+			return fmt.Errorf("Failed to download file: timeout, no response within the http timeout")
+		}
+		return wrapError("Failed to download file", err)
 	}
 
 	// Close body on function exit
@@ -64,12 +68,13 @@ func (self *Drive) DownloadRevision(args DownloadRevisionArgs) (err error) {
 		force:         args.Force,
 		stdout:        args.Stdout,
 		progress:      args.Progress,
+		decimalUnits:  args.DecimalUnits,
 	})
 
 	if err != nil {
 		return err
 	}
 
-	fmt.Fprintf(out, "Download complete, rate: %s/s, total size: %s\n", formatSize(rate, false), formatSize(bytes, false))
+	fmt.Fprintf(out, "Download complete, rate: %s/s, total size: %s\n", formatSize(rate, false, args.DecimalUnits), formatSize(bytes, false, args.DecimalUnits))
 	return nil
 }
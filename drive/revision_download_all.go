@@ -0,0 +1,156 @@
+package drive
+
+import (
+	"encoding/json"
+	"fmt"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type DownloadAllRevisionsArgs struct {
+	Out          io.Writer
+	Progress     io.Writer
+	FileId       string
+	Path         string
+	Force        bool
+	Timeout      time.Duration
+	DecimalUnits bool
+}
+
+// Downloads every revision of the file into a directory named after the file,
+// each revision is saved as <index>-<modifiedTime>.<ext>
+func (self *Drive) DownloadAllRevisions(args DownloadAllRevisionsArgs) error {
+	f, err := self.service.Files.Get(args.FileId).Fields("id", "name", "mimeType", "md5Checksum").Do()
+	if err != nil {
+		return wrapError("Failed to get file", err)
+	}
+
+	if !isBinary(f) {
+		return fmt.Errorf("Downloading revisions is not supported for directories and google documents")
+	}
+
+	revisions, err := self.listAllRevisions(args.FileId, "id,size,modifiedTime")
+	if err != nil {
+		return err
+	}
+
+	dirPath := filepath.Join(args.Path, f.Name)
+	if err := os.MkdirAll(dirPath, 0775); err != nil {
+		return wrapError("Failed to create directory", err)
+	}
+
+	var total int64
+
+	for i, rev := range revisions {
+		fpath := filepath.Join(dirPath, revisionFilename(i+1, rev, f.Name))
+
+		bytes, err := self.downloadRevisionTo(rev, fpath, args)
+		if err != nil {
+			return err
+		}
+
+		total += bytes
+	}
+
+	fmt.Fprintf(args.Out, "Downloaded %d revisions, total %s\n", len(revisions), formatSize(total, false, args.DecimalUnits))
+	return nil
+}
+
+// Revision list as returned by the revisions.list call, the client library
+// in use does not support listing more than the first page
+type revisionPage struct {
+	Revisions     []*drive.Revision `json:"revisions"`
+	NextPageToken string            `json:"nextPageToken"`
+}
+
+// Lists the revisions of all pages, oldest first. Fields are the
+// fields of each revision, i.e. id,size
+func (self *Drive) listAllRevisions(fileId string, fields string) ([]*drive.Revision, error) {
+	var revisions []*drive.Revision
+	var pageToken string
+
+	for {
+		params := url.Values{}
+		params.Set("pageSize", "1000")
+		params.Set("fields", fmt.Sprintf("nextPageToken,revisions(%s)", fields))
+		if pageToken != "" {
+			params.Set("pageToken", pageToken)
+		}
+
+		path := fmt.Sprintf("files/%s/revisions", url.PathEscape(fileId))
+		urls := googleapi.ResolveRelative(self.service.BasePath, path) + "?" + params.Encode()
+		req, _ := http.NewRequest("GET", urls, nil)
+
+		res, err := self.client.Do(req)
+		if err != nil {
+			return nil, wrapError("Failed listing revisions", err)
+		}
+
+		page := &revisionPage{}
+		err = googleapi.CheckResponse(res)
+		if err == nil {
+			err = json.NewDecoder(res.Body).Decode(page)
+		}
+		res.Body.Close()
+
+		if err != nil {
+			return nil, wrapError("Failed listing revisions", err)
+		}
+
+		revisions = append(revisions, page.Revisions...)
+
+		if page.NextPageToken == "" {
+			return revisions, nil
+		}
+
+		pageToken = page.NextPageToken
+	}
+}
+
+func (self *Drive) downloadRevisionTo(rev *drive.Revision, fpath string, args DownloadAllRevisionsArgs) (int64, error) {
+	// Get timeout reader wrapper and context
+	timeoutReaderWrapper, ctx := getTimeoutReaderWrapperContext(args.Timeout)
+
+	res, err := self.service.Revisions.Get(args.FileId, rev.Id).Context(ctx).Download()
+	if err != nil {
+		if isTimeoutError(err) {
+			return 0, fmt.Errorf("Failed to download revision: timeout, no data was transferred for %v", args.Timeout)
+		}
+		if isRequestTimeoutError(err) {
+			return 0, fmt.Errorf("Failed to download revision: timeout, no response within the http timeout")
+		}
+		return 0, fmt.Errorf("Failed to download revision %s: %s", rev.Id, err)
+	}
+
+	// Close body on function exit
+	defer res.Body.Close()
+
+	fmt.Fprintf(args.Out, "Downloading revision %s -> %s\n", rev.Id, fpath)
+
+	bytes, _, err := self.saveFile(saveFileArgs{
+		out:           args.Out,
+		body:          timeoutReaderWrapper(res.Body),
+		contentLength: res.ContentLength,
+		fpath:         fpath,
+		force:         args.Force,
+		progress:      args.Progress,
+		decimalUnits:  args.DecimalUnits,
+	})
+
+	return bytes, err
+}
+
+// The modified time is formatted without colons to give valid filenames on all platforms
+func revisionFilename(index int, rev *drive.Revision, name string) string {
+	modified := rev.ModifiedTime
+	if t, err := time.Parse(time.RFC3339, rev.ModifiedTime); err == nil {
+		modified = t.UTC().Format("20060102T150405Z")
+	}
+	return fmt.Sprintf("%d-%s%s", index, modified, filepath.Ext(name))
+}
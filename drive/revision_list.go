@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"google.golang.org/api/drive/v3"
 	"io"
+	"sort"
 	"text/tabwriter"
 )
 
@@ -13,13 +14,29 @@ type ListRevisionsArgs struct {
 	NameWidth   int64
 	SkipHeader  bool
 	SizeInBytes bool
+	DecimalUnits bool
 	UseCsv		bool
+	// Sort by modified or size, revisions are printed in api order by default
+	SortBy string
 }
 
 func (self *Drive) ListRevisions(args ListRevisionsArgs) (err error) {
+	switch args.SortBy {
+	case "", "modified", "size":
+	default:
+		return fmt.Errorf("Invalid sort '%s', must be modified or size", args.SortBy)
+	}
+
 	revList, err := self.service.Revisions.List(args.Id).Fields("revisions(id,keepForever,size,modifiedTime,originalFilename)").Do()
 	if err != nil {
-		return fmt.Errorf("Failed listing revisions: %s", err)
+		return wrapError("Failed listing revisions", err)
+	}
+
+	switch args.SortBy {
+	case "modified":
+		sort.Stable(byRevisionModified(revList.Revisions))
+	case "size":
+		sort.Stable(byRevisionSize(revList.Revisions))
 	}
 
 	PrintRevisionList(PrintRevisionListArgs{
@@ -28,6 +45,7 @@ func (self *Drive) ListRevisions(args ListRevisionsArgs) (err error) {
 		NameWidth:   int(args.NameWidth),
 		SkipHeader:  args.SkipHeader,
 		SizeInBytes: args.SizeInBytes,
+		DecimalUnits: args.DecimalUnits,
 	})
 
 	return
@@ -39,6 +57,7 @@ type PrintRevisionListArgs struct {
 	NameWidth   int
 	SkipHeader  bool
 	SizeInBytes bool
+	DecimalUnits bool
 }
 
 func PrintRevisionList(args PrintRevisionListArgs) {
@@ -53,7 +72,7 @@ func PrintRevisionList(args PrintRevisionListArgs) {
 		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
 			rev.Id,
 			truncateString(rev.OriginalFilename, args.NameWidth),
-			formatSize(rev.Size, args.SizeInBytes),
+			formatSize(rev.Size, args.SizeInBytes, args.DecimalUnits),
 			formatDatetime(rev.ModifiedTime),
 			formatBool(rev.KeepForever),
 		)
@@ -61,3 +80,38 @@ func PrintRevisionList(args PrintRevisionListArgs) {
 
 	w.Flush()
 }
+
+type byRevisionModified []*drive.Revision
+
+func (self byRevisionModified) Len() int {
+	return len(self)
+}
+
+func (self byRevisionModified) Swap(i, j int) {
+	self[i], self[j] = self[j], self[i]
+}
+
+// Ties are broken by id so the order is the same on every run
+func (self byRevisionModified) Less(i, j int) bool {
+	if self[i].ModifiedTime != self[j].ModifiedTime {
+		return self[i].ModifiedTime < self[j].ModifiedTime
+	}
+	return self[i].Id < self[j].Id
+}
+
+type byRevisionSize []*drive.Revision
+
+func (self byRevisionSize) Len() int {
+	return len(self)
+}
+
+func (self byRevisionSize) Swap(i, j int) {
+	self[i], self[j] = self[j], self[i]
+}
+
+func (self byRevisionSize) Less(i, j int) bool {
+	if self[i].Size != self[j].Size {
+		return self[i].Size < self[j].Size
+	}
+	return self[i].Id < self[j].Id
+}
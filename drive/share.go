@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"google.golang.org/api/drive/v3"
 	"io"
+	"sort"
+	"strings"
 	"text/tabwriter"
 )
 
@@ -15,6 +17,10 @@ type ShareArgs struct {
 	Email        string
 	Domain       string
 	Discoverable bool
+	// Only print the link to the file
+	LinkOnly bool
+	// Only print the permission that would be granted
+	DryRun bool
 }
 
 func (self *Drive) Share(args ShareArgs) error {
@@ -26,15 +32,56 @@ func (self *Drive) Share(args ShareArgs) error {
 		Domain:             args.Domain,
 	}
 
-	_, err := self.service.Permissions.Create(args.FileId, permission).Do()
+	if args.DryRun {
+		f, err := self.service.Files.Get(args.FileId).Fields("id", "name").Do()
+		if err != nil {
+			return wrapError("Failed to get file", err)
+		}
+		printDryRun(args.Out, "grant %s permission to %s on '%s' (%s)", args.Role, shareTarget(args), f.Name, f.Id)
+		return nil
+	}
+
+	_, err := self.createPermission(args.FileId, permission, true, 0)
 	if err != nil {
-		return fmt.Errorf("Failed to share file: %s", err)
+		return wrapError("Failed to share file", err)
+	}
+
+	if !args.LinkOnly {
+		fmt.Fprintf(args.Out, "Granted %s permission to %s\n", args.Role, args.Type)
+	}
+
+	// Links are printed when anyone with the link can access the file
+	if args.Type != "anyone" && !args.LinkOnly {
+		return nil
+	}
+
+	f, err := self.service.Files.Get(args.FileId).Fields("webViewLink", "webContentLink").Do()
+	if err != nil {
+		return wrapError("Failed to get file", err)
+	}
+
+	if args.LinkOnly {
+		fmt.Fprintln(args.Out, f.WebViewLink)
+		return nil
 	}
 
-	fmt.Fprintf(args.Out, "Granted %s permission to %s\n", args.Role, args.Type)
+	fmt.Fprintf(args.Out, "ViewUrl: %s\n", f.WebViewLink)
+	if f.WebContentLink != "" {
+		fmt.Fprintf(args.Out, "DownloadUrl: %s\n", f.WebContentLink)
+	}
 	return nil
 }
 
+func shareTarget(args ShareArgs) string {
+	switch {
+	case args.Email != "":
+		return args.Email
+	case args.Domain != "":
+		return args.Domain
+	}
+	return args.Type
+}
+
 type RevokePermissionArgs struct {
 	Out          io.Writer
 	FileId       string
@@ -44,7 +91,7 @@ type RevokePermissionArgs struct {
 func (self *Drive) RevokePermission(args RevokePermissionArgs) error {
 	err := self.service.Permissions.Delete(args.FileId, args.PermissionId).Do()
 	if err != nil {
-		fmt.Errorf("Failed to revoke permission: %s", err)
+		wrapError("Failed to revoke permission", err)
 		return err
 	}
 
@@ -55,13 +102,27 @@ func (self *Drive) RevokePermission(args RevokePermissionArgs) error {
 type ListPermissionsArgs struct {
 	Out    io.Writer
 	FileId string
+	// Sort by role or email, permissions are printed in api order by default
+	SortBy string
 }
 
 func (self *Drive) ListPermissions(args ListPermissionsArgs) error {
+	switch args.SortBy {
+	case "", "role", "email":
+	default:
+		return fmt.Errorf("Invalid sort '%s', must be role or email", args.SortBy)
+	}
+
 	permList, err := self.service.Permissions.List(args.FileId).Fields("permissions(id,role,type,domain,emailAddress,allowFileDiscovery)").Do()
 	if err != nil {
-		fmt.Errorf("Failed to list permissions: %s", err)
-		return err
+		return wrapError("Failed to list permissions", err)
+	}
+
+	switch args.SortBy {
+	case "role":
+		sort.Stable(byPermissionRole(permList.Permissions))
+	case "email":
+		sort.Stable(byPermissionEmail(permList.Permissions))
 	}
 
 	printPermissions(printPermissionsArgs{
@@ -79,7 +140,7 @@ func (self *Drive) shareAnyoneReader(fileId string) error {
 
 	_, err := self.service.Permissions.Create(fileId, permission).Do()
 	if err != nil {
-		return fmt.Errorf("Failed to share file: %s", err)
+		return wrapError("Failed to share file", err)
 	}
 
 	return nil
@@ -109,3 +170,57 @@ func printPermissions(args printPermissionsArgs) {
 
 	w.Flush()
 }
+
+// Roles from most to least privileged
+var permissionRoles = []string{"owner", "organizer", "fileOrganizer", "writer", "commenter", "reader"}
+
+func permissionRoleRank(role string) int {
+	for i, r := range permissionRoles {
+		if r == role {
+			return i
+		}
+	}
+	return len(permissionRoles)
+}
+
+// Most privileged role first, permissions with the same role by email
+type byPermissionRole []*drive.Permission
+
+func (self byPermissionRole) Len() int {
+	return len(self)
+}
+
+func (self byPermissionRole) Swap(i, j int) {
+	self[i], self[j] = self[j], self[i]
+}
+
+func (self byPermissionRole) Less(i, j int) bool {
+	ri, rj := permissionRoleRank(self[i].Role), permissionRoleRank(self[j].Role)
+	if ri != rj {
+		return ri < rj
+	}
+	return byPermissionEmail(self).Less(i, j)
+}
+
+// Permissions without an email, i.e. domain and anyone, are sorted by domain.
+// Remaining ties are broken by id so the order is the same on every run
+type byPermissionEmail []*drive.Permission
+
+func (self byPermissionEmail) Len() int {
+	return len(self)
+}
+
+func (self byPermissionEmail) Swap(i, j int) {
+	self[i], self[j] = self[j], self[i]
+}
+
+func (self byPermissionEmail) Less(i, j int) bool {
+	ei, ej := strings.ToLower(self[i].EmailAddress), strings.ToLower(self[j].EmailAddress)
+	if ei != ej {
+		return ei < ej
+	}
+	if self[i].Domain != self[j].Domain {
+		return self[i].Domain < self[j].Domain
+	}
+	return self[i].Id < self[j].Id
+}
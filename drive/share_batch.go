@@ -0,0 +1,106 @@
+package drive
+
+import (
+	"fmt"
+	"google.golang.org/api/drive/v3"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Drive is strict about the rate of permission changes,
+// so only a few permissions are created at the same time by default
+const defaultShareConcurrency = 2
+
+type ShareBatchArgs struct {
+	Out         io.Writer
+	FileId      string
+	Role        string
+	Type        string
+	Emails      []string
+	Concurrency int
+	Verbose     bool
+}
+
+type shareResult struct {
+	retries int
+	err     error
+}
+
+// Shares the file with each email, failures are reported
+// after all emails have been processed
+func (self *Drive) ShareBatch(args ShareBatchArgs) error {
+	concurrency := args.Concurrency
+	if concurrency < 1 {
+		concurrency = defaultShareConcurrency
+	}
+
+	results := make([]shareResult, len(args.Emails))
+	sem := make(chan struct{}, concurrency)
+	wg := &sync.WaitGroup{}
+
+	for i, email := range args.Emails {
+		wg.Add(1)
+		go func(i int, email string) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			permission := &drive.Permission{
+				Role:         args.Role,
+				Type:         args.Type,
+				EmailAddress: email,
+			}
+			results[i].retries, results[i].err = self.createPermission(args.FileId, permission, true, 0)
+		}(i, email)
+	}
+
+	wg.Wait()
+
+	var failed []string
+
+	for i, result := range results {
+		email := args.Emails[i]
+
+		if result.err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %s", email, result.err))
+		} else {
+			fmt.Fprintf(args.Out, "Granted %s permission to %s\n", args.Role, email)
+		}
+
+		if args.Verbose && result.retries > 0 {
+			fmt.Fprintf(args.Out, "Retried %s %d times\n", email, result.retries)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("Failed to share file with %d of %d emails: %s", len(failed), len(args.Emails), strings.Join(failed, "; "))
+	}
+
+	return nil
+}
+
+// Creates the permission, retrying with exponential backoff on backend and rate limit errors.
+// Returns the number of retries
+func (self *Drive) createPermission(fileId string, permission *drive.Permission, notify bool, try int) (int, error) {
+	call := self.service.Permissions.Create(fileId, permission)
+
+	// Users and groups are notified by email unless turned off
+	if !notify {
+		call = call.SendNotificationEmail(false)
+	}
+
+	_, err := call.Do()
+	if err == nil {
+		return try, nil
+	}
+
+	// Other 403 errors are permission denials, i.e. sharing outside of the domain
+	if (isBackendError(err) || IsRateLimit(err)) && try < MaxErrorRetries {
+		exponentialBackoffSleep(try)
+		return self.createPermission(fileId, permission, notify, try+1)
+	}
+
+	return try, err
+}
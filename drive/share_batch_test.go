@@ -0,0 +1,65 @@
+package drive
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"google.golang.org/api/drive/v3"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Rate limited requests are retried, other 403 errors fail without retrying
+func TestShareBatchRetriesOnlyRateLimits(t *testing.T) {
+	requests := map[string]int{}
+	mutex := sync.Mutex{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		permission := &drive.Permission{}
+		json.NewDecoder(r.Body).Decode(permission)
+
+		mutex.Lock()
+		requests[permission.EmailAddress]++
+		n := requests[permission.EmailAddress]
+		mutex.Unlock()
+
+		switch {
+		case permission.EmailAddress == "outside@example.org":
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"error":{"code":403,"message":"Sharing outside of the domain is not allowed","errors":[{"reason":"forbidden"}]}}`)
+		case permission.EmailAddress == "limited@example.com" && n == 1:
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"error":{"code":403,"message":"Rate limit exceeded","errors":[{"reason":"userRateLimitExceeded"}]}}`)
+		default:
+			fmt.Fprint(w, `{"id":"permission"}`)
+		}
+	}))
+	defer srv.Close()
+
+	d, err := New(srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.service.BasePath = srv.URL + "/"
+
+	err = d.ShareBatch(ShareBatchArgs{
+		Out:    &bytes.Buffer{},
+		FileId: "file",
+		Role:   "reader",
+		Type:   "user",
+		Emails: []string{"outside@example.org", "limited@example.com"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 emails: outside@example.org") {
+		t.Fatalf("got error %v, want only outside@example.org to fail", err)
+	}
+
+	if requests["outside@example.org"] != 1 {
+		t.Errorf("permission denial was sent %d times, want 1", requests["outside@example.org"])
+	}
+	if requests["limited@example.com"] != 2 {
+		t.Errorf("rate limited request was sent %d times, want 2", requests["limited@example.com"])
+	}
+}
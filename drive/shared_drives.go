@@ -0,0 +1,62 @@
+package drive
+
+import (
+	"encoding/json"
+	"google.golang.org/api/googleapi"
+	"net/http"
+	"net/url"
+)
+
+// Shared drive as returned by the drives.list call
+type sharedDrive struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type sharedDriveList struct {
+	Drives        []*sharedDrive `json:"drives"`
+	NextPageToken string         `json:"nextPageToken"`
+}
+
+// The drive client library in use does not include the drives resource,
+// so the drives.list call is made manually
+func (self *Drive) listSharedDrives() ([]*sharedDrive, error) {
+	var drives []*sharedDrive
+	var pageToken string
+
+	for {
+		params := url.Values{}
+		params.Set("pageSize", "100")
+		params.Set("fields", "nextPageToken,drives(id,name)")
+		if pageToken != "" {
+			params.Set("pageToken", pageToken)
+		}
+
+		urls := googleapi.ResolveRelative(self.service.BasePath, "drives") + "?" + params.Encode()
+		req, _ := http.NewRequest("GET", urls, nil)
+
+		res, err := self.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		list := &sharedDriveList{}
+		err = googleapi.CheckResponse(res)
+		if err == nil {
+			err = json.NewDecoder(res.Body).Decode(list)
+		}
+		res.Body.Close()
+
+		if err != nil {
+			return nil, wrapError("Failed to list shared drives", err)
+		}
+
+		drives = append(drives, list.Drives...)
+
+		if list.NextPageToken == "" {
+			return drives, nil
+		}
+
+		pageToken = list.NextPageToken
+	}
+}
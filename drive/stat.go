@@ -0,0 +1,85 @@
+package drive
+
+import (
+	"google.golang.org/api/drive/v3"
+	"os"
+	"time"
+)
+
+// FileInfo implements os.FileInfo for a drive file, Sys returns the *drive.File
+type FileInfo struct {
+	File    *drive.File
+	modTime time.Time
+}
+
+func (self *FileInfo) Name() string {
+	return self.File.Name
+}
+
+func (self *FileInfo) Size() int64 {
+	return self.File.Size
+}
+
+func (self *FileInfo) Mode() os.FileMode {
+	if self.IsDir() {
+		return os.ModeDir | 0755
+	}
+	return 0644
+}
+
+func (self *FileInfo) ModTime() time.Time {
+	return self.modTime
+}
+
+func (self *FileInfo) IsDir() bool {
+	return isDir(self.File)
+}
+
+func (self *FileInfo) Sys() interface{} {
+	return self.File
+}
+
+// Stat returns information about the file with the given id or absolute path.
+// The error satisfies IsNotFound if the file does not exist
+func (self *Drive) Stat(path string) (*FileInfo, error) {
+	id, err := self.resolvePathToId(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := self.service.Files.Get(id).Fields("id", "name", "mimeType", "size", "md5Checksum", "modifiedTime", "parents").Do()
+	if err != nil {
+		return nil, wrapError("Failed to get file", err)
+	}
+
+	// Parse the time once instead of on every call to ModTime
+	modTime, _ := time.Parse(time.RFC3339, f.ModifiedTime)
+
+	return &FileInfo{File: f, modTime: modTime}, nil
+}
+
+// Exists returns true and the id if the id or absolute path refers to a file
+// that is not trashed. A missing file is not an error
+func (self *Drive) Exists(path string) (bool, string, error) {
+	id, err := self.resolvePathToId(path)
+	if err != nil {
+		if IsNotFound(err) {
+			return false, "", nil
+		}
+		return false, "", err
+	}
+
+	f, err := self.service.Files.Get(id).Fields("id", "trashed").Do()
+	if err != nil {
+		if IsNotFound(err) {
+			return false, "", nil
+		}
+		return false, "", wrapError("Failed to get file", err)
+	}
+
+	if f.Trashed {
+		return false, "", nil
+	}
+
+	return true, f.Id, nil
+}
@@ -85,16 +85,6 @@ func (self *Drive) prepareSyncFiles(localPath string, root *drive.File, cmp File
 	}, nil
 }
 
-func (self *Drive) isSyncFile(id string) (bool, error) {
-	f, err := self.service.Files.Get(id).Fields("appProperties").Do()
-	if err != nil {
-		return false, fmt.Errorf("Failed to get file: %s", err)
-	}
-
-	_, ok := f.AppProperties["sync"]
-	return ok, nil
-}
-
 func prepareLocalFiles(root string) ([]*LocalFile, error) {
 	var files []*LocalFile
 
@@ -146,7 +136,7 @@ func prepareLocalFiles(root string) ([]*LocalFile, error) {
 	})
 
 	if err != nil {
-		return nil, fmt.Errorf("Failed to prepare local files: %s", err)
+		return nil, wrapError("Failed to prepare local files", err)
 	}
 
 	return files, err
@@ -161,7 +151,7 @@ func (self *Drive) prepareRemoteFiles(rootDir *drive.File, sortOrder string) ([]
 	}
 	files, err := self.listAllFiles(listArgs)
 	if err != nil {
-		return nil, fmt.Errorf("Failed listing files: %s", err)
+		return nil, wrapError("Failed listing files", err)
 	}
 
 	if err := checkFiles(files); err != nil {
@@ -582,8 +572,13 @@ func (self byRemotePath) Swap(i, j int) {
 	self[i], self[j] = self[j], self[i]
 }
 
+// Paths differing only in case are tie-broken by id
 func (self byRemotePath) Less(i, j int) bool {
-	return strings.ToLower(self[i].relPath) < strings.ToLower(self[j].relPath)
+	pi, pj := strings.ToLower(self[i].relPath), strings.ToLower(self[j].relPath)
+	if pi != pj {
+		return pi < pj
+	}
+	return self[i].file.Id < self[j].file.Id
 }
 
 type ignoreFunc func(string) bool
@@ -599,13 +594,13 @@ func prepareIgnorer(path string) (ignoreFunc, error) {
 
 	ignorer, err := ignore.CompileIgnoreFile(path)
 	if err != nil {
-		return acceptAll, fmt.Errorf("Failed to prepare ignorer: %s", err)
+		return acceptAll, wrapError("Failed to prepare ignorer", err)
 	}
 
 	return ignorer.MatchesPath, nil
 }
 
-func formatConflicts(conflicts []*changedFile, out io.Writer) {
+func formatConflicts(conflicts []*changedFile, out io.Writer, decimal bool) {
 	w := new(tabwriter.Writer)
 	w.Init(out, 0, 0, 3, ' ', 0)
 
@@ -614,8 +609,8 @@ func formatConflicts(conflicts []*changedFile, out io.Writer) {
 	for _, cf := range conflicts {
 		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
 			truncateString(cf.local.relPath, 60),
-			formatSize(cf.local.Size(), false),
-			formatSize(cf.remote.Size(), false),
+			formatSize(cf.local.Size(), false, decimal),
+			formatSize(cf.remote.Size(), false, decimal),
 			cf.local.Modified().Local().Format("Jan _2 2006 15:04:05.000"),
 			cf.remote.Modified().Local().Format("Jan _2 2006 15:04:05.000"),
 		)
@@ -22,6 +22,12 @@ type DownloadSyncArgs struct {
 	Timeout          time.Duration
 	Resolution       ConflictResolution
 	Comparer         FileComparer
+	Confirm          ConfirmFunc
+	// Print the time of each transfer and a summary when done
+	Timings bool
+	// Create the local directory if it does not exist
+	Mkdirs       bool
+	DecimalUnits bool
 }
 
 func (self *Drive) DownloadSync(args DownloadSyncArgs) error {
@@ -34,6 +40,22 @@ func (self *Drive) DownloadSync(args DownloadSyncArgs) error {
 		return err
 	}
 
+	if !fileExists(args.Path) {
+		if !args.Mkdirs {
+			return fmt.Errorf("Local directory '%s' does not exist, use --mkdirs to create it", args.Path)
+		}
+
+		if args.DryRun {
+			fmt.Fprintf(args.Out, "Local directory %s does not exist and would be created, all remote files would be downloaded\n", args.Path)
+			return nil
+		}
+
+		if err := os.MkdirAll(args.Path, 0775); err != nil {
+			return fmt.Errorf("Failed to create directory '%s': %s", args.Path, err)
+		}
+		fmt.Fprintf(args.Out, "Created directory %s\n", args.Path)
+	}
+
 	fmt.Fprintln(args.Out, "Collecting file information...")
 	files, err := self.prepareSyncFiles(args.Path, rootDir, args.Comparer)
 	if err != nil {
@@ -47,7 +69,7 @@ func (self *Drive) DownloadSync(args DownloadSyncArgs) error {
 
 	// Ensure that we don't overwrite any local changes
 	if args.Resolution == NoResolution {
-		err = ensureNoLocalModifications(changedFiles)
+		err = ensureNoLocalModifications(changedFiles, args.DecimalUnits)
 		if err != nil {
 			return fmt.Errorf("Conflict detected!\nThe following files have changed and the local file are newer than it's remote counterpart:\n\n%s\nNo conflict resolution was given, aborting...", err)
 		}
@@ -59,14 +81,16 @@ func (self *Drive) DownloadSync(args DownloadSyncArgs) error {
 		return err
 	}
 
+	timings := newTransferTimings(args.Timings && !args.DryRun, args.DecimalUnits)
+
 	// Download missing files
-	err = self.downloadMissingFiles(files, args)
+	err = self.downloadMissingFiles(files, args, timings)
 	if err != nil {
 		return err
 	}
 
 	// Download files that has changed
-	err = self.downloadChangedFiles(changedFiles, args)
+	err = self.downloadChangedFiles(changedFiles, args, timings)
 	if err != nil {
 		return err
 	}
@@ -78,6 +102,8 @@ func (self *Drive) DownloadSync(args DownloadSyncArgs) error {
 			return err
 		}
 	}
+
+	timings.print(args.Out)
 	fmt.Fprintf(args.Out, "Sync finished in %s\n", time.Since(started))
 
 	return nil
@@ -87,7 +113,7 @@ func (self *Drive) getSyncRoot(rootId string) (*drive.File, error) {
 	fields := []googleapi.Field{"id", "name", "mimeType", "appProperties"}
 	f, err := self.service.Files.Get(rootId).Fields(fields...).Do()
 	if err != nil {
-		return nil, fmt.Errorf("Failed to find root dir: %s", err)
+		return nil, wrapError("Failed to find root dir", err)
 	}
 
 	// Ensure file is a directory
@@ -117,7 +143,7 @@ func (self *Drive) createMissingLocalDirs(files *syncFiles, args DownloadSyncArg
 	for i, rf := range missingDirs {
 		absPath, err := filepath.Abs(filepath.Join(args.Path, rf.relPath))
 		if err != nil {
-			return fmt.Errorf("Failed to determine local absolute path: %s", err)
+			return wrapError("Failed to determine local absolute path", err)
 		}
 		fmt.Fprintf(args.Out, "[%04d/%04d] Creating directory %s\n", i+1, missingCount, filepath.Join(filepath.Base(args.Path), rf.relPath))
 
@@ -131,7 +157,7 @@ func (self *Drive) createMissingLocalDirs(files *syncFiles, args DownloadSyncArg
 	return nil
 }
 
-func (self *Drive) downloadMissingFiles(files *syncFiles, args DownloadSyncArgs) error {
+func (self *Drive) downloadMissingFiles(files *syncFiles, args DownloadSyncArgs, timings *transferTimings) error {
 	missingFiles := files.filterMissingLocalFiles()
 	missingCount := len(missingFiles)
 
@@ -142,20 +168,22 @@ func (self *Drive) downloadMissingFiles(files *syncFiles, args DownloadSyncArgs)
 	for i, rf := range missingFiles {
 		absPath, err := filepath.Abs(filepath.Join(args.Path, rf.relPath))
 		if err != nil {
-			return fmt.Errorf("Failed to determine local absolute path: %s", err)
+			return wrapError("Failed to determine local absolute path", err)
 		}
 		fmt.Fprintf(args.Out, "[%04d/%04d] Downloading %s -> %s\n", i+1, missingCount, rf.relPath, filepath.Join(filepath.Base(args.Path), rf.relPath))
 
+		transferStarted := time.Now()
 		err = self.downloadRemoteFile(rf.file.Id, absPath, args, 0)
 		if err != nil {
 			return err
 		}
+		timings.record(args.Out, rf.relPath, rf.file.Size, transferStarted)
 	}
 
 	return nil
 }
 
-func (self *Drive) downloadChangedFiles(changedFiles []*changedFile, args DownloadSyncArgs) error {
+func (self *Drive) downloadChangedFiles(changedFiles []*changedFile, args DownloadSyncArgs, timings *transferTimings) error {
 	changedCount := len(changedFiles)
 
 	if changedCount > 0 {
@@ -170,14 +198,16 @@ func (self *Drive) downloadChangedFiles(changedFiles []*changedFile, args Downlo
 
 		absPath, err := filepath.Abs(filepath.Join(args.Path, cf.remote.relPath))
 		if err != nil {
-			return fmt.Errorf("Failed to determine local absolute path: %s", err)
+			return wrapError("Failed to determine local absolute path", err)
 		}
 		fmt.Fprintf(args.Out, "[%04d/%04d] Downloading %s -> %s\n", i+1, changedCount, cf.remote.relPath, filepath.Join(filepath.Base(args.Path), cf.remote.relPath))
 
+		transferStarted := time.Now()
 		err = self.downloadRemoteFile(cf.remote.file.Id, absPath, args, 0)
 		if err != nil {
 			return err
 		}
+		timings.record(args.Out, cf.remote.relPath, cf.remote.file.Size, transferStarted)
 	}
 
 	return nil
@@ -199,8 +229,10 @@ func (self *Drive) downloadRemoteFile(id, fpath string, args DownloadSyncArgs, t
 			return self.downloadRemoteFile(id, fpath, args, try)
 		} else if isTimeoutError(err) {
 			return fmt.Errorf("Failed to download file: timeout, no data was transferred for %v", args.Timeout)
+		} else if isRequestTimeoutError(err) {
+			return fmt.Errorf("Failed to download file: timeout, no response within the http timeout")
 		} else {
-			return fmt.Errorf("Failed to download file: %s", err)
+			return wrapError("Failed to download file", err)
 		}
 	}
 
@@ -208,7 +240,7 @@ func (self *Drive) downloadRemoteFile(id, fpath string, args DownloadSyncArgs, t
 	defer res.Body.Close()
 
 	// Wrap response body in progress reader
-	progressReader := getProgressReader(res.Body, args.Progress, res.ContentLength)
+	progressReader := getProgressReader(res.Body, args.Progress, res.ContentLength, args.DecimalUnits)
 
 	// Wrap reader in timeout reader
 	reader := timeoutReaderWrapper(progressReader)
@@ -256,6 +288,18 @@ func (self *Drive) deleteExtraneousLocalFiles(files *syncFiles, args DownloadSyn
 		fmt.Fprintf(args.Out, "\n%d local files are extraneous\n", extraneousCount)
 	}
 
+	if extraneousCount > 0 && !args.DryRun {
+		var size int64
+		for _, lf := range extraneousFiles {
+			size += lf.info.Size()
+		}
+
+		err := confirm(args.Confirm, fmt.Sprintf("Delete %d local files, total %s", extraneousCount, formatSize(size, false, args.DecimalUnits)))
+		if err != nil {
+			return err
+		}
+	}
+
 	// Sort files so that the files with the longest path comes first
 	sort.Sort(sort.Reverse(byLocalPathLength(extraneousFiles)))
 
@@ -268,7 +312,7 @@ func (self *Drive) deleteExtraneousLocalFiles(files *syncFiles, args DownloadSyn
 
 		err := os.Remove(lf.absPath)
 		if err != nil {
-			return fmt.Errorf("Failed to delete local file: %s", err)
+			return wrapError("Failed to delete local file", err)
 		}
 	}
 
@@ -316,13 +360,13 @@ func checkLocalConflict(cf *changedFile, resolution ConflictResolution) (bool, s
 	return true, "conflicting file, unhandled case"
 }
 
-func ensureNoLocalModifications(files []*changedFile) error {
+func ensureNoLocalModifications(files []*changedFile, decimal bool) error {
 	conflicts := findLocalConflicts(files)
 	if len(conflicts) == 0 {
 		return nil
 	}
 
 	buffer := bytes.NewBufferString("")
-	formatConflicts(conflicts, buffer)
-	return fmt.Errorf(buffer.String())
+	formatConflicts(conflicts, buffer, decimal)
+	return fmt.Errorf("%s", buffer.String())
 }
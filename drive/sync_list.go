@@ -34,6 +34,7 @@ type ListRecursiveSyncArgs struct {
 	SkipHeader  bool
 	PathWidth   int64
 	SizeInBytes bool
+	DecimalUnits bool
 	SortOrder   string
 	UseCsv	    bool
 }
@@ -90,7 +91,7 @@ func printSyncDirContent(files []*RemoteFile, args ListRecursiveSyncArgs) {
 			rf.file.Id,
 			truncateString(rf.relPath, int(args.PathWidth)),
 			filetype(rf.file),
-			formatSize(rf.file.Size, args.SizeInBytes),
+			formatSize(rf.file.Size, args.SizeInBytes, args.DecimalUnits),
 			formatDatetime(rf.file.ModifiedTime),
 		)
 	}
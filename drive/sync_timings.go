@@ -0,0 +1,102 @@
+package drive
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+const slowestTransfersCount = 10
+
+type fileTiming struct {
+	path     string
+	size     int64
+	duration time.Duration
+}
+
+// Keeps track of how long each file transfer took during a sync.
+// A nil value means that timings are disabled
+type transferTimings struct {
+	timings      []fileTiming
+	decimalUnits bool
+}
+
+func newTransferTimings(enabled, decimalUnits bool) *transferTimings {
+	if !enabled {
+		return nil
+	}
+	return &transferTimings{decimalUnits: decimalUnits}
+}
+
+// Records and prints the time of a finished transfer
+func (self *transferTimings) record(out io.Writer, path string, size int64, started time.Time) {
+	if self == nil {
+		return
+	}
+
+	ended := time.Now()
+	t := fileTiming{path, size, ended.Sub(started)}
+	self.timings = append(self.timings, t)
+
+	rate := calcRate(size, started, ended)
+	fmt.Fprintf(out, "Transferred %s in %s, %s/s\n", formatUsage(size, false, self.decimalUnits), roundDuration(t.duration), formatUsage(rate, false, self.decimalUnits))
+}
+
+// Prints the slowest transfers, the total transfer time and the aggregate throughput
+func (self *transferTimings) print(out io.Writer) {
+	if self == nil || len(self.timings) == 0 {
+		return
+	}
+
+	var total time.Duration
+	var size int64
+	for _, t := range self.timings {
+		total += t.duration
+		size += t.size
+	}
+
+	sorted := make([]fileTiming, len(self.timings))
+	copy(sorted, self.timings)
+	sort.Sort(byDurationDesc(sorted))
+
+	if len(sorted) > slowestTransfersCount {
+		sorted = sorted[:slowestTransfersCount]
+	}
+
+	fmt.Fprintln(out, "\nSlowest transfers:")
+
+	w := new(tabwriter.Writer)
+	w.Init(out, 0, 0, 3, ' ', 0)
+
+	for _, t := range sorted {
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", roundDuration(t.duration), formatUsage(t.size, false, self.decimalUnits), t.path)
+	}
+	w.Flush()
+
+	start := time.Time{}
+	rate := calcRate(size, start, start.Add(total))
+	fmt.Fprintf(out, "Transferred %d files, %s in %s, %s/s\n", len(self.timings), formatUsage(size, false, self.decimalUnits), roundDuration(total), formatUsage(rate, false, self.decimalUnits))
+}
+
+func roundDuration(d time.Duration) time.Duration {
+	if d < time.Second {
+		return d.Round(time.Millisecond)
+	}
+	return d.Round(100 * time.Millisecond)
+}
+
+type byDurationDesc []fileTiming
+
+func (self byDurationDesc) Len() int {
+	return len(self)
+}
+
+func (self byDurationDesc) Swap(i, j int) {
+	self[i], self[j] = self[j], self[i]
+}
+
+func (self byDurationDesc) Less(i, j int) bool {
+	return self[i].duration > self[j].duration
+}
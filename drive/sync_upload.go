@@ -23,6 +23,10 @@ type UploadSyncArgs struct {
 	Timeout          time.Duration
 	Resolution       ConflictResolution
 	Comparer         FileComparer
+	Confirm          ConfirmFunc
+	// Print the time of each transfer and a summary when done
+	Timings      bool
+	DecimalUnits bool
 }
 
 func (self *Drive) UploadSync(args UploadSyncArgs) error {
@@ -52,13 +56,13 @@ func (self *Drive) UploadSync(args UploadSyncArgs) error {
 	fmt.Fprintf(args.Out, "Found %d local files and %d remote files\n", len(files.local), len(files.remote))
 
 	// Ensure that there is enough free space on drive
-	if ok, msg := self.checkRemoteFreeSpace(missingFiles, changedFiles); !ok {
-		return fmt.Errorf(msg)
+	if ok, msg := self.checkRemoteFreeSpace(missingFiles, changedFiles, args.DecimalUnits); !ok {
+		return fmt.Errorf("%s", msg)
 	}
 
 	// Ensure that we don't overwrite any remote changes
 	if args.Resolution == NoResolution {
-		err = ensureNoRemoteModifications(changedFiles)
+		err = ensureNoRemoteModifications(changedFiles, args.DecimalUnits)
 		if err != nil {
 			return fmt.Errorf("Conflict detected!\nThe following files have changed and the remote file are newer than it's local counterpart:\n\n%s\nNo conflict resolution was given, aborting...", err)
 		}
@@ -70,14 +74,16 @@ func (self *Drive) UploadSync(args UploadSyncArgs) error {
 		return err
 	}
 
+	timings := newTransferTimings(args.Timings && !args.DryRun, args.DecimalUnits)
+
 	// Upload missing files
-	err = self.uploadMissingFiles(missingFiles, files, args)
+	err = self.uploadMissingFiles(missingFiles, files, args, timings)
 	if err != nil {
 		return err
 	}
 
 	// Update modified files
-	err = self.updateChangedFiles(changedFiles, rootDir, args)
+	err = self.updateChangedFiles(changedFiles, rootDir, args, timings)
 	if err != nil {
 		return err
 	}
@@ -89,6 +95,8 @@ func (self *Drive) UploadSync(args UploadSyncArgs) error {
 			return err
 		}
 	}
+
+	timings.print(args.Out)
 	fmt.Fprintf(args.Out, "Sync finished in %s\n", time.Since(started))
 
 	return nil
@@ -98,7 +106,7 @@ func (self *Drive) prepareSyncRoot(args UploadSyncArgs) (*drive.File, error) {
 	fields := []googleapi.Field{"id", "name", "mimeType", "appProperties"}
 	f, err := self.service.Files.Get(args.RootId).Fields(fields...).Do()
 	if err != nil {
-		return nil, fmt.Errorf("Failed to find root dir: %s", err)
+		return nil, wrapError("Failed to find root dir", err)
 	}
 
 	// Ensure file is a directory
@@ -115,7 +123,7 @@ func (self *Drive) prepareSyncRoot(args UploadSyncArgs) (*drive.File, error) {
 	// Check if the directory is empty
 	isEmpty, err := self.dirIsEmpty(f.Id)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to check if root dir is empty: %s", err)
+		return nil, wrapError("Failed to check if root dir is empty", err)
 	}
 
 	// Ensure that the directory is empty
@@ -123,6 +131,11 @@ func (self *Drive) prepareSyncRoot(args UploadSyncArgs) (*drive.File, error) {
 		return nil, fmt.Errorf("Root directory is not empty, the initial sync requires an empty directory")
 	}
 
+	if args.DryRun {
+		fmt.Fprintf(args.Out, "Directory %s would be marked as sync root\n", f.Id)
+		return f, nil
+	}
+
 	// Update directory with syncRoot property
 	dstFile := &drive.File{
 		AppProperties: map[string]string{"sync": "true", "syncRoot": "true"},
@@ -130,7 +143,7 @@ func (self *Drive) prepareSyncRoot(args UploadSyncArgs) (*drive.File, error) {
 
 	f, err = self.service.Files.Update(f.Id, dstFile).Fields(fields...).Do()
 	if err != nil {
-		return nil, fmt.Errorf("Failed to update root directory: %s", err)
+		return nil, wrapError("Failed to update root directory", err)
 	}
 
 	return f, nil
@@ -184,7 +197,7 @@ type createMissingRemoteDirArgs struct {
 	try      int
 }
 
-func (self *Drive) uploadMissingFiles(missingFiles []*LocalFile, files *syncFiles, args UploadSyncArgs) error {
+func (self *Drive) uploadMissingFiles(missingFiles []*LocalFile, files *syncFiles, args UploadSyncArgs, timings *transferTimings) error {
 	missingCount := len(missingFiles)
 
 	if missingCount > 0 {
@@ -200,16 +213,18 @@ func (self *Drive) uploadMissingFiles(missingFiles []*LocalFile, files *syncFile
 
 		fmt.Fprintf(args.Out, "[%04d/%04d] Uploading %s -> %s\n", i+1, missingCount, lf.relPath, filepath.Join(files.root.file.Name, lf.relPath))
 
+		transferStarted := time.Now()
 		err := self.uploadMissingFile(parent.file.Id, lf, args, 0)
 		if err != nil {
 			return err
 		}
+		timings.record(args.Out, lf.relPath, lf.info.Size(), transferStarted)
 	}
 
 	return nil
 }
 
-func (self *Drive) updateChangedFiles(changedFiles []*changedFile, root *drive.File, args UploadSyncArgs) error {
+func (self *Drive) updateChangedFiles(changedFiles []*changedFile, root *drive.File, args UploadSyncArgs, timings *transferTimings) error {
 	changedCount := len(changedFiles)
 
 	if changedCount > 0 {
@@ -224,10 +239,12 @@ func (self *Drive) updateChangedFiles(changedFiles []*changedFile, root *drive.F
 
 		fmt.Fprintf(args.Out, "[%04d/%04d] Updating %s -> %s\n", i+1, changedCount, cf.local.relPath, filepath.Join(root.Name, cf.local.relPath))
 
+		transferStarted := time.Now()
 		err := self.updateChangedFile(cf, args, 0)
 		if err != nil {
 			return err
 		}
+		timings.record(args.Out, cf.local.relPath, cf.local.info.Size(), transferStarted)
 	}
 
 	return nil
@@ -241,6 +258,18 @@ func (self *Drive) deleteExtraneousRemoteFiles(files *syncFiles, args UploadSync
 		fmt.Fprintf(args.Out, "\n%d remote files are extraneous\n", extraneousCount)
 	}
 
+	if extraneousCount > 0 && !args.DryRun {
+		var size int64
+		for _, rf := range extraneousFiles {
+			size += rf.file.Size
+		}
+
+		err := confirm(args.Confirm, fmt.Sprintf("Permanently delete %d remote files, total %s", extraneousCount, formatSize(size, false, args.DecimalUnits)))
+		if err != nil {
+			return err
+		}
+	}
+
 	// Sort files so that the files with the longest path comes first
 	sort.Sort(sort.Reverse(byRemotePathLength(extraneousFiles)))
 
@@ -275,7 +304,7 @@ func (self *Drive) createMissingRemoteDir(args createMissingRemoteDirArgs) (*dri
 			args.try++
 			return self.createMissingRemoteDir(args)
 		} else {
-			return nil, fmt.Errorf("Failed to create directory: %s", err)
+			return nil, wrapError("Failed to create directory", err)
 		}
 	}
 
@@ -289,7 +318,7 @@ func (self *Drive) uploadMissingFile(parentId string, lf *LocalFile, args Upload
 
 	srcFile, err := os.Open(lf.absPath)
 	if err != nil {
-		return fmt.Errorf("Failed to open file: %s", err)
+		return wrapError("Failed to open file", err)
 	}
 
 	// Close file on function exit
@@ -306,7 +335,7 @@ func (self *Drive) uploadMissingFile(parentId string, lf *LocalFile, args Upload
 	chunkSize := googleapi.ChunkSize(int(args.ChunkSize))
 
 	// Wrap file in progress reader
-	progressReader := getProgressReader(srcFile, args.Progress, lf.info.Size())
+	progressReader := getProgressReader(srcFile, args.Progress, lf.info.Size(), args.DecimalUnits)
 
 	// Wrap reader in timeout reader
 	reader, ctx := getTimeoutReaderContext(progressReader, args.Timeout)
@@ -319,8 +348,10 @@ func (self *Drive) uploadMissingFile(parentId string, lf *LocalFile, args Upload
 			return self.uploadMissingFile(parentId, lf, args, try)
 		} else if isTimeoutError(err) {
 			return fmt.Errorf("Failed to upload file: timeout, no data was transferred for %v", args.Timeout)
+		} else if isRequestTimeoutError(err) {
+			return fmt.Errorf("Failed to upload file: timeout, no response within the http timeout")
 		} else {
-			return fmt.Errorf("Failed to upload file: %s", err)
+			return wrapError("Failed to upload file", err)
 		}
 	}
 
@@ -334,7 +365,7 @@ func (self *Drive) updateChangedFile(cf *changedFile, args UploadSyncArgs, try i
 
 	srcFile, err := os.Open(cf.local.absPath)
 	if err != nil {
-		return fmt.Errorf("Failed to open file: %s", err)
+		return wrapError("Failed to open file", err)
 	}
 
 	// Close file on function exit
@@ -347,7 +378,7 @@ func (self *Drive) updateChangedFile(cf *changedFile, args UploadSyncArgs, try i
 	chunkSize := googleapi.ChunkSize(int(args.ChunkSize))
 
 	// Wrap file in progress reader
-	progressReader := getProgressReader(srcFile, args.Progress, cf.local.info.Size())
+	progressReader := getProgressReader(srcFile, args.Progress, cf.local.info.Size(), args.DecimalUnits)
 
 	// Wrap reader in timeout reader
 	reader, ctx := getTimeoutReaderContext(progressReader, args.Timeout)
@@ -360,8 +391,10 @@ func (self *Drive) updateChangedFile(cf *changedFile, args UploadSyncArgs, try i
 			return self.updateChangedFile(cf, args, try)
 		} else if isTimeoutError(err) {
 			return fmt.Errorf("Failed to upload file: timeout, no data was transferred for %v", args.Timeout)
+		} else if isRequestTimeoutError(err) {
+			return fmt.Errorf("Failed to upload file: timeout, no response within the http timeout")
 		} else {
-			return fmt.Errorf("Failed to update file: %s", err)
+			return wrapError("Failed to update file", err)
 		}
 	}
 
@@ -380,7 +413,7 @@ func (self *Drive) deleteRemoteFile(rf *RemoteFile, args UploadSyncArgs, try int
 			try++
 			return self.deleteRemoteFile(rf, args, try)
 		} else {
-			return fmt.Errorf("Failed to delete file: %s", err)
+			return wrapError("Failed to delete file", err)
 		}
 	}
 
@@ -391,7 +424,7 @@ func (self *Drive) dirIsEmpty(id string) (bool, error) {
 	query := fmt.Sprintf("'%s' in parents", id)
 	fileList, err := self.service.Files.List().Q(query).Do()
 	if err != nil {
-		return false, fmt.Errorf("Empty dir check failed: ", err)
+		return false, wrapError("Empty dir check failed", err)
 	}
 
 	return len(fileList.Files) == 0, nil
@@ -438,18 +471,18 @@ func checkRemoteConflict(cf *changedFile, resolution ConflictResolution) (bool,
 	return true, "conflicting file, unhandled case"
 }
 
-func ensureNoRemoteModifications(files []*changedFile) error {
+func ensureNoRemoteModifications(files []*changedFile, decimal bool) error {
 	conflicts := findRemoteConflicts(files)
 	if len(conflicts) == 0 {
 		return nil
 	}
 
 	buffer := bytes.NewBufferString("")
-	formatConflicts(conflicts, buffer)
-	return fmt.Errorf(buffer.String())
+	formatConflicts(conflicts, buffer, decimal)
+	return fmt.Errorf("%s", buffer.String())
 }
 
-func (self *Drive) checkRemoteFreeSpace(missingFiles []*LocalFile, changedFiles []*changedFile) (bool, string) {
+func (self *Drive) checkRemoteFreeSpace(missingFiles []*LocalFile, changedFiles []*changedFile, decimal bool) (bool, string) {
 	about, err := self.service.About.Get().Fields("storageQuota").Do()
 	if err != nil {
 		return false, fmt.Sprintf("Failed to determine free space: %s", err)
@@ -473,7 +506,7 @@ func (self *Drive) checkRemoteFreeSpace(missingFiles []*LocalFile, changedFiles
 	}
 
 	if totalSize > freeSpace {
-		return false, fmt.Sprintf("Not enough free space, have %s need %s", formatSize(freeSpace, false), formatSize(totalSize, false))
+		return false, fmt.Sprintf("Not enough free space, have %s need %s", formatSize(freeSpace, false, decimal), formatSize(totalSize, false, decimal))
 	}
 
 	return true, ""
@@ -0,0 +1,100 @@
+package drive
+
+import (
+	"bufio"
+	"fmt"
+	"google.golang.org/api/drive/v3"
+	"io"
+	"strings"
+	"time"
+)
+
+type TouchArgs struct {
+	Out io.Writer
+	// File ids are read from In, one per line, if the id is -
+	Id   string
+	In   io.Reader
+	Time string
+}
+
+// Sets the modified time of the files to the given time, or the current time if now.
+// All files are processed before returning an error if any failed
+func (self *Drive) Touch(args TouchArgs) error {
+	modifiedTime, err := parseTouchTime(args.Time)
+	if err != nil {
+		return err
+	}
+
+	ids := []string{args.Id}
+	if args.Id == "-" {
+		ids, err = readIds(args.In)
+		if err != nil {
+			return err
+		}
+	}
+
+	failed := 0
+
+	for _, id := range ids {
+		f, err := self.touch(id, modifiedTime)
+		if err != nil {
+			fmt.Fprintf(args.Out, "Failed to touch %s: %s\n", id, err)
+			failed++
+			continue
+		}
+
+		fmt.Fprintf(args.Out, "Modified time of '%s' set to %s\n", f.Name, formatDatetime(f.ModifiedTime))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("Failed to touch %d of %d files", failed, len(ids))
+	}
+
+	return nil
+}
+
+// The file is read back after the update to show the time stored by drive
+func (self *Drive) touch(id, modifiedTime string) (*drive.File, error) {
+	_, err := self.service.Files.Update(id, &drive.File{ModifiedTime: modifiedTime}).Fields("id").Do()
+	if err != nil {
+		return nil, wrapError("Failed to update file", err)
+	}
+
+	f, err := self.service.Files.Get(id).Fields("id", "name", "modifiedTime").Do()
+	if err != nil {
+		return nil, wrapError("Failed to get file", err)
+	}
+
+	return f, nil
+}
+
+func parseTouchTime(value string) (string, error) {
+	if value == "" || value == "now" {
+		return time.Now().UTC().Format(time.RFC3339), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return "", fmt.Errorf("Invalid time '%s', expected RFC 3339 (2006-01-02T15:04:05Z) or now", value)
+	}
+
+	return t.UTC().Format(time.RFC3339), nil
+}
+
+// Returns the non-empty lines of the reader
+func readIds(r io.Reader) ([]string, error) {
+	var ids []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if id := strings.TrimSpace(scanner.Text()); id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, wrapError("Failed to read file ids", err)
+	}
+
+	return ids, nil
+}
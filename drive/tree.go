@@ -0,0 +1,218 @@
+package drive
+
+import (
+	"encoding/json"
+	"fmt"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+	"io"
+	"path"
+	"sync"
+)
+
+// Max number of directories listed at the same time when building a tree,
+// it is also the max number of goroutines walking directories
+const maxConcurrentTreeListings = 4
+
+type TreeArgs struct {
+	Out      io.Writer
+	Id       string
+	MaxDepth int
+	// Add the total size of the files below each directory, directories
+	// below the max depth are still walked to sum their sizes
+	Sizes bool
+	// Collapse directories containing a single directory and no files
+	FlattenSingleChild bool
+	DecimalUnits       bool
+}
+
+// Directories include their children unless the max depth is reached,
+// empty directories have no children
+type treeNode struct {
+	Id            string      `json:"id"`
+	Name          string      `json:"name"`
+	Path          string      `json:"path"`
+	TotalSize     *int64      `json:"totalSize,omitempty"`
+	TotalSizeText string      `json:"totalSizeText,omitempty"`
+	Type          string      `json:"type"`
+	Size          int64       `json:"size"`
+	Children      []*treeNode `json:"children,omitempty"`
+
+	// Sizes of the distinct files below the directory by id, only kept
+	// until the sizes of the parent are summed
+	files map[string]int64
+}
+
+// Prints the directory hierarchy as nested json, a max depth <= 0 means no limit
+func (self *Drive) Tree(args TreeArgs) error {
+	f, err := self.service.Files.Get(args.Id).Fields("id", "name", "mimeType", "size", "md5Checksum", "parents").Do()
+	if err != nil {
+		return wrapError("Failed to get file", err)
+	}
+
+	if !isDir(f) {
+		return fmt.Errorf("'%s' is not a directory", f.Name)
+	}
+
+	// Only the path of the root is looked up, the paths below it are
+	// the path of the parent joined with the name
+	rootPath, err := self.newPathfinder().absPath(f)
+	if err != nil {
+		return err
+	}
+
+	walker := &treeWalker{
+		drive:        self,
+		maxDepth:     args.MaxDepth,
+		sizes:        args.Sizes,
+		decimalUnits: args.DecimalUnits,
+		sem:          make(chan struct{}, maxConcurrentTreeListings),
+		workers:      make(chan struct{}, maxConcurrentTreeListings),
+	}
+
+	root, err := walker.walk(f, rootPath, 1)
+	if err != nil {
+		return err
+	}
+
+	if args.FlattenSingleChild {
+		flattenSingleChild(root)
+	}
+
+	enc := json.NewEncoder(args.Out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(root)
+}
+
+type treeWalker struct {
+	drive    *Drive
+	maxDepth int
+	sizes    bool
+	// Total sizes are shown in decimal units
+	decimalUnits bool
+	sem          chan struct{}
+	workers      chan struct{}
+}
+
+func (self *treeWalker) walk(f *drive.File, fpath string, depth int) (*treeNode, error) {
+	node := &treeNode{
+		Id:   f.Id,
+		Name: f.Name,
+		Path: fpath,
+		Type: filetype(f),
+		Size: f.Size,
+	}
+
+	belowMaxDepth := self.maxDepth > 0 && depth > self.maxDepth
+
+	if !isDir(f) || (belowMaxDepth && !self.sizes) {
+		return node, nil
+	}
+
+	files, err := self.list(f)
+	if err != nil {
+		return nil, err
+	}
+
+	node.Children = make([]*treeNode, len(files))
+	errs := make([]error, len(files))
+	wg := &sync.WaitGroup{}
+
+	for i, child := range files {
+		childPath := path.Join(fpath, child.Name)
+
+		if !isDir(child) {
+			node.Children[i], errs[i] = self.walk(child, childPath, depth+1)
+			continue
+		}
+
+		// Directories are walked by a new goroutine if one is available and by
+		// this goroutine otherwise, so that parents never wait for a free worker
+		select {
+		case self.workers <- struct{}{}:
+			wg.Add(1)
+			go func(i int, child *drive.File, childPath string) {
+				defer func() {
+					<-self.workers
+					wg.Done()
+				}()
+				node.Children[i], errs[i] = self.walk(child, childPath, depth+1)
+			}(i, child, childPath)
+		default:
+			node.Children[i], errs[i] = self.walk(child, childPath, depth+1)
+		}
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if self.sizes {
+		self.sumSizes(node)
+	}
+
+	if belowMaxDepth {
+		node.Children = nil
+	}
+
+	return node, nil
+}
+
+// Chains of directories containing a single directory and no files are
+// collapsed into the last directory of the chain, named by joining the names
+// of the chain with /. Its total size is the same as the first directory
+func flattenSingleChild(node *treeNode) {
+	for len(node.Children) == 1 && node.Children[0].Type == "dir" {
+		child := node.Children[0]
+		child.Name = node.Name + "/" + child.Name
+		*node = *child
+	}
+
+	for _, child := range node.Children {
+		flattenSingleChild(child)
+	}
+}
+
+// Children are walked before their parent, so their files are already collected.
+// Files are collected by id, so files and directories with several parents
+// below the directory are only counted once
+func (self *treeWalker) sumSizes(node *treeNode) {
+	node.files = map[string]int64{}
+
+	for _, child := range node.Children {
+		if child.files == nil {
+			node.files[child.Id] = child.Size
+			continue
+		}
+
+		for id, size := range child.files {
+			node.files[id] = size
+		}
+		child.files = nil
+	}
+
+	total := sumFileSizes(node.files)
+	node.TotalSize = &total
+	node.TotalSizeText = formatSize(total, false, self.decimalUnits)
+}
+
+// Only the listing is limited, so that waiting parents do not block their children
+func (self *treeWalker) list(parent *drive.File) ([]*drive.File, error) {
+	self.sem <- struct{}{}
+	defer func() { <-self.sem }()
+
+	files, err := self.drive.listAllFiles(listAllFilesArgs{
+		query:     fmt.Sprintf("'%s' in parents and trashed = false", parent.Id),
+		fields:    []googleapi.Field{"nextPageToken", "files(id,name,mimeType,size,md5Checksum)"},
+		sortOrder: "folder,name",
+	})
+	if err != nil {
+		return nil, wrapError("Failed listing files", err)
+	}
+
+	return files, nil
+}
@@ -0,0 +1,90 @@
+package drive
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// The shared directory is below both a and b, its files must be counted once
+func TestTreeSizesMultipleParents(t *testing.T) {
+	d, _ := newFakeDrive(t,
+		fakeFolder("top", "Top"),
+		fakeFolder("a", "a", "top"),
+		fakeFolder("b", "b", "top"),
+		fakeFolder("shared", "shared", "a", "b"),
+		fakeBinary("f1", "f1", "12345", "shared"),
+		fakeBinary("f2", "f2", "123", "shared", "b"),
+		fakeBinary("f3", "f3", "1", "a"),
+	)
+
+	for i := 0; i < 10; i++ {
+		out := &bytes.Buffer{}
+		if err := d.Tree(TreeArgs{Out: out, Id: "top", Sizes: true}); err != nil {
+			t.Fatal(err)
+		}
+
+		root := &treeNode{}
+		if err := json.Unmarshal(out.Bytes(), root); err != nil {
+			t.Fatal(err)
+		}
+
+		totals := map[string]int64{}
+		var collect func(*treeNode)
+		collect = func(node *treeNode) {
+			if node.TotalSize != nil {
+				totals[node.Path] = *node.TotalSize
+			}
+			for _, child := range node.Children {
+				collect(child)
+			}
+		}
+		collect(root)
+
+		expected := map[string]int64{
+			"Top":          9,
+			"Top/a":        9,
+			"Top/a/shared": 8,
+			"Top/b":        8,
+			"Top/b/shared": 8,
+		}
+		for path, size := range expected {
+			if totals[path] != size {
+				t.Errorf("%s: got total size %d, want %d", path, totals[path], size)
+			}
+		}
+	}
+}
+
+// Collapsed directories are named by joining the names of the chain with /
+func TestTreeFlattenSingleChild(t *testing.T) {
+	d, _ := newFakeDrive(t, singleChildFiles()...)
+
+	out := &bytes.Buffer{}
+	if err := d.Tree(TreeArgs{Out: out, Id: "top", FlattenSingleChild: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	root := &treeNode{}
+	if err := json.Unmarshal(out.Bytes(), root); err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	var collect func(*treeNode)
+	collect = func(node *treeNode) {
+		names = append(names, node.Name)
+		for _, child := range node.Children {
+			collect(child)
+		}
+	}
+	collect(root)
+
+	sort.Strings(names)
+	expected := []string{"Top/a", "b/x/c", "fa", "fc"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("got %v, want %v", names, expected)
+	}
+}
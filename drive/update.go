@@ -6,6 +6,7 @@ import (
 	"google.golang.org/api/googleapi"
 	"io"
 	"mime"
+	"os"
 	"path/filepath"
 	"time"
 )
@@ -22,16 +23,34 @@ type UpdateArgs struct {
 	Recursive   bool
 	ChunkSize   int64
 	Timeout     time.Duration
+	// Only update if the local file is newer than the remote file
+	NewerThanRemote bool
+	Force           bool
+	// Only print what would be updated
+	DryRun       bool
+	DecimalUnits bool
 }
 
 func (self *Drive) Update(args UpdateArgs) error {
 	srcFile, srcFileInfo, err := openFile(args.Path)
 	if err != nil {
-		return fmt.Errorf("Failed to open file: %s", err)
+		return wrapError("Failed to open file", err)
 	}
 
 	defer srcFile.Close()
 
+	if args.NewerThanRemote && !args.Force {
+		newer, reason, err := self.isNewerThanRemote(args.Id, srcFileInfo)
+		if err != nil {
+			return err
+		}
+
+		if !newer {
+			fmt.Fprintf(args.Out, "Skipping %s, %s, use --force to update anyway\n", args.Path, reason)
+			return nil
+		}
+	}
+
 	// Instantiate empty drive file
 	dstFile := &drive.File{Description: args.Description}
 
@@ -52,11 +71,20 @@ func (self *Drive) Update(args UpdateArgs) error {
 	// Set parent folders
 	dstFile.Parents = args.Parents
 
+	if args.DryRun {
+		remote, err := self.service.Files.Get(args.Id).Fields("id", "name").Do()
+		if err != nil {
+			return wrapError("Failed to get file", err)
+		}
+		printDryRun(args.Out, "update '%s' (%s) with %s as '%s', total %s", remote.Name, remote.Id, args.Path, dstFile.Name, formatSize(srcFileInfo.Size(), false, args.DecimalUnits))
+		return nil
+	}
+
 	// Chunk size option
 	chunkSize := googleapi.ChunkSize(int(args.ChunkSize))
 
 	// Wrap file in progress reader
-	progressReader := getProgressReader(srcFile, args.Progress, srcFileInfo.Size())
+	progressReader := getProgressReader(srcFile, args.Progress, srcFileInfo.Size(), args.DecimalUnits)
 
 	// Wrap reader in timeout reader
 	reader, ctx := getTimeoutReaderContext(progressReader, args.Timeout)
@@ -69,12 +97,42 @@ func (self *Drive) Update(args UpdateArgs) error {
 		if isTimeoutError(err) {
 			return fmt.Errorf("Failed to upload file: timeout, no data was transferred for %v", args.Timeout)
 		}
-		return fmt.Errorf("Failed to upload file: %s", err)
+		if isRequestTimeoutError(err) {
+			return fmt.Errorf("Failed to upload file: timeout, no response within the http timeout")
+		}
+		return wrapError("Failed to upload file", err)
 	}
 
 	// Calculate average upload rate
 	rate := calcRate(f.Size, started, time.Now())
 
-	fmt.Fprintf(args.Out, "Updated %s at %s/s, total %s\n", f.Id, formatSize(rate, false), formatSize(f.Size, false))
+	fmt.Fprintf(args.Out, "Updated %s at %s/s, total %s\n", f.Id, formatSize(rate, false, args.DecimalUnits), formatSize(f.Size, false, args.DecimalUnits))
 	return nil
 }
+
+// Returns true if the local file was modified after the remote file,
+// otherwise the reason for not updating is returned
+func (self *Drive) isNewerThanRemote(id string, info os.FileInfo) (bool, string, error) {
+	f, err := self.service.Files.Get(id).Fields("id", "modifiedTime").Do()
+	if err != nil {
+		return false, "", wrapError("Failed to get file", err)
+	}
+
+	remoteTime, err := time.Parse(time.RFC3339, f.ModifiedTime)
+	if err != nil {
+		return false, "", fmt.Errorf("Failed to parse remote modified time '%s': %s", f.ModifiedTime, err)
+	}
+
+	// Drive stores the modified time with millisecond precision
+	localTime := info.ModTime().Truncate(time.Millisecond)
+
+	if localTime.After(remoteTime) {
+		return true, "", nil
+	}
+
+	if localTime.Equal(remoteTime) {
+		return false, fmt.Sprintf("remote file has the same modified time as the local file (%s)", formatDatetime(f.ModifiedTime)), nil
+	}
+
+	return false, fmt.Sprintf("remote file modified %s is newer than the local file modified %s", formatDatetime(f.ModifiedTime), localTime.Local().Format(defaultTimeLayout)), nil
+}
@@ -5,9 +5,11 @@ import (
 	"google.golang.org/api/drive/v3"
 	"google.golang.org/api/googleapi"
 	"io"
+	"io/ioutil"
 	"mime"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -24,6 +26,21 @@ type UploadArgs struct {
 	Delete      bool
 	ChunkSize   int64
 	Timeout     time.Duration
+	// Log failed files and keep going when uploading recursively
+	ContinueOnError bool
+	// Refuse to upload if the files do not fit in the remaining quota
+	CheckQuota bool
+	// Keep the original format, the file is never converted to a google document
+	NoConvert bool
+	// Store the upload session in SessionsPath and resume it on the next upload of the same file
+	Resume       bool
+	SessionsPath string
+	// Only print what would be uploaded
+	DryRun bool
+	// Rename, skip or replace when a file with the same name exists in the
+	// parent, by default a duplicate is uploaded. See conflictStrategies
+	OnConflict   string
+	DecimalUnits bool
 }
 
 func (self *Drive) Upload(args UploadArgs) error {
@@ -31,25 +48,52 @@ func (self *Drive) Upload(args UploadArgs) error {
 		return fmt.Errorf("Chunk size is to big, max chunk size for this computer is %d", intMax()-1)
 	}
 
+	if err := checkConflictStrategy(args.OnConflict); err != nil {
+		return err
+	}
+
+	// Parents starting with / are paths
+	var err error
+	args.Parents, err = self.resolvePathsToIds(args.Parents)
+	if err != nil {
+		return err
+	}
+
+	parents, err := self.getParents(args.Parents)
+	if err != nil {
+		return err
+	}
+
 	// Ensure that none of the parents are sync dirs
-	for _, parent := range args.Parents {
-		isSyncDir, err := self.isSyncFile(parent)
+	for _, parent := range parents {
+		if _, ok := parent.AppProperties["sync"]; ok {
+			return fmt.Errorf("%s is a sync directory, use 'sync upload' instead", parent.Id)
+		}
+	}
+
+	if args.CheckQuota {
+		err = self.checkQuota(args.Path, args.DecimalUnits)
 		if err != nil {
 			return err
 		}
+	}
 
-		if isSyncDir {
-			return fmt.Errorf("%s is a sync directory, use 'sync upload' instead", parent)
-		}
+	if args.DryRun {
+		return self.uploadDryRun(args)
 	}
 
 	if args.Recursive {
-		return self.uploadRecursive(args)
+		failures := newFailureSummary(args.ContinueOnError)
+		err := self.uploadRecursive(args, failures)
+		if err != nil {
+			return err
+		}
+		return failures.print(args.Out)
 	}
 
 	info, err := os.Stat(args.Path)
 	if err != nil {
-		return fmt.Errorf("Failed stat file: %s", err)
+		return wrapError("Failed stat file", err)
 	}
 
 	if info.IsDir() {
@@ -60,7 +104,19 @@ func (self *Drive) Upload(args UploadArgs) error {
 	if err != nil {
 		return err
 	}
-	fmt.Fprintf(args.Out, "Uploaded %s at %s/s, total %s\n", f.Id, formatSize(rate, false), formatSize(f.Size, false))
+
+	// Skipped because of a name conflict
+	if f == nil {
+		return nil
+	}
+	fmt.Fprintf(args.Out, "Uploaded %s at %s/s, total %s\n", f.Id, formatSize(rate, false, args.DecimalUnits), formatSize(f.Size, false, args.DecimalUnits))
+
+	if len(args.Parents) > 0 {
+		err = self.printParentPaths(args.Out, f)
+		if err != nil {
+			return err
+		}
+	}
 
 	if args.Share {
 		err = self.shareAnyoneReader(f.Id)
@@ -74,7 +130,7 @@ func (self *Drive) Upload(args UploadArgs) error {
 	if args.Delete {
 		err = os.Remove(args.Path)
 		if err != nil {
-			return fmt.Errorf("Failed to delete file: %s", err)
+			return wrapError("Failed to delete file", err)
 		}
 		fmt.Fprintf(args.Out, "Removed %s\n", args.Path)
 	}
@@ -82,48 +138,161 @@ func (self *Drive) Upload(args UploadArgs) error {
 	return nil
 }
 
-func (self *Drive) uploadRecursive(args UploadArgs) error {
+func (self *Drive) uploadDryRun(args UploadArgs) error {
 	info, err := os.Stat(args.Path)
 	if err != nil {
-		return fmt.Errorf("Failed stat file: %s", err)
+		return wrapError("Failed stat file", err)
+	}
+
+	if !info.IsDir() {
+		dstFile := &drive.File{Name: args.Name, Parents: args.Parents}
+		if dstFile.Name == "" {
+			dstFile.Name = info.Name()
+		}
+
+		// Skipping and replacing is printed as the resolved action
+		existing, skip, err := self.resolveUploadConflict(args, dstFile)
+		if err != nil || skip || existing != nil {
+			return err
+		}
+
+		printDryRun(args.Out, "upload %s as '%s' to %s, total %s", args.Path, dstFile.Name, dryRunParents(args.Parents), formatSize(info.Size(), false, args.DecimalUnits))
+		return nil
+	}
+
+	if !args.Recursive {
+		return fmt.Errorf("'%s' is a directory, use --recursive to upload directories", info.Name())
+	}
+
+	var files, dirs int
+	var size int64
+	err = filepath.Walk(args.Path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			dirs++
+		} else if info.Mode().IsRegular() {
+			files++
+			size += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to walk '%s': %s", args.Path, err)
+	}
+
+	printDryRun(args.Out, "upload directory %s to %s, %d files in %d directories, total %s", args.Path, dryRunParents(args.Parents), files, dirs, formatSize(size, false, args.DecimalUnits))
+
+	if args.OnConflict != "" {
+		return self.uploadDirectoryDryRun(args)
+	}
+	return nil
+}
+
+// Prints the resolved conflicts of the files in the directory. Only existing
+// directories are walked, as the files of new directories can not conflict
+func (self *Drive) uploadDirectoryDryRun(args UploadArgs) error {
+	existing, err := self.findDirectory(filepath.Base(args.Path), args.Parents)
+	if err != nil || existing == nil {
+		return err
+	}
+
+	printDryRun(args.Out, "upload %s into existing directory with id %s", args.Path, existing.Id)
+
+	infos, err := ioutil.ReadDir(args.Path)
+	if err != nil {
+		return wrapError("Failed reading directory", err)
+	}
+
+	for _, info := range infos {
+		newArgs := args
+		newArgs.Path = filepath.Join(args.Path, info.Name())
+		newArgs.Parents = []string{existing.Id}
+
+		if info.IsDir() {
+			err = self.uploadDirectoryDryRun(newArgs)
+		} else if info.Mode().IsRegular() {
+			_, _, err = self.resolveUploadConflict(newArgs, &drive.File{Name: info.Name(), Parents: newArgs.Parents})
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Returns an error if the size of the file, or all files in the directory,
+// is larger than the remaining quota. Accounts without a limit are not checked
+func (self *Drive) checkQuota(path string, decimal bool) error {
+	about, err := self.service.About.Get().Fields("storageQuota").Do()
+	if err != nil {
+		return wrapError("Failed to get about", err)
+	}
+
+	quota := about.StorageQuota
+	if quota == nil || quota.Limit == 0 {
+		return nil
+	}
+
+	var size int64
+	err = filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to get size of '%s': %s", path, err)
+	}
+
+	free := quota.Limit - quota.Usage
+	if size > free {
+		return fmt.Errorf("Insufficient quota, upload requires %s but only %s is available", formatSize(size, false, decimal), formatSize(free, false, decimal))
+	}
+
+	return nil
+}
+
+func (self *Drive) uploadRecursive(args UploadArgs, failures *failureSummary) error {
+	info, err := os.Stat(args.Path)
+	if err != nil {
+		return failures.record(args.Out, args.Path, wrapError("Failed stat file", err))
 	}
 
 	if info.IsDir() {
 		args.Name = ""
-		return self.uploadDirectory(args)
+		return self.uploadDirectory(args, failures)
 	} else if info.Mode().IsRegular() {
 		_, _, err := self.uploadFile(args)
-		return err
+		return failures.record(args.Out, args.Path, err)
 	}
 
 	return nil
 }
 
-func (self *Drive) uploadDirectory(args UploadArgs) error {
+func (self *Drive) uploadDirectory(args UploadArgs, failures *failureSummary) error {
 	srcFile, srcFileInfo, err := openFile(args.Path)
 	if err != nil {
-		return err
+		return failures.record(args.Out, args.Path, err)
 	}
 
 	// Close file on function exit
 	defer srcFile.Close()
 
-	fmt.Fprintf(args.Out, "Creating directory %s\n", srcFileInfo.Name())
-	// Make directory on drive
-	f, err := self.mkdir(MkdirArgs{
-		Out:         args.Out,
-		Name:        srcFileInfo.Name(),
-		Parents:     args.Parents,
-		Description: args.Description,
-	})
+	f, err := self.uploadDirectoryFolder(args, srcFileInfo.Name())
 	if err != nil {
-		return err
+		return failures.record(args.Out, args.Path, err)
 	}
 
 	// Read files from directory
 	names, err := srcFile.Readdirnames(0)
 	if err != nil && err != io.EOF {
-		return fmt.Errorf("Failed reading directory: %s", err)
+		return failures.record(args.Out, args.Path, wrapError("Failed reading directory", err))
 	}
 
 	for _, name := range names {
@@ -134,7 +303,7 @@ func (self *Drive) uploadDirectory(args UploadArgs) error {
 		newArgs.Description = ""
 
 		// Upload
-		err = self.uploadRecursive(newArgs)
+		err = self.uploadRecursive(newArgs, failures)
 		if err != nil {
 			return err
 		}
@@ -143,6 +312,32 @@ func (self *Drive) uploadDirectory(args UploadArgs) error {
 	return nil
 }
 
+// With a conflict strategy the directory is uploaded into an existing directory
+// with the same name, so uploading it again does not create a second directory
+// tree. The strategy then applies to the files in it
+func (self *Drive) uploadDirectoryFolder(args UploadArgs, name string) (*drive.File, error) {
+	if args.OnConflict != "" {
+		existing, err := self.findDirectory(name, args.Parents)
+		if err != nil {
+			return nil, err
+		}
+
+		if existing != nil {
+			fmt.Fprintf(args.Out, "Using existing directory %s with id %s\n", name, existing.Id)
+			return existing, nil
+		}
+	}
+
+	fmt.Fprintf(args.Out, "Creating directory %s\n", name)
+	// Make directory on drive
+	return self.mkdir(MkdirArgs{
+		Out:         args.Out,
+		Name:        name,
+		Parents:     args.Parents,
+		Description: args.Description,
+	})
+}
+
 func (self *Drive) uploadFile(args UploadArgs) (*drive.File, int64, error) {
 	srcFile, srcFileInfo, err := openFile(args.Path)
 	if err != nil {
@@ -169,14 +364,28 @@ func (self *Drive) uploadFile(args UploadArgs) (*drive.File, int64, error) {
 		dstFile.MimeType = args.Mime
 	}
 
+	// Drive only converts files uploaded with a google apps mime type
+	if args.NoConvert && strings.HasPrefix(dstFile.MimeType, googleAppsMimePrefix) {
+		return nil, 0, fmt.Errorf("Mime type '%s' converts the file to a google document, which is not allowed with --no-convert", dstFile.MimeType)
+	}
+
 	// Set parent folders
 	dstFile.Parents = args.Parents
 
+	existing, skip, err := self.resolveUploadConflict(args, dstFile)
+	if err != nil || skip {
+		return nil, 0, err
+	}
+
+	if args.Resume && srcFileInfo.Size() > 0 {
+		return self.uploadFileResumable(srcFile, srcFileInfo, dstFile, existing, args)
+	}
+
 	// Chunk size option
 	chunkSize := googleapi.ChunkSize(int(args.ChunkSize))
 
 	// Wrap file in progress reader
-	progressReader := getProgressReader(srcFile, args.Progress, srcFileInfo.Size())
+	progressReader := getProgressReader(srcFile, args.Progress, srcFileInfo.Size(), args.DecimalUnits)
 
 	// Wrap reader in timeout reader
 	reader, ctx := getTimeoutReaderContext(progressReader, args.Timeout)
@@ -184,12 +393,30 @@ func (self *Drive) uploadFile(args UploadArgs) (*drive.File, int64, error) {
 	fmt.Fprintf(args.Out, "Uploading %s\n", args.Path)
 	started := time.Now()
 
-	f, err := self.service.Files.Create(dstFile).Fields("id", "name", "size", "md5Checksum", "webContentLink").Context(ctx).Media(reader, chunkSize).Do()
+	fields := []googleapi.Field{"id", "name", "size", "md5Checksum", "mimeType", "webContentLink", "parents"}
+
+	var f *drive.File
+	if existing != nil {
+		// The parents of the existing file are kept
+		update := &drive.File{Description: dstFile.Description, MimeType: dstFile.MimeType}
+		f, err = self.service.Files.Update(existing.Id, update).Fields(fields...).Context(ctx).Media(reader, chunkSize).Do()
+	} else {
+		f, err = self.service.Files.Create(dstFile).Fields(fields...).Context(ctx).Media(reader, chunkSize).Do()
+	}
 	if err != nil {
 		if isTimeoutError(err) {
 			return nil, 0, fmt.Errorf("Failed to upload file: timeout, no data was transferred for %v", args.Timeout)
 		}
-		return nil, 0, fmt.Errorf("Failed to upload file: %s", err)
+		if isRequestTimeoutError(err) {
+			return nil, 0, fmt.Errorf("Failed to upload file: timeout, no response within the http timeout")
+		}
+		return nil, 0, wrapError("Failed to upload file", err)
+	}
+
+	if args.NoConvert {
+		if err := checkNotConverted(args.Out, dstFile.MimeType, f); err != nil {
+			return nil, 0, err
+		}
 	}
 
 	// Calculate average upload rate
@@ -198,17 +425,32 @@ func (self *Drive) uploadFile(args UploadArgs) (*drive.File, int64, error) {
 	return f, rate, nil
 }
 
+// Returns an error if the uploaded file was converted to a google document,
+// a mismatching mime type is only reported
+func checkNotConverted(out io.Writer, mimeType string, f *drive.File) error {
+	if strings.HasPrefix(f.MimeType, googleAppsMimePrefix) {
+		return fmt.Errorf("Uploaded file %s was converted to '%s'", f.Id, f.MimeType)
+	}
+
+	if mimeType != "" && f.MimeType != mimeType {
+		fmt.Fprintf(out, "Uploaded file %s has mime type '%s', expected '%s'\n", f.Id, f.MimeType, mimeType)
+	}
+
+	return nil
+}
+
 type UploadStreamArgs struct {
-	Out         io.Writer
-	In          io.Reader
-	Name        string
-	Description string
-	Parents     []string
-	Mime        string
-	Share       bool
-	ChunkSize   int64
-	Progress    io.Writer
-	Timeout     time.Duration
+	Out          io.Writer
+	In           io.Reader
+	Name         string
+	Description  string
+	Parents      []string
+	Mime         string
+	Share        bool
+	ChunkSize    int64
+	Progress     io.Writer
+	Timeout      time.Duration
+	DecimalUnits bool
 }
 
 func (self *Drive) UploadStream(args UploadStreamArgs) error {
@@ -216,6 +458,18 @@ func (self *Drive) UploadStream(args UploadStreamArgs) error {
 		return fmt.Errorf("Chunk size is to big, max chunk size for this computer is %d", intMax()-1)
 	}
 
+	// Parents starting with / are paths
+	var err error
+	args.Parents, err = self.resolvePathsToIds(args.Parents)
+	if err != nil {
+		return err
+	}
+
+	// Ensure that the parents exists before streaming any data
+	if _, err := self.getParents(args.Parents); err != nil {
+		return err
+	}
+
 	// Instantiate empty drive file
 	dstFile := &drive.File{Name: args.Name, Description: args.Description}
 
@@ -231,7 +485,7 @@ func (self *Drive) UploadStream(args UploadStreamArgs) error {
 	chunkSize := googleapi.ChunkSize(int(args.ChunkSize))
 
 	// Wrap file in progress reader
-	progressReader := getProgressReader(args.In, args.Progress, 0)
+	progressReader := getProgressReader(args.In, args.Progress, 0, args.DecimalUnits)
 
 	// Wrap reader in timeout reader
 	reader, ctx := getTimeoutReaderContext(progressReader, args.Timeout)
@@ -244,13 +498,16 @@ func (self *Drive) UploadStream(args UploadStreamArgs) error {
 		if isTimeoutError(err) {
 			return fmt.Errorf("Failed to upload file: timeout, no data was transferred for %v", args.Timeout)
 		}
-		return fmt.Errorf("Failed to upload file: %s", err)
+		if isRequestTimeoutError(err) {
+			return fmt.Errorf("Failed to upload file: timeout, no response within the http timeout")
+		}
+		return wrapError("Failed to upload file", err)
 	}
 
 	// Calculate average upload rate
 	rate := calcRate(f.Size, started, time.Now())
 
-	fmt.Fprintf(args.Out, "Uploaded %s at %s/s, total %s\n", f.Id, formatSize(rate, false), formatSize(f.Size, false))
+	fmt.Fprintf(args.Out, "Uploaded %s at %s/s, total %s\n", f.Id, formatSize(rate, false, args.DecimalUnits), formatSize(f.Size, false, args.DecimalUnits))
 	if args.Share {
 		err = self.shareAnyoneReader(f.Id)
 		if err != nil {
@@ -261,3 +518,42 @@ func (self *Drive) UploadStream(args UploadStreamArgs) error {
 	}
 	return nil
 }
+
+// Print the path of the file in each of its parents
+func (self *Drive) printParentPaths(out io.Writer, f *drive.File) error {
+	pathfinder := self.newPathfinder()
+
+	for _, parentId := range f.Parents {
+		path, err := pathfinder.absPath(&drive.File{Name: f.Name, Parents: []string{parentId}})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Available at %s\n", path)
+	}
+
+	return nil
+}
+
+// Get parent directories, fails if any of the parents
+// does not exist or is not a directory
+func (self *Drive) getParents(ids []string) ([]*drive.File, error) {
+	var parents []*drive.File
+
+	for _, id := range ids {
+		f, err := self.service.Files.Get(id).Fields("id", "name", "mimeType", "appProperties").Do()
+		if err != nil {
+			if isNotFoundError(err) {
+				return nil, fmt.Errorf("Parent %s not found", id)
+			}
+			return nil, fmt.Errorf("Failed to get parent %s: %s", id, err)
+		}
+
+		if !isDir(f) {
+			return nil, fmt.Errorf("Parent %s is not a folder", id)
+		}
+
+		parents = append(parents, f)
+	}
+
+	return parents, nil
+}
@@ -0,0 +1,94 @@
+package drive
+
+import (
+	"fmt"
+	"google.golang.org/api/drive/v3"
+	"path/filepath"
+	"strings"
+)
+
+// Ways to handle a file with the same name in the parent when uploading,
+// an empty strategy uploads a duplicate
+var conflictStrategies = []string{"rename", "skip", "replace"}
+
+func checkConflictStrategy(strategy string) error {
+	if strategy == "" || containsString(conflictStrategies, strategy) {
+		return nil
+	}
+	return fmt.Errorf("Unknown conflict strategy '%s', available strategies: %s", strategy, formatList(conflictStrategies))
+}
+
+// Resolves a name conflict of the file about to be uploaded in its first parent.
+// The name is changed when renaming, and the existing file is returned when it
+// should be replaced. Skip is true if the file should not be uploaded at all.
+// Conflicts are only looked up, so it is also used by dry runs
+func (self *Drive) resolveUploadConflict(args UploadArgs, dstFile *drive.File) (existing *drive.File, skip bool, err error) {
+	if args.OnConflict == "" {
+		return nil, false, nil
+	}
+
+	parent := "root"
+	if len(dstFile.Parents) > 0 {
+		parent = dstFile.Parents[0]
+	}
+
+	files, err := self.findFilesByName(dstFile.Name, parent)
+	if err != nil || len(files) == 0 {
+		return nil, false, err
+	}
+
+	switch args.OnConflict {
+	case "skip":
+		printConflict(args, "Skipping", "skip", "%s, '%s' already exists with id %s", args.Path, dstFile.Name, files[0].Id)
+		return nil, true, nil
+
+	case "replace":
+		if len(files) > 1 {
+			var ids []string
+			for _, f := range files {
+				ids = append(ids, f.Id)
+			}
+			return nil, false, fmt.Errorf("Ambiguous file name '%s', found %d files: %s", dstFile.Name, len(ids), formatList(ids))
+		}
+		printConflict(args, "Replacing", "replace", "'%s' with id %s", dstFile.Name, files[0].Id)
+		return files[0], false, nil
+	}
+
+	ext := filepath.Ext(dstFile.Name)
+	base := strings.TrimSuffix(dstFile.Name, ext)
+
+	for i := 2; ; i++ {
+		name := fmt.Sprintf("%s (%d)%s", base, i, ext)
+
+		files, err := self.findFilesByName(name, parent)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if len(files) == 0 {
+			printConflict(args, "Renaming", "rename", "%s to %s, the name is already used", dstFile.Name, name)
+			dstFile.Name = name
+			return nil, false, nil
+		}
+	}
+}
+
+// Prints the resolved conflict, or what would be done in a dry run
+func printConflict(args UploadArgs, action, dryRunAction, format string, a ...interface{}) {
+	if args.DryRun {
+		printDryRun(args.Out, dryRunAction+" "+format, a...)
+		return
+	}
+	fmt.Fprintf(args.Out, action+" "+format+"\n", a...)
+}
+
+// Directories are not considered, as uploaded files never replace them
+func (self *Drive) findFilesByName(name, parent string) ([]*drive.File, error) {
+	query := fmt.Sprintf("name = '%s' and '%s' in parents and trashed = false and mimeType != '%s'", escapeQueryValue(name), escapeQueryValue(parent), DirectoryMimeType)
+
+	fileList, err := self.service.Files.List().Q(query).Fields("files(id,name)").Do()
+	if err != nil {
+		return nil, wrapError("Failed to list files", err)
+	}
+	return fileList.Files, nil
+}
@@ -0,0 +1,217 @@
+package drive
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestUploadDryRunResolvesConflicts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gdrive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "a.txt")
+	if err := ioutil.WriteFile(path, []byte("content"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]string{
+		"":        "Dry run, would upload " + path + " as 'a.txt' to parent, total 7.0 B\n",
+		"skip":    "Dry run, would skip " + path + ", 'a.txt' already exists with id existing\n",
+		"replace": "Dry run, would replace 'a.txt' with id existing\n",
+		"rename": "Dry run, would rename a.txt to a (3).txt, the name is already used\n" +
+			"Dry run, would upload " + path + " as 'a (3).txt' to parent, total 7.0 B\n",
+	}
+
+	for strategy, expected := range cases {
+		d, fake := newFakeDrive(t,
+			fakeFolder("parent", "parent"),
+			fakeBinary("existing", "a.txt", "old", "parent"),
+			fakeBinary("renamed", "a (2).txt", "old", "parent"),
+			fakeBinary("other", "a (3).txt", "old", "root"),
+		)
+
+		out := &bytes.Buffer{}
+		err := d.Upload(UploadArgs{
+			Out:        out,
+			Path:       path,
+			Parents:    []string{"parent"},
+			DryRun:     true,
+			OnConflict: strategy,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if out.String() != expected {
+			t.Errorf("strategy %q: got %q, want %q", strategy, out.String(), expected)
+		}
+
+		for _, r := range fake.requests {
+			if r.Method != "GET" {
+				t.Errorf("strategy %q: dry run sent %s %s", strategy, r.Method, r.URL)
+			}
+		}
+	}
+}
+
+// Uploading the directory again must not create a second directory tree
+func TestUploadRecursiveReusesDirectories(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gdrive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "photos")
+	if err := os.MkdirAll(filepath.Join(src, "2020"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.jpg", filepath.Join("2020", "b.jpg")} {
+		if err := ioutil.WriteFile(filepath.Join(src, name), []byte(name), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The strategy applies to the files in the existing directories,
+	// names are sorted as directories are read in any order
+	expectedFiles := map[string]string{
+		"rename":  "a (2).jpg,a.jpg,b (2).jpg,b.jpg",
+		"skip":    "a.jpg,b.jpg",
+		"replace": "a.jpg,b.jpg",
+	}
+
+	for _, strategy := range conflictStrategies {
+		d, fake := newFakeDrive(t, fakeFolder("parent", "parent"))
+
+		for i := 0; i < 2; i++ {
+			err := d.Upload(UploadArgs{
+				Out:        ioutil.Discard,
+				Progress:   ioutil.Discard,
+				Path:       src,
+				Parents:    []string{"parent"},
+				Recursive:  true,
+				OnConflict: strategy,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		var dirs, files []string
+		for _, id := range fake.order {
+			if f := fake.files[id]; isDir(&f.File) {
+				dirs = append(dirs, f.Name)
+			} else {
+				files = append(files, f.Name)
+			}
+		}
+		if strings.Join(dirs, ",") != "parent,photos,2020" {
+			t.Errorf("strategy %s: got directories %v, want parent, photos and 2020", strategy, dirs)
+		}
+		sort.Strings(files)
+		if strings.Join(files, ",") != expectedFiles[strategy] {
+			t.Errorf("strategy %s: got files %v, want %s", strategy, files, expectedFiles[strategy])
+		}
+	}
+}
+
+func TestUploadDryRunRecursiveConflicts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gdrive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "photos")
+	if err := os.MkdirAll(filepath.Join(src, "2020"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.jpg", "new.jpg", filepath.Join("2020", "b.jpg")} {
+		if err := ioutil.WriteFile(filepath.Join(src, name), []byte(name), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d, fake := newFakeDrive(t,
+		fakeFolder("parent", "parent"),
+		fakeFolder("photos", "photos", "parent"),
+		fakeBinary("a", "a.jpg", "old", "photos"),
+	)
+
+	out := &bytes.Buffer{}
+	err = d.Upload(UploadArgs{
+		Out:        out,
+		Path:       src,
+		Parents:    []string{"parent"},
+		Recursive:  true,
+		DryRun:     true,
+		OnConflict: "skip",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "Dry run, would upload directory " + src + " to parent, 3 files in 2 directories, total 22.0 B\n" +
+		"Dry run, would upload " + src + " into existing directory with id photos\n" +
+		"Dry run, would skip " + filepath.Join(src, "a.jpg") + ", 'a.jpg' already exists with id a\n"
+	if out.String() != expected {
+		t.Errorf("got %q, want %q", out.String(), expected)
+	}
+
+	for _, r := range fake.requests {
+		if r.Method != "GET" {
+			t.Errorf("dry run sent %s %s", r.Method, r.URL)
+		}
+	}
+}
+
+// A resumed upload replacing a file must update the existing file, not create
+// a second file with the same name
+func TestUploadResumeReplacesExisting(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gdrive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "a.txt")
+	if err := ioutil.WriteFile(path, []byte("new content"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	d, fake := newFakeDrive(t,
+		fakeFolder("parent", "parent"),
+		fakeBinary("existing", "a.txt", "old", "parent"),
+	)
+
+	err = d.Upload(UploadArgs{
+		Out:          &bytes.Buffer{},
+		Progress:     ioutil.Discard,
+		Path:         path,
+		Parents:      []string{"parent"},
+		Resume:       true,
+		SessionsPath: filepath.Join(dir, "sessions.json"),
+		OnConflict:   "replace",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fake.files) != 2 {
+		t.Fatalf("got %d files, want the folder and the replaced file", len(fake.files))
+	}
+	if content := fake.files["existing"].content; content != "new content" {
+		t.Errorf("got content %q, want the new content", content)
+	}
+	if sessions := fake.sessions; len(sessions) != 1 || sessions[0].existing != "existing" {
+		t.Errorf("expected a single upload session replacing the existing file")
+	}
+}
@@ -0,0 +1,442 @@
+package drive
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"golang.org/x/net/context"
+	"golang.org/x/net/context/ctxhttp"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Drive invalidates resumable upload sessions after about a week
+const uploadSessionMaxAge = 7 * 24 * time.Hour
+
+const uploadResumeIncomplete = 308
+
+var uploadFileFields = []string{"id", "name", "size", "md5Checksum", "mimeType", "webContentLink", "parents"}
+
+// Resumable upload session stored in the config dir,
+// so that an interrupted upload can be resumed by the next invocation
+type uploadSession struct {
+	Uri          string    `json:"uri"`
+	Path         string    `json:"path"`
+	Size         int64     `json:"size"`
+	ModifiedTime time.Time `json:"modifiedTime"`
+	Offset       int64     `json:"offset"`
+	Created      time.Time `json:"created"`
+}
+
+type ClearUploadSessionsArgs struct {
+	Out          io.Writer
+	SessionsPath string
+}
+
+// Only the local sessions file is changed, no drive client is needed
+func ClearUploadSessions(args ClearUploadSessionsArgs) error {
+	sessions, err := readUploadSessions(args.SessionsPath)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(args.SessionsPath); err != nil && !os.IsNotExist(err) {
+		return wrapError("Failed to remove upload sessions", err)
+	}
+
+	fmt.Fprintf(args.Out, "Removed %d upload sessions\n", len(sessions))
+	return nil
+}
+
+// Uploads the file with a resumable session which is kept if the upload is interrupted,
+// uploading the same unchanged file again continues from the last byte received by drive.
+// The content of the existing file is replaced if given
+func (self *Drive) uploadFileResumable(srcFile *os.File, srcFileInfo os.FileInfo, dstFile, existing *drive.File, args UploadArgs) (*drive.File, int64, error) {
+	absPath, err := filepath.Abs(args.Path)
+	if err != nil {
+		return nil, 0, wrapError("Failed to determine local absolute path", err)
+	}
+
+	var existingId string
+	if existing != nil {
+		existingId = existing.Id
+	}
+
+	key := uploadSessionKey(absPath, srcFileInfo.Size(), dstFile, existingId)
+
+	session, err := self.getUploadSession(args, key, srcFileInfo)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if session == nil {
+		session, err = self.newUploadSession(args, key, absPath, srcFileInfo, dstFile, existingId)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	fmt.Fprintf(args.Out, "Uploading %s\n", args.Path)
+	started := time.Now()
+
+	f, err := self.resumeUpload(srcFile, session, key, args, 0)
+	if isUploadSessionExpired(err) {
+		fmt.Fprintf(args.Out, "Upload session expired, restarting upload of %s\n", args.Path)
+
+		session, err = self.newUploadSession(args, key, absPath, srcFileInfo, dstFile, existingId)
+		if err != nil {
+			return nil, 0, err
+		}
+		f, err = self.resumeUpload(srcFile, session, key, args, 0)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := removeUploadSession(args.SessionsPath, key); err != nil {
+		return nil, 0, err
+	}
+
+	if args.NoConvert {
+		if err := checkNotConverted(args.Out, dstFile.MimeType, f); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	// Calculate average upload rate
+	rate := calcRate(f.Size, started, time.Now())
+
+	return f, rate, nil
+}
+
+// Returns the stored session for the file or nil if there is none,
+// sessions for files that changed since the upload started are discarded
+func (self *Drive) getUploadSession(args UploadArgs, key string, info os.FileInfo) (*uploadSession, error) {
+	sessions, err := readUploadSessions(args.SessionsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	session, ok := sessions[key]
+	if !ok {
+		return nil, nil
+	}
+
+	if !session.ModifiedTime.Equal(info.ModTime()) {
+		fmt.Fprintf(args.Out, "Local file changed, restarting upload of %s\n", args.Path)
+		return nil, nil
+	}
+
+	return session, nil
+}
+
+func (self *Drive) newUploadSession(args UploadArgs, key, absPath string, info os.FileInfo, dstFile *drive.File, existingId string) (*uploadSession, error) {
+	uri, err := self.createUploadSession(dstFile, existingId, info.Size())
+	if err != nil {
+		return nil, wrapError("Failed to create upload session", err)
+	}
+
+	session := &uploadSession{
+		Uri:          uri,
+		Path:         absPath,
+		Size:         info.Size(),
+		ModifiedTime: info.ModTime(),
+		Created:      time.Now(),
+	}
+
+	if err := writeUploadSession(args.SessionsPath, key, session); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (self *Drive) resumeUpload(srcFile *os.File, session *uploadSession, key string, args UploadArgs, try int) (*drive.File, error) {
+	// Ask drive how much data it has received, it may be less than what was sent
+	offset, f, err := self.uploadSessionStatus(session)
+	if err != nil {
+		if isBackendOrRateLimitError(err) && try < MaxErrorRetries {
+			exponentialBackoffSleep(try)
+			return self.resumeUpload(srcFile, session, key, args, try+1)
+		}
+		return nil, wrapError("Failed to get upload status", err)
+	}
+
+	// The previous invocation was interrupted after the last chunk was received
+	if f != nil {
+		return f, nil
+	}
+
+	if offset > 0 {
+		fmt.Fprintf(args.Out, "Resuming upload at %s\n", formatSize(offset, false, args.DecimalUnits))
+	}
+
+	// Wrap the remaining data in progress and timeout readers
+	remaining := io.NewSectionReader(srcFile, offset, session.Size-offset)
+	progressReader := getProgressReader(remaining, args.Progress, session.Size-offset, args.DecimalUnits)
+	reader, ctx := getTimeoutReaderContext(progressReader, args.Timeout)
+
+	// Data not yet received by drive is kept at the start of the buffer
+	buf := make([]byte, 0, uploadChunkSize(args.ChunkSize, session.Size-offset))
+
+	for {
+		n, err := io.ReadFull(reader, buf[len(buf):cap(buf)])
+		buf = buf[:len(buf)+n]
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return nil, wrapError("Failed to read file", err)
+		}
+
+		if len(buf) == 0 {
+			return nil, fmt.Errorf("Failed to upload file: %s changed during upload", session.Path)
+		}
+
+		received, f, err := self.uploadChunk(ctx, session, buf, offset)
+		if err != nil {
+			if isTimeoutError(err) {
+				return nil, fmt.Errorf("Upload was interrupted: timeout, no data was transferred for %v, run the command again to resume", args.Timeout)
+			}
+			// Connection errors are retried as well as backend errors
+			if _, ok := apiError(err); (!ok || isBackendOrRateLimitError(err)) && try < MaxErrorRetries {
+				exponentialBackoffSleep(try)
+				return self.resumeUpload(srcFile, session, key, args, try+1)
+			}
+			return nil, wrapError("Upload was interrupted, run the command again to resume", err)
+		}
+
+		if f != nil {
+			return f, nil
+		}
+
+		// Start over from the data received by drive if it is outside of the chunk
+		if received < offset || received > offset+int64(len(buf)) {
+			if try < MaxErrorRetries {
+				return self.resumeUpload(srcFile, session, key, args, try+1)
+			}
+			return nil, fmt.Errorf("Upload was interrupted: unexpected upload status, run the command again to resume")
+		}
+
+		buf = buf[:copy(buf, buf[received-offset:])]
+		offset = received
+
+		// Errors are retried by chunk, not for the whole upload
+		try = 0
+
+		session.Offset = offset
+		if err := writeUploadSession(args.SessionsPath, key, session); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Chunks must be a multiple of 256 KiB except for the last one,
+// the whole file is sent in one chunk if the chunk size is 0
+func uploadChunkSize(chunkSize, remaining int64) int64 {
+	if chunkSize <= 0 || chunkSize > remaining {
+		return remaining
+	}
+
+	if chunkSize%googleapi.MinUploadChunkSize != 0 {
+		chunkSize += googleapi.MinUploadChunkSize - chunkSize%googleapi.MinUploadChunkSize
+	}
+
+	return chunkSize
+}
+
+// Starts a resumable upload and returns the session uri. The upload replaces
+// the content of the existing file if an id is given, its parents are kept
+func (self *Drive) createUploadSession(dstFile *drive.File, existingId string, size int64) (string, error) {
+	method := "POST"
+	path := "files"
+	metadata := dstFile
+
+	if existingId != "" {
+		method = "PATCH"
+		path = "files/{fileId}"
+		metadata = &drive.File{Description: dstFile.Description, MimeType: dstFile.MimeType}
+	}
+
+	body, err := json.Marshal(metadata)
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{}
+	params.Set("uploadType", "resumable")
+	params.Set("fields", strings.Join(uploadFileFields, ","))
+
+	urls := googleapi.ResolveRelative(self.service.BasePath, "/upload/drive/v3/"+path)
+
+	req, _ := http.NewRequest(method, urls+"?"+params.Encode(), bytes.NewReader(body))
+	googleapi.Expand(req.URL, map[string]string{
+		"fileId": existingId,
+	})
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	req.Header.Set("X-Upload-Content-Length", strconv.FormatInt(size, 10))
+	if dstFile.MimeType != "" {
+		req.Header.Set("X-Upload-Content-Type", dstFile.MimeType)
+	}
+
+	res, err := self.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if err := googleapi.CheckResponse(res); err != nil {
+		return "", err
+	}
+
+	uri := res.Header.Get("Location")
+	if uri == "" {
+		return "", fmt.Errorf("No session uri in response")
+	}
+
+	return uri, nil
+}
+
+func (self *Drive) uploadSessionStatus(session *uploadSession) (int64, *drive.File, error) {
+	req, _ := http.NewRequest("PUT", session.Uri, nil)
+	req.Header.Set("Content-Range", fmt.Sprintf("bytes */%d", session.Size))
+
+	res, err := self.client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return parseUploadResponse(res)
+}
+
+func (self *Drive) uploadChunk(ctx context.Context, session *uploadSession, chunk []byte, offset int64) (int64, *drive.File, error) {
+	req, _ := http.NewRequest("PUT", session.Uri, bytes.NewReader(chunk))
+	req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, offset+int64(len(chunk))-1, session.Size))
+
+	res, err := ctxhttp.Do(ctx, self.client, req)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return parseUploadResponse(res)
+}
+
+// Returns the number of bytes received by drive if the upload is incomplete,
+// or the uploaded file when it is complete
+func parseUploadResponse(res *http.Response) (int64, *drive.File, error) {
+	defer res.Body.Close()
+
+	if res.StatusCode == uploadResumeIncomplete {
+		return receivedBytes(res.Header.Get("Range")), nil, nil
+	}
+
+	if err := googleapi.CheckResponse(res); err != nil {
+		return 0, nil, err
+	}
+
+	f := &drive.File{}
+	if err := json.NewDecoder(res.Body).Decode(f); err != nil {
+		return 0, nil, err
+	}
+
+	return f.Size, f, nil
+}
+
+// The range header has the form bytes=0-<last byte received>
+// and is missing if no data has been received
+func receivedBytes(header string) int64 {
+	i := strings.LastIndex(header, "-")
+	if i == -1 {
+		return 0
+	}
+
+	last, err := strconv.ParseInt(header[i+1:], 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return last + 1
+}
+
+func isUploadSessionExpired(err error) bool {
+	ae, ok := apiError(err)
+	return ok && (ae.Code == 404 || ae.Code == 410)
+}
+
+// Uploads of the same file with another name, mime type or parents, and uploads
+// replacing a file are kept apart, as the metadata is given when the session is created
+func uploadSessionKey(absPath string, size int64, dstFile *drive.File, existingId string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s:%d:%s:%s:%s:%s", absPath, size, strings.Join(dstFile.Parents, ","), dstFile.Name, dstFile.MimeType, existingId)))
+	return hex.EncodeToString(sum[:])
+}
+
+// Returns the stored sessions, expired sessions are left out
+func readUploadSessions(path string) (map[string]*uploadSession, error) {
+	sessions := map[string]*uploadSession{}
+
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return sessions, nil
+	}
+	if err != nil {
+		return nil, wrapError("Failed to read upload sessions", err)
+	}
+
+	if err := json.Unmarshal(content, &sessions); err != nil {
+		return nil, wrapError("Failed to read upload sessions", err)
+	}
+
+	for key, session := range sessions {
+		if time.Since(session.Created) > uploadSessionMaxAge {
+			delete(sessions, key)
+		}
+	}
+
+	return sessions, nil
+}
+
+func writeUploadSessions(path string, sessions map[string]*uploadSession) error {
+	data, err := json.MarshalIndent(sessions, "", "  ")
+	if err != nil {
+		return wrapError("Failed to save upload sessions", err)
+	}
+
+	if err := mkdir(path); err != nil {
+		return wrapError("Failed to save upload sessions", err)
+	}
+
+	// The session uri allows uploading without further authentication
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		return wrapError("Failed to save upload sessions", err)
+	}
+
+	return nil
+}
+
+func writeUploadSession(path, key string, session *uploadSession) error {
+	sessions, err := readUploadSessions(path)
+	if err != nil {
+		return err
+	}
+
+	sessions[key] = session
+	return writeUploadSessions(path, sessions)
+}
+
+func removeUploadSession(path, key string) error {
+	sessions, err := readUploadSessions(path)
+	if err != nil {
+		return err
+	}
+
+	delete(sessions, key)
+	return writeUploadSessions(path, sessions)
+}
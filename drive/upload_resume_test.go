@@ -0,0 +1,77 @@
+package drive
+
+import (
+	"bytes"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// A session is only resumed by an upload with the same destination and metadata
+func TestUploadSessionKey(t *testing.T) {
+	dstFile := &drive.File{Name: "a.txt", MimeType: "text/plain", Parents: []string{"p1"}}
+	key := uploadSessionKey("/tmp/a.txt", 10, dstFile, "")
+
+	others := map[string]string{
+		"size":     uploadSessionKey("/tmp/a.txt", 11, dstFile, ""),
+		"existing": uploadSessionKey("/tmp/a.txt", 10, dstFile, "existing"),
+		"parent":   uploadSessionKey("/tmp/a.txt", 10, &drive.File{Name: "a.txt", MimeType: "text/plain", Parents: []string{"p2"}}, ""),
+		"name":     uploadSessionKey("/tmp/a.txt", 10, &drive.File{Name: "b.txt", MimeType: "text/plain", Parents: []string{"p1"}}, ""),
+		"mime":     uploadSessionKey("/tmp/a.txt", 10, &drive.File{Name: "a.txt", MimeType: "text/csv", Parents: []string{"p1"}}, ""),
+	}
+	for name, other := range others {
+		if other == key {
+			t.Errorf("another %s gives the same session key", name)
+		}
+	}
+
+	if other := uploadSessionKey("/tmp/a.txt", 10, &drive.File{Name: "a.txt", MimeType: "text/plain", Parents: []string{"p1"}}, ""); other != key {
+		t.Errorf("the same upload gives another session key")
+	}
+}
+
+// Every chunk fails once, more errors than retries in total must not stop
+// the upload. The session is created at the upload endpoint of the api
+func TestUploadResumeRetriesEachChunk(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gdrive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	content := strings.Repeat("x", (MaxErrorRetries+1)*googleapi.MinUploadChunkSize)
+	path := filepath.Join(dir, "a.txt")
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	d, fake := newFakeDrive(t, fakeFolder("parent", "parent"))
+	fake.chunkErrors = MaxErrorRetries + 1
+
+	err = d.Upload(UploadArgs{
+		Out:          &bytes.Buffer{},
+		Progress:     ioutil.Discard,
+		Path:         path,
+		Parents:      []string{"parent"},
+		Resume:       true,
+		ChunkSize:    googleapi.MinUploadChunkSize,
+		SessionsPath: filepath.Join(dir, "sessions.json"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fake.chunkErrors != 0 {
+		t.Errorf("%d chunk errors left, every chunk should have failed once", fake.chunkErrors)
+	}
+	if n := len(fake.requestsTo("upload/drive/v3/files")); n != 1 {
+		t.Errorf("got %d requests to the upload endpoint, want 1", n)
+	}
+	if len(fake.sessions) != 1 || fake.sessions[0].file.content != content {
+		t.Errorf("expected the whole file to be uploaded in a single session")
+	}
+}
@@ -0,0 +1,83 @@
+package drive
+
+import (
+	"fmt"
+	"golang.org/x/net/context/ctxhttp"
+	"io"
+	"net/http"
+	"path"
+	"time"
+)
+
+type UploadUrlArgs struct {
+	Out io.Writer
+	// Client used to get the url, defaults to http.DefaultClient
+	Client      *http.Client
+	Url         string
+	Name        string
+	Description string
+	Parents     []string
+	Mime        string
+	Share       bool
+	ChunkSize   int64
+	Progress    io.Writer
+	Timeout     time.Duration
+}
+
+// Streams the response body of the url to a new file without storing it on disk
+func (self *Drive) UploadUrl(args UploadUrlArgs) error {
+	// A separate client is used to not send the drive credentials to the remote host,
+	// redirects are followed by default
+	client := args.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequest("GET", args.Url, nil)
+	if err != nil {
+		return wrapError("Failed to get url", err)
+	}
+
+	// Cancel the request if no data is received within the timeout
+	timeoutReader, ctx := getTimeoutReaderWrapperContext(args.Timeout)
+
+	res, err := ctxhttp.Do(ctx, client, req)
+	if err != nil {
+		return wrapError("Failed to get url", err)
+	}
+
+	// Close body on function exit
+	defer res.Body.Close()
+
+	// Fail before any file is created on drive
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("Failed to get url: %s", res.Status)
+	}
+
+	name := args.Name
+	if name == "" {
+		name = urlFilename(res)
+	}
+
+	return self.UploadStream(UploadStreamArgs{
+		Out:         args.Out,
+		In:          timeoutReader(res.Body),
+		Name:        name,
+		Description: args.Description,
+		Parents:     args.Parents,
+		Mime:        args.Mime,
+		Share:       args.Share,
+		ChunkSize:   args.ChunkSize,
+		Progress:    args.Progress,
+		Timeout:     args.Timeout,
+	})
+}
+
+// Use the last path element of the final url after redirects as filename
+func urlFilename(res *http.Response) string {
+	name := path.Base(res.Request.URL.Path)
+	if name == "/" || name == "." {
+		return res.Request.URL.Host
+	}
+	return name
+}
@@ -16,11 +16,25 @@ type kv struct {
 	value string
 }
 
+func containsString(a []string, s string) bool {
+	for _, v := range a {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
 func formatList(a []string) string {
 	return strings.Join(a, ", ")
 }
 
-func formatSize(bytes int64, forceBytes bool) string {
+var binarySizeUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+var decimalSizeUnits = []string{"B", "KB", "MB", "GB", "TB", "PB", "EB"}
+
+// Sizes are shown in binary units (KiB, MiB) unless decimal units (KB, MB)
+// are given, or in bytes when forcing bytes
+func formatSize(bytes int64, forceBytes, decimal bool) string {
 	if bytes == 0 {
 		return ""
 	}
@@ -29,13 +43,18 @@ func formatSize(bytes int64, forceBytes bool) string {
 		return fmt.Sprintf("%v B", bytes)
 	}
 
-	units := []string{"B", "KB", "MB", "GB", "TB", "PB"}
+	base, units := 1024.0, binarySizeUnits
+	if decimal {
+		base, units = 1000.0, decimalSizeUnits
+	}
 
 	var i int
 	value := float64(bytes)
 
-	for value > 1000 {
-		value /= 1000
+	// Values that would be rounded up to the base are shown in the next unit,
+	// i.e. 999999 bytes is shown as 1.0 MB instead of 1000.0 KB
+	for value >= base-0.05 && i < len(units)-1 {
+		value /= base
 		i++
 	}
 	return fmt.Sprintf("%.1f %s", value, units[i])
@@ -60,15 +79,44 @@ func formatBool(b bool) string {
 	return strings.Title(strconv.FormatBool(b))
 }
 
+const defaultTimeLayout = "2006-01-02 15:04:05"
+
+var timeFormatPresets = map[string]string{
+	"rfc3339":  time.RFC3339,
+	"date":     "2006-01-02",
+	"datetime": defaultTimeLayout,
+}
+
+// Returns the go layout for the named preset, any other value is used as a go layout.
+// The default layout is used if no format is given
+func parseTimeFormat(format string) (string, error) {
+	if format == "" {
+		return defaultTimeLayout, nil
+	}
+
+	if layout, ok := timeFormatPresets[strings.ToLower(format)]; ok {
+		return layout, nil
+	}
+
+	// A layout without any time elements formats every time as the layout itself
+	if time.Date(2001, 3, 4, 7, 8, 9, 0, time.UTC).Format(format) == format {
+		return "", fmt.Errorf("Invalid time format '%s', use rfc3339, date, datetime or a go layout like '2006-01-02 15:04'", format)
+	}
+
+	return format, nil
+}
+
 func formatDatetime(iso string) string {
+	return formatTime(iso, defaultTimeLayout)
+}
+
+// Formats the RFC 3339 time in local time, returns the value as is if it can not be parsed
+func formatTime(iso, layout string) string {
 	t, err := time.Parse(time.RFC3339, iso)
 	if err != nil {
 		return iso
 	}
-	local := t.Local()
-	year, month, day := local.Date()
-	hour, min, sec := local.Clock()
-	return fmt.Sprintf("%04d-%02d-%02d %02d:%02d:%02d", year, month, day, hour, min, sec)
+	return t.Local().Format(layout)
 }
 
 // Truncates string to given max length, and inserts ellipsis into
@@ -149,20 +197,22 @@ func pow(x int, y int) int {
 	return int(f)
 }
 
-func min(x int, y int) int {
-	n := math.Min(float64(x), float64(y))
-	return int(n)
+func min64(x int64, y int64) int64 {
+	if x < y {
+		return x
+	}
+	return y
 }
 
 func openFile(path string) (*os.File, os.FileInfo, error) {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, nil, fmt.Errorf("Failed to open file: %s", err)
+		return nil, nil, wrapError("Failed to open file", err)
 	}
 
 	info, err := f.Stat()
 	if err != nil {
-		return nil, nil, fmt.Errorf("Failed getting file metadata: %s", err)
+		return nil, nil, wrapError("Failed getting file metadata", err)
 	}
 
 	return f, info, nil
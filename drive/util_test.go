@@ -0,0 +1,88 @@
+package drive
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFormatSize(t *testing.T) {
+	cases := []struct {
+		bytes   int64
+		binary  string
+		decimal string
+	}{
+		{0, "", ""},
+		{1, "1.0 B", "1.0 B"},
+		{999, "999.0 B", "999.0 B"},
+		{1000, "1000.0 B", "1.0 KB"},
+		{1001, "1001.0 B", "1.0 KB"},
+		{1023, "1023.0 B", "1.0 KB"},
+		{1024, "1.0 KiB", "1.0 KB"},
+		{1025, "1.0 KiB", "1.0 KB"},
+		{999949, "976.5 KiB", "999.9 KB"},
+		{999999, "976.6 KiB", "1.0 MB"},
+		{1000000, "976.6 KiB", "1.0 MB"},
+		{1000001, "976.6 KiB", "1.0 MB"},
+		{1048575, "1.0 MiB", "1.0 MB"},
+		{1048576, "1.0 MiB", "1.0 MB"},
+		{1048577, "1.0 MiB", "1.0 MB"},
+	}
+
+	for _, c := range cases {
+		if s := formatSize(c.bytes, false, false); s != c.binary {
+			t.Errorf("binary %d: got %q, want %q", c.bytes, s, c.binary)
+		}
+
+		if s := formatSize(c.bytes, false, true); s != c.decimal {
+			t.Errorf("decimal %d: got %q, want %q", c.bytes, s, c.decimal)
+		}
+	}
+}
+
+func TestFormatSizeForceBytes(t *testing.T) {
+	cases := map[int64]string{
+		0:       "",
+		1023:    "1023 B",
+		1024:    "1024 B",
+		1000000: "1000000 B",
+	}
+
+	for bytes, expected := range cases {
+		if s := formatSize(bytes, true, false); s != expected {
+			t.Errorf("%d: got %q, want %q", bytes, s, expected)
+		}
+	}
+}
+
+func TestFormatSizeLarge(t *testing.T) {
+	cases := map[int64]string{
+		1 << 32:       "4.0 GiB",
+		1 << 40:       "1.0 TiB",
+		1 << 60:       "1.0 EiB",
+		math.MaxInt64: "8.0 EiB",
+	}
+
+	for bytes, expected := range cases {
+		if s := formatSize(bytes, false, false); s != expected {
+			t.Errorf("%d: got %q, want %q", bytes, s, expected)
+		}
+	}
+}
+
+func TestMin64(t *testing.T) {
+	cases := []struct {
+		a, b, min int64
+	}{
+		{1, 2, 1},
+		{2, 1, 1},
+		{1000, 1<<32 + 1, 1000},
+		{math.MaxInt64, 1000, 1000},
+		{math.MinInt64, math.MaxInt64, math.MinInt64},
+	}
+
+	for _, c := range cases {
+		if min := min64(c.a, c.b); min != c.min {
+			t.Errorf("min64(%d, %d): got %d, want %d", c.a, c.b, min, c.min)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package drive
+
+import (
+	"fmt"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+	"io"
+	"path/filepath"
+	"sort"
+)
+
+type VerifyArgs struct {
+	Out      io.Writer
+	Path     string
+	RootId   string
+	Comparer FileComparer
+	// Record the size and checksums of matching files in a csv file
+	ManifestPath string
+}
+
+// Compares a local directory with a remote directory without transferring any files,
+// an error is returned if any of the files differ or only exist on one side
+func (self *Drive) Verify(args VerifyArgs) error {
+	rootDir, err := self.service.Files.Get(args.RootId).Fields("id", "name", "mimeType").Do()
+	if err != nil {
+		return wrapError("Failed to get file", err)
+	}
+
+	if !isDir(rootDir) {
+		return fmt.Errorf("'%s' is not a directory", rootDir.Name)
+	}
+
+	localFiles, err := prepareLocalFiles(args.Path)
+	if err != nil {
+		return err
+	}
+
+	var manifest *downloadManifest
+	if args.ManifestPath != "" {
+		manifest, err = newDownloadManifest(args.ManifestPath)
+		if err != nil {
+			return err
+		}
+		defer manifest.close()
+	}
+
+	remoteFiles, err := self.listRemoteTree(rootDir, "")
+	if err != nil {
+		return err
+	}
+
+	remoteByPath := map[string]*RemoteFile{}
+	for _, rf := range remoteFiles {
+		remoteByPath[rf.relPath] = rf
+	}
+
+	var discrepancies []string
+	seen := map[string]bool{}
+
+	for _, lf := range localFiles {
+		if lf.info.IsDir() {
+			continue
+		}
+
+		seen[lf.relPath] = true
+
+		rf, found := remoteByPath[lf.relPath]
+		if !found {
+			discrepancies = append(discrepancies, fmt.Sprintf("Missing remotely: %s", lf.relPath))
+			continue
+		}
+
+		// Google documents have no checksum to compare with
+		if !isBinary(rf.file) {
+			continue
+		}
+
+		if args.Comparer.Changed(lf, rf) {
+			discrepancies = append(discrepancies, fmt.Sprintf("Differs: %s", lf.relPath))
+			continue
+		}
+
+		if err := manifest.record(filepath.Join(args.Path, lf.relPath), rf.Md5()); err != nil {
+			discrepancies = append(discrepancies, fmt.Sprintf("Differs: %s: %s", lf.relPath, err))
+		}
+	}
+
+	for _, rf := range remoteFiles {
+		// Google documents are never stored locally with the same name
+		if !seen[rf.relPath] && isBinary(rf.file) {
+			discrepancies = append(discrepancies, fmt.Sprintf("Missing locally: %s", rf.relPath))
+		}
+	}
+
+	if len(discrepancies) == 0 {
+		fmt.Fprintf(args.Out, "All %d files match\n", len(seen))
+		return nil
+	}
+
+	sort.Strings(discrepancies)
+	for _, d := range discrepancies {
+		fmt.Fprintln(args.Out, d)
+	}
+
+	return fmt.Errorf("Found %d discrepancies", len(discrepancies))
+}
+
+// Recursively lists all files below the given directory, directories are not included
+func (self *Drive) listRemoteTree(parent *drive.File, relPath string) ([]*RemoteFile, error) {
+	listArgs := listAllFilesArgs{
+		query:  fmt.Sprintf("'%s' in parents and trashed = false", parent.Id),
+		fields: []googleapi.Field{"nextPageToken", "files(id,name,mimeType,size,md5Checksum,modifiedTime)"},
+	}
+	files, err := self.listAllFiles(listArgs)
+	if err != nil {
+		return nil, wrapError("Failed listing files", err)
+	}
+
+	var remoteFiles []*RemoteFile
+
+	for _, f := range files {
+		path := filepath.Join(relPath, f.Name)
+
+		if isDir(f) {
+			children, err := self.listRemoteTree(f, path)
+			if err != nil {
+				return nil, err
+			}
+			remoteFiles = append(remoteFiles, children...)
+			continue
+		}
+
+		remoteFiles = append(remoteFiles, &RemoteFile{relPath: path, file: f})
+	}
+
+	return remoteFiles, nil
+}
@@ -2,8 +2,8 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"github.com/mzamorski/gdrive/cli"
+	"os"
 )
 
 const Name = "gdrive"
@@ -11,13 +11,21 @@ const Version = "2.2.1"
 
 const DefaultMaxFiles = 30
 const DefaultMaxChanges = 100
+const DefaultChangesInterval = "30s"
 const DefaultNameWidth = 40
 const DefaultPathWidth = 60
+const DefaultColumnPadding = 3
 const DefaultUploadChunkSize = 8 * 1024 * 1024
 const DefaultTimeout = 5 * 60
+const DefaultHttpTimeout = 0
 const DefaultQuery = "trashed = false and 'me' in owners"
 const DefaultShareRole = "reader"
 const DefaultShareType = "anyone"
+const DefaultShareConcurrency = 2
+const DefaultDownloadConcurrency = 4
+const NoFilesExitCode = 3
+const ExistsErrorExitCode = 2
+const DefaultRecentFiles = 25
 
 var DefaultConfigDir = GetDefaultConfigDir()
 
@@ -44,6 +52,45 @@ func main() {
 			Patterns:    []string{"--service-account"},
 			Description: "Oauth service account filename, used for server to server communication without user interaction (filename path is relative to config dir)",
 		},
+		cli.StringFlag{
+			Name:        "impersonate",
+			Patterns:    []string{"--impersonate"},
+			Description: "Email of user to impersonate with the service account, requires domain-wide delegation",
+		},
+		cli.IntFlag{
+			Name:         "httpTimeout",
+			Patterns:     []string{"--http-timeout"},
+			Description:  fmt.Sprintf("Set timeout in seconds for connecting and receiving the response headers of a http request, transferring data is not limited, use 0 for no timeout, default: %d", DefaultHttpTimeout),
+			DefaultValue: DefaultHttpTimeout,
+		},
+		cli.StringFlag{
+			Name:        "userAgent",
+			Patterns:    []string{"--user-agent"},
+			Description: "User-Agent header sent with every request",
+		},
+		cli.StringFlag{
+			Name:        "quotaProject",
+			Patterns:    []string{"--quota-project"},
+			Description: "Google cloud project used for quota and billing, sent as the X-Goog-User-Project header with every request",
+		},
+		cli.BoolFlag{
+			Name:        "dryRun",
+			Patterns:    []string{"--dry-run"},
+			Description: "Print what upload, update, delete, mv, share, mkdir and sync would do without changing anything, other commands fail before changing anything",
+			OmitValue:   true,
+		},
+		cli.BoolFlag{
+			Name:        "debug",
+			Patterns:    []string{"--debug"},
+			Description: "Log http requests to stderr",
+			OmitValue:   true,
+		},
+		cli.BoolFlag{
+			Name:        "si",
+			Patterns:    []string{"--si"},
+			Description: "Show sizes in decimal units (KB, MB), default is binary units (KiB, MiB)",
+			OmitValue:   true,
+		},
 	}
 
 	handlers := []*cli.Handler{
@@ -57,26 +104,56 @@ func main() {
 					cli.IntFlag{
 						Name:         "maxFiles",
 						Patterns:     []string{"-m", "--max"},
-						Description:  fmt.Sprintf("Max files to list, default: %d", DefaultMaxFiles),
+						Description:  fmt.Sprintf("Max files to list, use 0 for no limit. Files hidden by --no-folders or --modified-by-me are not counted, default: %d", DefaultMaxFiles),
 						DefaultValue: DefaultMaxFiles,
 					},
 					cli.StringFlag{
 						Name:         "query",
 						Patterns:     []string{"-q", "--query"},
-						Description:  fmt.Sprintf(`Default query: "%s". See https://developers.google.com/drive/search-parameters`, DefaultQuery),
+						Description:  fmt.Sprintf(`Default query: "%s". See https://developers.google.com/drive/search-parameters. Use - to read the query from stdin`, DefaultQuery),
 						DefaultValue: DefaultQuery,
 					},
+					cli.StringFlag{
+						Name:        "queryFile",
+						Patterns:    []string{"--query-file"},
+						Description: "Read the query from file, use - for stdin. Trailing newlines are removed",
+					},
 					cli.StringFlag{
 						Name:        "sortOrder",
 						Patterns:    []string{"--order"},
 						Description: "Sort order. See https://godoc.org/google.golang.org/api/drive/v3#FilesListCall.OrderBy",
 					},
+					cli.IntFlag{
+						Name:         "recent",
+						Patterns:     []string{"--recent"},
+						Description:  fmt.Sprintf("List the most recently used files first with their modified time, optionally followed by the number of files to list, default: %d. Overrides --max", DefaultRecentFiles),
+						OmitValue:    true,
+						OmittedValue: DefaultRecentFiles,
+					},
 					cli.IntFlag{
 						Name:         "nameWidth",
 						Patterns:     []string{"--name-width"},
 						Description:  fmt.Sprintf("Width of name column, default: %d, minimum: 9, use 0 for full width", DefaultNameWidth),
 						DefaultValue: DefaultNameWidth,
 					},
+					cli.IntFlag{
+						Name:         "padding",
+						Patterns:     []string{"--padding"},
+						Description:  fmt.Sprintf("Padding added to the width of each column, default: %d", DefaultColumnPadding),
+						DefaultValue: DefaultColumnPadding,
+					},
+					cli.IntFlag{
+						Name:         "minWidth",
+						Patterns:     []string{"--min-width"},
+						Description:  "Minimum width of each column including padding, default: 0",
+						DefaultValue: 0,
+					},
+					cli.StringFlag{
+						Name:         "padChar",
+						Patterns:     []string{"--pad-char"},
+						Description:  "Character columns are padded with, space or tab, default: space",
+						DefaultValue: "space",
+					},
 					cli.BoolFlag{
 						Name:        "absPath",
 						Patterns:    []string{"--absolute"},
@@ -94,19 +171,200 @@ func main() {
 						Patterns:    []string{"--csv-output"},
 						Description: "Use CSV output.",
 						OmitValue:   true,
-					},				
+					},
+					cli.StringFlag{
+						Name:        "format",
+						Patterns:    []string{"--format"},
+						Description: "Output format: table, csv, json, gron or xlsx. Gron prints one assignment per line, i.e. files[0].name = \"report.pdf\". Xlsx writes a workbook with sizes as numbers and times as dates to --output. Default: table",
+					},
+					cli.StringFlag{
+						Name:        "output",
+						Patterns:    []string{"--output"},
+						Description: "Write the listed files to this file instead of stdout, required for xlsx",
+					},
+					cli.StringFlag{
+						Name:        "timeFormat",
+						Patterns:    []string{"--time-format"},
+						Description: "Time format: rfc3339, date, datetime or a go layout like '2006-01-02 15:04'. Default: datetime",
+					},
 					cli.BoolFlag{
 						Name:        "useExtended",
 						Patterns:    []string{"--extended"},
 						Description: "Use extended output.",
 						OmitValue:   true,
-					},						
+					},
+					cli.StringFlag{
+						Name:        "columns",
+						Patterns:    []string{"--columns"},
+						Description: "Comma separated list of columns to show, overrides --extended. Available columns: id, name, type, size, created, modified, md5, revision, modifiedbyme, viewed, sharedby, owner",
+					},
 					cli.BoolFlag{
 						Name:        "sizeInBytes",
 						Patterns:    []string{"--bytes"},
 						Description: "Size in bytes",
 						OmitValue:   true,
 					},
+					cli.StringFlag{
+						Name:        "mime",
+						Patterns:    []string{"--mime"},
+						Description: "Only list files with the given mime type or file extension, i.e. pdf. Combined with the query using 'and'",
+					},
+					cli.StringFlag{
+						Name:        "parent",
+						Patterns:    []string{"--parent"},
+						Description: "Only list files in the given directory, given by id, absolute path like /photos/2020 or a path below root, me or shared like shared/Reports. Use shared to list the files shared with you. Combined with the query using 'and'",
+					},
+					cli.StringFlag{
+						Name:        "label",
+						Patterns:    []string{"--label"},
+						Description: "Only list files with the given label id applied. Combined with the query using 'and'",
+					},
+					cli.BoolFlag{
+						Name:        "failIfEmpty",
+						Patterns:    []string{"--fail-if-empty"},
+						Description: fmt.Sprintf("Exit with code %d if no files matched, can be combined with --count", NoFilesExitCode),
+						OmitValue:   true,
+					},
+					cli.StringFlag{
+						Name:        "space",
+						Patterns:    []string{"--space"},
+						Description: "List files in the given space: drive, appDataFolder or photos, default: drive",
+					},
+					cli.BoolFlag{
+						Name:        "noProgress",
+						Patterns:    []string{"--no-progress"},
+						Description: "Hide the number of fetched files, it is only shown on a terminal when listing many pages",
+						OmitValue:   true,
+					},
+					cli.BoolFlag{
+						Name:        "markDuplicates",
+						Patterns:    []string{"--annotate-duplicates"},
+						Description: "Add a column marking files with the same md5 as another listed file with the number of their duplicate group, files without a checksum are not marked",
+						OmitValue:   true,
+					},
+					cli.StringFlag{
+						Name:        "pageToken",
+						Patterns:    []string{"--page-token"},
+						Description: "List the single page starting at the token printed by --print-next-token, --max is the page size",
+					},
+					cli.BoolFlag{
+						Name:        "printNextToken",
+						Patterns:    []string{"--print-next-token"},
+						Description: "List a single page and print the token of the next page to stderr, nothing is printed after the last page. The page has at most --max files, fewer if filtered",
+						OmitValue:   true,
+					},
+					cli.BoolFlag{
+						Name:        "hideFolders",
+						Patterns:    []string{"--no-folders"},
+						Description: "Do not list folders, only files are counted towards --max and --count",
+						OmitValue:   true,
+					},
+					cli.BoolFlag{
+						Name:        "foldersFirst",
+						Patterns:    []string{"--folders-first"},
+						Description: "List folders before files, the sort order is kept within folders and files",
+						OmitValue:   true,
+					},
+					cli.StringFlag{
+						Name:        "sortBy",
+						Patterns:    []string{"--sort"},
+						Description: "Sort the listed files by name. Only the listed files are sorted, use --order to sort all files. Default: --order",
+					},
+					cli.BoolFlag{
+						Name:        "natural",
+						Patterns:    []string{"--natural"},
+						Description: "Compare numbers in names by value with --sort name, so file2 is before file10",
+						OmitValue:   true,
+					},
+					cli.BoolFlag{
+						Name:        "modifiedByMe",
+						Patterns:    []string{"--modified-by-me"},
+						Description: "Only list files modified by me, ordered by the time I last modified them unless --order is given",
+						OmitValue:   true,
+					},
+					cli.StringFlag{
+						Name:        "viewedAfter",
+						Patterns:    []string{"--viewed-after"},
+						Description: "Only list files viewed by me after the given date (2006-01-02 or RFC 3339). Combined with the query using 'and'",
+					},
+					cli.BoolFlag{
+						Name:        "sharedWithMe",
+						Patterns:    []string{"--shared-with-me"},
+						Description: "Only list files shared with me and show who shared them. Combined with the query using 'and', the owner condition of the default query is dropped",
+						OmitValue:   true,
+					},
+					cli.StringFlag{
+						Name:        "owner",
+						Patterns:    []string{"--owner"},
+						Description: "Only list files owned by the email address or me, show the owners with --columns owner. Combined with the query using 'and', the owner condition of the default query is dropped",
+					},
+					cli.StringFlag{
+						Name:        "modifiedAfter",
+						Patterns:    []string{"--modified-after"},
+						Description: "Only list files modified after the given date (2006-01-02 or RFC 3339). Combined with the query using 'and'",
+					},
+					cli.StringFlag{
+						Name:        "modifiedBefore",
+						Patterns:    []string{"--modified-before"},
+						Description: "Only list files modified before the given date (2006-01-02 or RFC 3339). Combined with the query using 'and'",
+					},
+					cli.StringFlag{
+						Name:        "formatTemplate",
+						Patterns:    []string{"--format-template"},
+						Description: "Go template used to render each file, i.e. '{{.Id}} {{.Name}} {{size .Size}}'. Available functions: size, date, type",
+					},
+					cli.BoolFlag{
+						Name:        "detailedType",
+						Patterns:    []string{"--detailed-type"},
+						Description: "Show type of google documents as gdoc, gsheet, gslides, gform or gdrawing instead of doc",
+						OmitValue:   true,
+					},
+					cli.BoolFlag{
+						Name:        "countOnly",
+						Patterns:    []string{"--count"},
+						Description: "Only print the number of matching files, limited by --max",
+						OmitValue:   true,
+					},
+				),
+			},
+		},
+		&cli.Handler{
+			Pattern:     "[global] download --cleanup [options] <path>",
+			Description: "Find partial downloads left behind by interrupted downloads with --resume",
+			Callback:    cleanupDownloadsHandler,
+			FlagGroups: cli.FlagGroups{
+				cli.NewFlagGroup("global", globalFlags...),
+				cli.NewFlagGroup("options",
+					cli.BoolFlag{
+						Name:        "resume",
+						Patterns:    []string{"--resume"},
+						Description: "Resume partial downloads of remote files that have not changed",
+						OmitValue:   true,
+					},
+					cli.BoolFlag{
+						Name:        "delete",
+						Patterns:    []string{"--delete"},
+						Description: "Remove partial downloads that are not resumed",
+						OmitValue:   true,
+					},
+					cli.BoolFlag{
+						Name:        "preserveMtime",
+						Patterns:    []string{"--preserve-mtime"},
+						Description: "Set the modified time of resumed files to the modified time of the remote file",
+						OmitValue:   true,
+					},
+					cli.BoolFlag{
+						Name:        "noProgress",
+						Patterns:    []string{"--no-progress"},
+						Description: "Hide progress",
+						OmitValue:   true,
+					},
+					cli.IntFlag{
+						Name:         "timeout",
+						Patterns:     []string{"--timeout"},
+						Description:  fmt.Sprintf("Set timeout in seconds, use 0 for no timeout. Timeout is reached when no data is transferred in set amount of seconds, default: %d", DefaultTimeout),
+						DefaultValue: DefaultTimeout,
+					},
 				),
 			},
 		},
@@ -135,6 +393,17 @@ func main() {
 						Description: "Download directory recursively, documents will be skipped",
 						OmitValue:   true,
 					},
+					cli.BoolFlag{
+						Name:        "continueOnError",
+						Patterns:    []string{"--continue-on-error"},
+						Description: "Log failed files and keep going when downloading recursively, prints a summary of failed paths at the end",
+						OmitValue:   true,
+					},
+					cli.StringFlag{
+						Name:        "manifest",
+						Patterns:    []string{"--manifest"},
+						Description: "Write the path, size, md5 and sha256 of each downloaded file to a csv file, the md5 is compared with the remote checksum. See verify-manifest",
+					},
 					cli.StringFlag{
 						Name:        "path",
 						Patterns:    []string{"--path"},
@@ -158,6 +427,34 @@ func main() {
 						Description: "Write file content to stdout",
 						OmitValue:   true,
 					},
+					cli.BoolFlag{
+						Name:        "resume",
+						Patterns:    []string{"--resume"},
+						Description: "Keep partially downloaded data in a .part file and resume from it, the partial data is discarded if the remote file has changed",
+						OmitValue:   true,
+					},
+					cli.IntFlag{
+						Name:         "timeout",
+						Patterns:     []string{"--timeout"},
+						Description:  fmt.Sprintf("Set timeout in seconds, use 0 for no timeout. Timeout is reached when no data is transferred in set amount of seconds, default: %d", DefaultTimeout),
+						DefaultValue: DefaultTimeout,
+					},
+					cli.BoolFlag{
+						Name:        "preserveMtime",
+						Patterns:    []string{"--preserve-mtime"},
+						Description: "Set the modified time of downloaded files to the remote modified time",
+						OmitValue:   true,
+					},
+				),
+			},
+		},
+		&cli.Handler{
+			Pattern:     "[global] cat [options] <fileId>",
+			Description: "Print file content to stdout, google documents are exported as text. The file can be given by id or absolute path",
+			Callback:    catHandler,
+			FlagGroups: cli.FlagGroups{
+				cli.NewFlagGroup("global", globalFlags...),
+				cli.NewFlagGroup("options",
 					cli.IntFlag{
 						Name:         "timeout",
 						Patterns:     []string{"--timeout"},
@@ -206,6 +503,97 @@ func main() {
 				),
 			},
 		},
+		&cli.Handler{
+			Pattern:     "[global] download folder [options] <fileId>",
+			Description: "Download directory recursively, google documents are exported using the default export mime type",
+			Callback:    downloadFolderHandler,
+			FlagGroups: cli.FlagGroups{
+				cli.NewFlagGroup("global", globalFlags...),
+				cli.NewFlagGroup("options",
+					cli.BoolFlag{
+						Name:        "force",
+						Patterns:    []string{"-f", "--force"},
+						Description: "Overwrite existing files",
+						OmitValue:   true,
+					},
+					cli.BoolFlag{
+						Name:        "skip",
+						Patterns:    []string{"-s", "--skip"},
+						Description: "Skip existing files",
+						OmitValue:   true,
+					},
+					cli.StringFlag{
+						Name:        "path",
+						Patterns:    []string{"--path"},
+						Description: "Download path",
+					},
+					cli.StringSliceFlag{
+						Name:        "mime",
+						Patterns:    []string{"--mime"},
+						Description: "Only download files with given mime type, a trailing / matches by prefix (i.e. image/), can be specified multiple times",
+					},
+					cli.StringSliceFlag{
+						Name:        "excludeMime",
+						Patterns:    []string{"--exclude-mime"},
+						Description: "Skip files with given mime type, a trailing / matches by prefix (i.e. video/), can be specified multiple times",
+					},
+					cli.BoolFlag{
+						Name:        "noProgress",
+						Patterns:    []string{"--no-progress"},
+						Description: "Hide progress",
+						OmitValue:   true,
+					},
+					cli.IntFlag{
+						Name:         "timeout",
+						Patterns:     []string{"--timeout"},
+						Description:  fmt.Sprintf("Set timeout in seconds, use 0 for no timeout. Timeout is reached when no data is transferred in set amount of seconds, default: %d", DefaultTimeout),
+						DefaultValue: DefaultTimeout,
+					},
+					cli.BoolFlag{
+						Name:        "preserveMtime",
+						Patterns:    []string{"--preserve-mtime"},
+						Description: "Set the modified time of downloaded files to the remote modified time",
+						OmitValue:   true,
+					},
+					cli.BoolFlag{
+						Name:        "flatten",
+						Patterns:    []string{"--flatten"},
+						Description: "Save all files directly in the download path without the directory structure, files with the same name are renamed",
+						OmitValue:   true,
+					},
+					cli.StringFlag{
+						Name:        "since",
+						Patterns:    []string{"--since"},
+						Description: "Only download files modified after this date (2006-01-02) or RFC 3339 timestamp, all directories are still searched. The newest modified time is printed for the next run",
+					},
+					cli.StringFlag{
+						Name:        "sinceFile",
+						Patterns:    []string{"--since-file"},
+						Description: "Read --since from this file if not given, and write the newest modified time to it after downloading",
+					},
+					cli.IntFlag{
+						Name:         "concurrency",
+						Patterns:     []string{"--concurrency"},
+						Description:  fmt.Sprintf("Number of files downloaded at the same time, progress is only shown when 1, default: %d", DefaultDownloadConcurrency),
+						DefaultValue: DefaultDownloadConcurrency,
+					},
+					cli.BoolFlag{
+						Name:        "flattenSingleChild",
+						Patterns:    []string{"--flatten-single-child"},
+						Description: "Download directories containing a single directory and no files into one directory, joining their names with _ as / is not allowed in local names",
+						OmitValue:   true,
+					},
+				),
+			},
+		},
+		&cli.Handler{
+			Pattern:     "[global] upload --clear-sessions",
+			Description: "Remove stored upload sessions",
+			Callback:    clearUploadSessionsHandler,
+			FlagGroups: cli.FlagGroups{
+				cli.NewFlagGroup("global", globalFlags...),
+			},
+		},
 		&cli.Handler{
 			Pattern:     "[global] upload [options] <path>",
 			Description: "Upload file or directory",
@@ -219,10 +607,34 @@ func main() {
 						Description: "Upload directory recursively",
 						OmitValue:   true,
 					},
+					cli.BoolFlag{
+						Name:        "continueOnError",
+						Patterns:    []string{"--continue-on-error"},
+						Description: "Log failed files and keep going when uploading recursively, prints a summary of failed paths at the end",
+						OmitValue:   true,
+					},
+					cli.BoolFlag{
+						Name:        "checkQuota",
+						Patterns:    []string{"--check-quota"},
+						Description: "Check that the files fit in the remaining storage quota before uploading",
+						OmitValue:   true,
+					},
+					cli.BoolFlag{
+						Name:        "noConvert",
+						Patterns:    []string{"--no-convert"},
+						Description: "Keep the original file format, fails if the mime type would convert the file to a google document",
+						OmitValue:   true,
+					},
+					cli.BoolFlag{
+						Name:        "resume",
+						Patterns:    []string{"--resume"},
+						Description: "Store the upload session in the config dir and resume it when the same unchanged file is uploaded again, sessions expire after a week",
+						OmitValue:   true,
+					},
 					cli.StringSliceFlag{
 						Name:        "parent",
 						Patterns:    []string{"-p", "--parent"},
-						Description: "Parent id, used to upload file to a specific directory, can be specified multiple times to give many parents",
+						Description: "Parent id or absolute path like /photos/2020, used to upload file to a specific directory, can be specified multiple times to give many parents",
 					},
 					cli.StringFlag{
 						Name:        "name",
@@ -263,6 +675,24 @@ func main() {
 						Description:  fmt.Sprintf("Set timeout in seconds, use 0 for no timeout. Timeout is reached when no data is transferred in set amount of seconds, default: %d", DefaultTimeout),
 						DefaultValue: DefaultTimeout,
 					},
+					cli.BoolFlag{
+						Name:        "renameOnConflict",
+						Patterns:    []string{"--rename-on-conflict"},
+						Description: "Append (2), (3), etc. to the name if a file with the same name exists in the parent, directories are uploaded into existing directories with the same name",
+						OmitValue:   true,
+					},
+					cli.BoolFlag{
+						Name:        "skipOnConflict",
+						Patterns:    []string{"--skip-on-conflict"},
+						Description: "Do not upload the file if a file with the same name exists in the parent, directories are uploaded into existing directories with the same name",
+						OmitValue:   true,
+					},
+					cli.BoolFlag{
+						Name:        "replaceOnConflict",
+						Patterns:    []string{"--replace-on-conflict"},
+						Description: "Update the content of the file with the same name in the parent instead of uploading a duplicate, directories are uploaded into existing directories with the same name. Not allowed with --resume",
+						OmitValue:   true,
+					},
 					cli.IntFlag{
 						Name:         "chunksize",
 						Patterns:     []string{"--chunksize"},
@@ -282,7 +712,7 @@ func main() {
 					cli.StringSliceFlag{
 						Name:        "parent",
 						Patterns:    []string{"-p", "--parent"},
-						Description: "Parent id, used to upload file to a specific directory, can be specified multiple times to give many parents",
+						Description: "Parent id or absolute path like /photos/2020, used to upload file to a specific directory, can be specified multiple times to give many parents",
 					},
 					cli.IntFlag{
 						Name:         "chunksize",
@@ -322,160 +752,652 @@ func main() {
 			},
 		},
 		&cli.Handler{
-			Pattern:     "[global] update [options] <fileId> <path>",
-			Description: "Update file, this creates a new revision of the file",
-			Callback:    updateHandler,
+			Pattern:     "[global] upload [options] --from-url <url>",
+			Description: "Upload file from url",
+			Callback:    uploadUrlHandler,
+			FlagGroups: cli.FlagGroups{
+				cli.NewFlagGroup("global", globalFlags...),
+				cli.NewFlagGroup("options",
+					cli.StringSliceFlag{
+						Name:        "parent",
+						Patterns:    []string{"-p", "--parent"},
+						Description: "Parent id or absolute path like /photos/2020, used to upload file to a specific directory, can be specified multiple times to give many parents",
+					},
+					cli.StringFlag{
+						Name:        "name",
+						Patterns:    []string{"--name"},
+						Description: "Filename, defaults to the last path element of the url",
+					},
+					cli.StringFlag{
+						Name:        "description",
+						Patterns:    []string{"--description"},
+						Description: "File description",
+					},
+					cli.StringFlag{
+						Name:        "mime",
+						Patterns:    []string{"--mime"},
+						Description: "Force mime type",
+					},
+					cli.BoolFlag{
+						Name:        "share",
+						Patterns:    []string{"--share"},
+						Description: "Share file",
+						OmitValue:   true,
+					},
+					cli.IntFlag{
+						Name:         "timeout",
+						Patterns:     []string{"--timeout"},
+						Description:  fmt.Sprintf("Set timeout in seconds, use 0 for no timeout. Timeout is reached when no data is transferred in set amount of seconds, default: %d", DefaultTimeout),
+						DefaultValue: DefaultTimeout,
+					},
+					cli.BoolFlag{
+						Name:        "noProgress",
+						Patterns:    []string{"--no-progress"},
+						Description: "Hide progress",
+						OmitValue:   true,
+					},
+					cli.IntFlag{
+						Name:         "chunksize",
+						Patterns:     []string{"--chunksize"},
+						Description:  fmt.Sprintf("Set chunk size in bytes, default: %d", DefaultUploadChunkSize),
+						DefaultValue: DefaultUploadChunkSize,
+					},
+				),
+			},
+		},
+		&cli.Handler{
+			Pattern:     "[global] update [options] <fileId> <path>",
+			Description: "Update file, this creates a new revision of the file",
+			Callback:    updateHandler,
+			FlagGroups: cli.FlagGroups{
+				cli.NewFlagGroup("global", globalFlags...),
+				cli.NewFlagGroup("options",
+					cli.StringSliceFlag{
+						Name:        "parent",
+						Patterns:    []string{"-p", "--parent"},
+						Description: "Parent id, used to upload file to a specific directory, can be specified multiple times to give many parents",
+					},
+					cli.StringFlag{
+						Name:        "name",
+						Patterns:    []string{"--name"},
+						Description: "Filename",
+					},
+					cli.StringFlag{
+						Name:        "description",
+						Patterns:    []string{"--description"},
+						Description: "File description",
+					},
+					cli.BoolFlag{
+						Name:        "noProgress",
+						Patterns:    []string{"--no-progress"},
+						Description: "Hide progress",
+						OmitValue:   true,
+					},
+					cli.StringFlag{
+						Name:        "mime",
+						Patterns:    []string{"--mime"},
+						Description: "Force mime type",
+					},
+					cli.IntFlag{
+						Name:         "timeout",
+						Patterns:     []string{"--timeout"},
+						Description:  fmt.Sprintf("Set timeout in seconds, use 0 for no timeout. Timeout is reached when no data is transferred in set amount of seconds, default: %d", DefaultTimeout),
+						DefaultValue: DefaultTimeout,
+					},
+					cli.IntFlag{
+						Name:         "chunksize",
+						Patterns:     []string{"--chunksize"},
+						Description:  fmt.Sprintf("Set chunk size in bytes, default: %d", DefaultUploadChunkSize),
+						DefaultValue: DefaultUploadChunkSize,
+					},
+					cli.BoolFlag{
+						Name:        "newerThanRemote",
+						Patterns:    []string{"--newer-than-remote"},
+						Description: "Only update if the local file was modified after the remote file",
+						OmitValue:   true,
+					},
+					cli.BoolFlag{
+						Name:        "force",
+						Patterns:    []string{"-f", "--force"},
+						Description: "Update even if the remote file is newer, used with --newer-than-remote",
+						OmitValue:   true,
+					},
+				),
+			},
+		},
+		&cli.Handler{
+			Pattern:     "[global] info [options] <fileId>",
+			Description: "Show file info",
+			Callback:    infoHandler,
+			FlagGroups: cli.FlagGroups{
+				cli.NewFlagGroup("global", globalFlags...),
+				cli.NewFlagGroup("options",
+					cli.BoolFlag{
+						Name:        "sizeInBytes",
+						Patterns:    []string{"--bytes"},
+						Description: "Show size in bytes",
+						OmitValue:   true,
+					},
+					cli.StringFlag{
+						Name:        "labels",
+						Patterns:    []string{"--labels"},
+						Description: "Comma separated list of label ids, shows which of the labels are applied to the file",
+					},
+					cli.BoolFlag{
+						Name:        "downloadUrl",
+						Patterns:    []string{"--download-url"},
+						Description: "Only print the urls the file can be downloaded from, export urls are printed for google documents. The api urls require an authorization header with your credentials",
+						OmitValue:   true,
+					},
+					cli.BoolFlag{
+						Name:        "published",
+						Patterns:    []string{"--published"},
+						Description: "Only print whether the file is published to the web, its public permissions and published url",
+						OmitValue:   true,
+					},
+					cli.StringFlag{
+						Name:        "fields",
+						Patterns:    []string{"--fields"},
+						Description: "Only fetch and print the given api fields, i.e. md5Checksum or size,owners(emailAddress). The id and name are always included",
+					},
+				),
+			},
+		},
+		&cli.Handler{
+			Pattern:     "[global] publish <fileId>",
+			Description: "Publish google document to the web, other files are shared with anyone with the link",
+			Callback:    publishHandler,
+			FlagGroups: cli.FlagGroups{
+				cli.NewFlagGroup("global", globalFlags...),
+			},
+		},
+		&cli.Handler{
+			Pattern:     "[global] unpublish <fileId>",
+			Description: "Stop publishing google document to the web, anyone permissions are removed from other files",
+			Callback:    unpublishHandler,
+			FlagGroups: cli.FlagGroups{
+				cli.NewFlagGroup("global", globalFlags...),
+			},
+		},
+		&cli.Handler{
+			Pattern:     "[global] mkdir [options] <name>",
+			Description: "Create directory",
+			Callback:    mkdirHandler,
+			FlagGroups: cli.FlagGroups{
+				cli.NewFlagGroup("global", globalFlags...),
+				cli.NewFlagGroup("options",
+					cli.StringSliceFlag{
+						Name:        "parent",
+						Patterns:    []string{"-p", "--parent"},
+						Description: "Parent id or absolute path like /photos/2020 of created directory, can be specified multiple times to give many parents",
+					},
+					cli.StringFlag{
+						Name:        "description",
+						Patterns:    []string{"--description"},
+						Description: "Directory description",
+					},
+					cli.StringFlag{
+						Name:        "colorRgb",
+						Patterns:    []string{"--color-rgb"},
+						Description: "Directory color as a hex rgb string, i.e. #4986e7",
+					},
+					cli.BoolFlag{
+						Name:        "noDuplicate",
+						Patterns:    []string{"--no-duplicate"},
+						Description: "Do not create the directory if a directory with the same name exists in the parent, the id of the existing directory is printed instead",
+						OmitValue:   true,
+					},
+				),
+			},
+		},
+		&cli.Handler{
+			Pattern:     "[global] mv <fileId> <parentId>",
+			Description: "Move file to another directory, in My Drive or a shared drive. The file is removed from its current parents",
+			Callback:    moveHandler,
+			FlagGroups: cli.FlagGroups{
+				cli.NewFlagGroup("global", globalFlags...),
+			},
+		},
+		&cli.Handler{
+			Pattern:     "[global] copy [options] <fileId>",
+			Description: "Copy file",
+			Callback:    copyHandler,
+			FlagGroups: cli.FlagGroups{
+				cli.NewFlagGroup("global", globalFlags...),
+				cli.NewFlagGroup("options",
+					cli.StringSliceFlag{
+						Name:        "parent",
+						Patterns:    []string{"-p", "--parent"},
+						Description: "Parent id or absolute path like /photos/2020 of the copy, can be specified multiple times to give many parents. Defaults to the parents of the file",
+					},
+					cli.StringFlag{
+						Name:        "name",
+						Patterns:    []string{"--name"},
+						Description: "Name of the copy, defaults to 'Copy of' the file name",
+					},
+					cli.BoolFlag{
+						Name:        "preservePermissions",
+						Patterns:    []string{"--preserve-permissions"},
+						Description: "Recreate the sharing of the file on the copy, the owner and inherited permissions are skipped",
+						OmitValue:   true,
+					},
+					cli.BoolFlag{
+						Name:        "notify",
+						Patterns:    []string{"--notify"},
+						Description: "Send notification emails to the users and groups given the permissions of the copy",
+						OmitValue:   true,
+					},
+				),
+			},
+		},
+		&cli.Handler{
+			Pattern:     "[global] batch [options] <path>",
+			Description: "Run upload, mkdir, share and move operations from a csv or jsonl file. Csv files must have a header row with the columns action, id, path, name, parent, role, type, email and domain",
+			Callback:    batchHandler,
+			FlagGroups: cli.FlagGroups{
+				cli.NewFlagGroup("global", globalFlags...),
+				cli.NewFlagGroup("options",
+					cli.IntFlag{
+						Name:         "concurrency",
+						Patterns:     []string{"--concurrency"},
+						Description:  "Number of operations to run at the same time, operations must not depend on each other when above 1. Default: 1",
+						DefaultValue: 1,
+					},
+					cli.IntFlag{
+						Name:         "chunksize",
+						Patterns:     []string{"--chunksize"},
+						Description:  fmt.Sprintf("Set chunk size in bytes for uploads, default: %d", DefaultUploadChunkSize),
+						DefaultValue: DefaultUploadChunkSize,
+					},
+					cli.IntFlag{
+						Name:         "timeout",
+						Patterns:     []string{"--timeout"},
+						Description:  fmt.Sprintf("Set timeout in seconds for uploads, use 0 for no timeout. Timeout is reached when no data is transferred in set amount of seconds, default: %d", DefaultTimeout),
+						DefaultValue: DefaultTimeout,
+					},
+				),
+			},
+		},
+		&cli.Handler{
+			Pattern:     "[global] share [options] <fileId>",
+			Description: "Share file or directory",
+			Callback:    shareHandler,
+			FlagGroups: cli.FlagGroups{
+				cli.NewFlagGroup("global", globalFlags...),
+				cli.NewFlagGroup("options",
+					cli.BoolFlag{
+						Name:        "linkOnly",
+						Patterns:    []string{"--link-only"},
+						Description: "Only print the link to the file",
+						OmitValue:   true,
+					},
+					cli.StringFlag{
+						Name:         "role",
+						Patterns:     []string{"--role"},
+						Description:  fmt.Sprintf("Share role: owner/writer/commenter/reader, default: %s", DefaultShareRole),
+						DefaultValue: DefaultShareRole,
+					},
+					cli.StringFlag{
+						Name:         "type",
+						Patterns:     []string{"--type"},
+						Description:  fmt.Sprintf("Share type: user/group/domain/anyone, default: %s", DefaultShareType),
+						DefaultValue: DefaultShareType,
+					},
+					cli.StringFlag{
+						Name:        "email",
+						Patterns:    []string{"--email"},
+						Description: "The email address of the user or group to share the file with. Requires 'user' or 'group' as type",
+					},
+					cli.StringFlag{
+						Name:        "domain",
+						Patterns:    []string{"--domain"},
+						Description: "The name of Google Apps domain. Requires 'domain' as type",
+					},
+					cli.BoolFlag{
+						Name:        "discoverable",
+						Patterns:    []string{"--discoverable"},
+						Description: "Make file discoverable by search engines",
+						OmitValue:   true,
+					},
+					cli.BoolFlag{
+						Name:        "revoke",
+						Patterns:    []string{"--revoke"},
+						Description: "Delete all sharing permissions (owner roles will be skipped)",
+						OmitValue:   true,
+					},
+				),
+			},
+		},
+		&cli.Handler{
+			Pattern:     "[global] share batch [options] <fileId>",
+			Description: "Share file or directory with many users or groups",
+			Callback:    shareBatchHandler,
+			FlagGroups: cli.FlagGroups{
+				cli.NewFlagGroup("global", globalFlags...),
+				cli.NewFlagGroup("options",
+					cli.StringSliceFlag{
+						Name:        "email",
+						Patterns:    []string{"--email"},
+						Description: "The email address of the user or group to share the file with, can be specified multiple times",
+					},
+					cli.StringFlag{
+						Name:         "role",
+						Patterns:     []string{"--role"},
+						Description:  fmt.Sprintf("Share role: owner/writer/commenter/reader, default: %s", DefaultShareRole),
+						DefaultValue: DefaultShareRole,
+					},
+					cli.StringFlag{
+						Name:         "type",
+						Patterns:     []string{"--type"},
+						Description:  "Share type: user/group, default: user",
+						DefaultValue: "user",
+					},
+					cli.IntFlag{
+						Name:         "concurrency",
+						Patterns:     []string{"--concurrency"},
+						Description:  fmt.Sprintf("Max number of permissions created at the same time, default: %d", DefaultShareConcurrency),
+						DefaultValue: DefaultShareConcurrency,
+					},
+					cli.BoolFlag{
+						Name:        "verbose",
+						Patterns:    []string{"--verbose"},
+						Description: "Show the number of retries for each email",
+						OmitValue:   true,
+					},
+				),
+			},
+		},
+		&cli.Handler{
+			Pattern:     "[global] share list [options] <fileId>",
+			Description: "List files permissions",
+			Callback:    shareListHandler,
+			FlagGroups: cli.FlagGroups{
+				cli.NewFlagGroup("global", globalFlags...),
+				cli.NewFlagGroup("options",
+					cli.StringFlag{
+						Name:        "sortBy",
+						Patterns:    []string{"--sort"},
+						Description: "Sort permissions by role, most privileged first, or email. Default: api order",
+					},
+				),
+			},
+		},
+		&cli.Handler{
+			Pattern:     "[global] share revoke <fileId> <permissionId>",
+			Description: "Revoke permission",
+			Callback:    shareRevokeHandler,
+			FlagGroups: cli.FlagGroups{
+				cli.NewFlagGroup("global", globalFlags...),
+			},
+		},
+		&cli.Handler{
+			Pattern:     "[global] delete [options] <fileId>",
+			Description: "Delete file or directory",
+			Callback:    deleteHandler,
+			FlagGroups: cli.FlagGroups{
+				cli.NewFlagGroup("global", globalFlags...),
+				cli.NewFlagGroup("options",
+					cli.BoolFlag{
+						Name:        "recursive",
+						Patterns:    []string{"-r", "--recursive"},
+						Description: "Delete directory and all it's content",
+						OmitValue:   true,
+					},
+					cli.BoolFlag{
+						Name:        "continueOnError",
+						Patterns:    []string{"--continue-on-error"},
+						Description: "Delete directory content file by file, logging failures and keeping going. Directories with content that failed to delete are kept",
+						OmitValue:   true,
+					},
+					cli.BoolFlag{
+						Name:        "onlyFiles",
+						Patterns:    []string{"--only-files"},
+						Description: "Only delete files when deleting recursively, directories are kept",
+						OmitValue:   true,
+					},
+					cli.BoolFlag{
+						Name:        "onlyFolders",
+						Patterns:    []string{"--only-folders"},
+						Description: "Only delete directories without any files in them when deleting recursively",
+						OmitValue:   true,
+					},
+					cli.BoolFlag{
+						Name:        "yes",
+						Patterns:    []string{"-y", "--yes"},
+						Description: "Delete without asking for confirmation, required when not running interactively",
+						OmitValue:   true,
+					},
+				),
+			},
+		},
+		&cli.Handler{
+			Pattern:     "[global] empty-trash [options]",
+			Description: "Permanently delete all files in trash",
+			Callback:    emptyTrashHandler,
+			FlagGroups: cli.FlagGroups{
+				cli.NewFlagGroup("global", globalFlags...),
+				cli.NewFlagGroup("options",
+					cli.StringFlag{
+						Name:        "minAge",
+						Patterns:    []string{"--min-age"},
+						Description: "Only delete files trashed longer ago than this duration, i.e. 7d, 2w or 12h. Recently trashed files are kept",
+					},
+					cli.BoolFlag{
+						Name:        "yes",
+						Patterns:    []string{"-y", "--yes"},
+						Description: "Delete without asking for confirmation, required when not running interactively",
+						OmitValue:   true,
+					},
+				),
+			},
+		},
+		&cli.Handler{
+			Pattern:     "[global] cleanup parents [options]",
+			Description: "Find files with multiple or duplicated parents, only reports the files unless --fix is given",
+			Callback:    cleanupParentsHandler,
 			FlagGroups: cli.FlagGroups{
 				cli.NewFlagGroup("global", globalFlags...),
 				cli.NewFlagGroup("options",
-					cli.StringSliceFlag{
+					cli.StringFlag{
 						Name:        "parent",
 						Patterns:    []string{"-p", "--parent"},
-						Description: "Parent id, used to upload file to a specific directory, can be specified multiple times to give many parents",
+						Description: "Only check files in the given directory",
 					},
 					cli.StringFlag{
-						Name:        "name",
-						Patterns:    []string{"--name"},
-						Description: "Filename",
-					},
-					cli.StringFlag{
-						Name:        "description",
-						Patterns:    []string{"--description"},
-						Description: "File description",
+						Name:        "keepParent",
+						Patterns:    []string{"--keep-parent"},
+						Description: "Parent id to keep for files that have it, defaults to the first parent of each file",
 					},
 					cli.BoolFlag{
-						Name:        "noProgress",
-						Patterns:    []string{"--no-progress"},
-						Description: "Hide progress",
+						Name:        "fix",
+						Patterns:    []string{"--fix"},
+						Description: "Remove all but the kept parent, without this the files are only reported",
 						OmitValue:   true,
 					},
+				),
+			},
+		},
+		&cli.Handler{
+			Pattern:     "[global] cleanup [options]",
+			Description: "Move files not modified for a given time to trash, only lists the files unless --force is given",
+			Callback:    cleanupHandler,
+			FlagGroups: cli.FlagGroups{
+				cli.NewFlagGroup("global", globalFlags...),
+				cli.NewFlagGroup("options",
 					cli.StringFlag{
-						Name:        "mime",
-						Patterns:    []string{"--mime"},
-						Description: "Force mime type",
+						Name:        "olderThan",
+						Patterns:    []string{"--older-than"},
+						Description: "Required. Trash files not modified within this duration, i.e. 90d, 2w or 12h",
 					},
-					cli.IntFlag{
-						Name:         "timeout",
-						Patterns:     []string{"--timeout"},
-						Description:  fmt.Sprintf("Set timeout in seconds, use 0 for no timeout. Timeout is reached when no data is transferred in set amount of seconds, default: %d", DefaultTimeout),
-						DefaultValue: DefaultTimeout,
+					cli.StringFlag{
+						Name:        "parent",
+						Patterns:    []string{"-p", "--parent"},
+						Description: "Only trash files in the given directory",
 					},
-					cli.IntFlag{
-						Name:         "chunksize",
-						Patterns:     []string{"--chunksize"},
-						Description:  fmt.Sprintf("Set chunk size in bytes, default: %d", DefaultUploadChunkSize),
-						DefaultValue: DefaultUploadChunkSize,
+					cli.BoolFlag{
+						Name:        "includeFolders",
+						Patterns:    []string{"--include-folders"},
+						Description: "Also trash directories",
+						OmitValue:   true,
+					},
+					cli.BoolFlag{
+						Name:        "force",
+						Patterns:    []string{"--force"},
+						Description: "Move the files to trash, without this the files are only listed",
+						OmitValue:   true,
+					},
+					cli.BoolFlag{
+						Name:        "sizeInBytes",
+						Patterns:    []string{"--bytes"},
+						Description: "Size in bytes",
+						OmitValue:   true,
 					},
 				),
 			},
 		},
 		&cli.Handler{
-			Pattern:     "[global] info [options] <fileId>",
-			Description: "Show file info",
-			Callback:    infoHandler,
+			Pattern:     "[global] dedupe [options] <fileId>",
+			Description: "Find files with identical content in a directory",
+			Callback:    dedupeHandler,
 			FlagGroups: cli.FlagGroups{
 				cli.NewFlagGroup("global", globalFlags...),
 				cli.NewFlagGroup("options",
+					cli.BoolFlag{
+						Name:        "trashDuplicates",
+						Patterns:    []string{"--trash-duplicates"},
+						Description: "Move duplicates to trash, the oldest file of each set of duplicates is kept",
+						OmitValue:   true,
+					},
 					cli.BoolFlag{
 						Name:        "sizeInBytes",
 						Patterns:    []string{"--bytes"},
-						Description: "Show size in bytes",
+						Description: "Size in bytes",
 						OmitValue:   true,
 					},
 				),
 			},
 		},
 		&cli.Handler{
-			Pattern:     "[global] mkdir [options] <name>",
-			Description: "Create directory",
-			Callback:    mkdirHandler,
+			Pattern:     "[global] verify-manifest [options] <manifestPath>",
+			Description: "Compare local files with the sizes and checksums recorded in a manifest, drive is not accessed",
+			Callback:    verifyManifestHandler,
 			FlagGroups: cli.FlagGroups{
 				cli.NewFlagGroup("global", globalFlags...),
 				cli.NewFlagGroup("options",
-					cli.StringSliceFlag{
-						Name:        "parent",
-						Patterns:    []string{"-p", "--parent"},
-						Description: "Parent id of created directory, can be specified multiple times to give many parents",
-					},
 					cli.StringFlag{
-						Name:        "description",
-						Patterns:    []string{"--description"},
-						Description: "Directory description",
+						Name:         "checksum",
+						Patterns:     []string{"--checksum"},
+						Description:  "Checksum to compare: md5 or sha256, default: sha256",
+						DefaultValue: "sha256",
 					},
 				),
 			},
 		},
 		&cli.Handler{
-			Pattern:     "[global] share [options] <fileId>",
-			Description: "Share file or directory",
-			Callback:    shareHandler,
+			Pattern:     "[global] verify [options] <path> <fileId>",
+			Description: "Compare local directory with a drive directory by checksum without transferring any files",
+			Callback:    verifyHandler,
 			FlagGroups: cli.FlagGroups{
 				cli.NewFlagGroup("global", globalFlags...),
 				cli.NewFlagGroup("options",
 					cli.StringFlag{
-						Name:         "role",
-						Patterns:     []string{"--role"},
-						Description:  fmt.Sprintf("Share role: owner/writer/commenter/reader, default: %s", DefaultShareRole),
-						DefaultValue: DefaultShareRole,
-					},
-					cli.StringFlag{
-						Name:         "type",
-						Patterns:     []string{"--type"},
-						Description:  fmt.Sprintf("Share type: user/group/domain/anyone, default: %s", DefaultShareType),
-						DefaultValue: DefaultShareType,
-					},
-					cli.StringFlag{
-						Name:        "email",
-						Patterns:    []string{"--email"},
-						Description: "The email address of the user or group to share the file with. Requires 'user' or 'group' as type",
+						Name:        "manifest",
+						Patterns:    []string{"--manifest"},
+						Description: "Write the path, size, md5 and sha256 of each matching file to a csv file. See verify-manifest",
 					},
-					cli.StringFlag{
-						Name:        "domain",
-						Patterns:    []string{"--domain"},
-						Description: "The name of Google Apps domain. Requires 'domain' as type",
+				),
+			},
+		},
+		&cli.Handler{
+			Pattern:     "[global] tree [options] <fileId>",
+			Description: "Print directory hierarchy as json",
+			Callback:    treeHandler,
+			FlagGroups: cli.FlagGroups{
+				cli.NewFlagGroup("global", globalFlags...),
+				cli.NewFlagGroup("options",
+					cli.IntFlag{
+						Name:         "maxDepth",
+						Patterns:     []string{"--max-depth"},
+						Description:  "Max depth of directories to include, use 0 for no limit, default: 0",
+						DefaultValue: 0,
 					},
 					cli.BoolFlag{
-						Name:        "discoverable",
-						Patterns:    []string{"--discoverable"},
-						Description: "Make file discoverable by search engines",
+						Name:        "sizes",
+						Patterns:    []string{"--sizes"},
+						Description: "Add the total size of each directory, including the files below the max depth",
 						OmitValue:   true,
 					},
 					cli.BoolFlag{
-						Name:        "revoke",
-						Patterns:    []string{"--revoke"},
-						Description: "Delete all sharing permissions (owner roles will be skipped)",
+						Name:        "flattenSingleChild",
+						Patterns:    []string{"--flatten-single-child"},
+						Description: "Collapse directories containing a single directory and no files, joining their names with /",
 						OmitValue:   true,
 					},
 				),
 			},
 		},
 		&cli.Handler{
-			Pattern:     "[global] share list <fileId>",
-			Description: "List files permissions",
-			Callback:    shareListHandler,
+			Pattern:     "[global] find [options] <fileId> <pattern>",
+			Description: "Recursively search directory for files with names containing the pattern, globs (*, ?, [...]) match the whole name",
+			Callback:    findHandler,
 			FlagGroups: cli.FlagGroups{
 				cli.NewFlagGroup("global", globalFlags...),
+				cli.NewFlagGroup("options",
+					cli.StringFlag{
+						Name:        "type",
+						Patterns:    []string{"--type"},
+						Description: "Only find files of the given type: dir or file",
+					},
+					cli.IntFlag{
+						Name:         "maxDepth",
+						Patterns:     []string{"--max-depth"},
+						Description:  "Max depth of directories to search, use 0 for no limit, default: 0",
+						DefaultValue: 0,
+					},
+				),
 			},
 		},
 		&cli.Handler{
-			Pattern:     "[global] share revoke <fileId> <permissionId>",
-			Description: "Revoke permission",
-			Callback:    shareRevokeHandler,
+			Pattern:     "[global] exists <path>",
+			Description: fmt.Sprintf("Print the id of the file at the absolute path or with the id, exits with code 1 if it does not exist and %d on errors", ExistsErrorExitCode),
+			Callback:    existsHandler,
 			FlagGroups: cli.FlagGroups{
 				cli.NewFlagGroup("global", globalFlags...),
 			},
 		},
 		&cli.Handler{
-			Pattern:     "[global] delete [options] <fileId>",
-			Description: "Delete file or directory",
-			Callback:    deleteHandler,
+			Pattern:     "[global] touch [options] <fileId>",
+			Description: "Set modified time of file, use - as file id to read ids from stdin, one per line",
+			Callback:    touchHandler,
+			FlagGroups: cli.FlagGroups{
+				cli.NewFlagGroup("global", globalFlags...),
+				cli.NewFlagGroup("options",
+					cli.StringFlag{
+						Name:         "time",
+						Patterns:     []string{"--time"},
+						Description:  "Modified time in RFC 3339 format (2006-01-02T15:04:05Z) or now. Default: now",
+						DefaultValue: "now",
+					},
+				),
+			},
+		},
+		&cli.Handler{
+			Pattern:     "[global] du [options] <fileId>",
+			Description: "Print total size of all files in directory",
+			Callback:    diskUsageHandler,
 			FlagGroups: cli.FlagGroups{
 				cli.NewFlagGroup("global", globalFlags...),
 				cli.NewFlagGroup("options",
+					cli.IntFlag{
+						Name:         "maxDepth",
+						Patterns:     []string{"-d", "--max-depth"},
+						Description:  "Also print the size of files and directories down to the given depth, largest first. Default: 0",
+						DefaultValue: 0,
+					},
 					cli.BoolFlag{
-						Name:        "recursive",
-						Patterns:    []string{"-r", "--recursive"},
-						Description: "Delete directory and all it's content",
+						Name:        "sizeInBytes",
+						Patterns:    []string{"--bytes"},
+						Description: "Size in bytes",
 						OmitValue:   true,
 					},
 				),
@@ -532,7 +1454,7 @@ func main() {
 						Patterns:    []string{"--csv-output"},
 						Description: "Use CSV output.",
 						OmitValue:   true,
-					},					
+					},
 					cli.BoolFlag{
 						Name:        "sizeInBytes",
 						Patterns:    []string{"--bytes"},
@@ -573,24 +1495,36 @@ func main() {
 						Description: "Delete extraneous local files",
 						OmitValue:   true,
 					},
-					cli.BoolFlag{
-						Name:        "dryRun",
-						Patterns:    []string{"--dry-run"},
-						Description: "Show what would have been transferred",
-						OmitValue:   true,
-					},
 					cli.BoolFlag{
 						Name:        "noProgress",
 						Patterns:    []string{"--no-progress"},
 						Description: "Hide progress",
 						OmitValue:   true,
 					},
+					cli.BoolFlag{
+						Name:        "timings",
+						Patterns:    []string{"--timings"},
+						Description: "Print how long each file transfer took and a summary of the slowest files",
+						OmitValue:   true,
+					},
+					cli.BoolFlag{
+						Name:        "mkdirs",
+						Patterns:    []string{"--mkdirs"},
+						Description: "Create the local directory and its parents if it does not exist",
+						OmitValue:   true,
+					},
 					cli.IntFlag{
 						Name:         "timeout",
 						Patterns:     []string{"--timeout"},
 						Description:  fmt.Sprintf("Set timeout in seconds, use 0 for no timeout. Timeout is reached when no data is transferred in set amount of seconds, default: %d", DefaultTimeout),
 						DefaultValue: DefaultTimeout,
 					},
+					cli.BoolFlag{
+						Name:        "yes",
+						Patterns:    []string{"-y", "--yes"},
+						Description: "Delete extraneous files without asking for confirmation, required when not running interactively",
+						OmitValue:   true,
+					},
 				),
 			},
 		},
@@ -625,18 +1559,18 @@ func main() {
 						Description: "Delete extraneous remote files",
 						OmitValue:   true,
 					},
-					cli.BoolFlag{
-						Name:        "dryRun",
-						Patterns:    []string{"--dry-run"},
-						Description: "Show what would have been transferred",
-						OmitValue:   true,
-					},
 					cli.BoolFlag{
 						Name:        "noProgress",
 						Patterns:    []string{"--no-progress"},
 						Description: "Hide progress",
 						OmitValue:   true,
 					},
+					cli.BoolFlag{
+						Name:        "timings",
+						Patterns:    []string{"--timings"},
+						Description: "Print how long each file transfer took and a summary of the slowest files",
+						OmitValue:   true,
+					},
 					cli.IntFlag{
 						Name:         "timeout",
 						Patterns:     []string{"--timeout"},
@@ -649,6 +1583,62 @@ func main() {
 						Description:  fmt.Sprintf("Set chunk size in bytes, default: %d", DefaultUploadChunkSize),
 						DefaultValue: DefaultUploadChunkSize,
 					},
+					cli.BoolFlag{
+						Name:        "yes",
+						Patterns:    []string{"-y", "--yes"},
+						Description: "Delete extraneous files without asking for confirmation, required when not running interactively",
+						OmitValue:   true,
+					},
+				),
+			},
+		},
+		&cli.Handler{
+			Pattern:     "[global] changes watch [options]",
+			Description: "Poll for file changes and print each change as it is detected",
+			Callback:    watchChangesHandler,
+			FlagGroups: cli.FlagGroups{
+				cli.NewFlagGroup("global", globalFlags...),
+				cli.NewFlagGroup("options",
+					cli.StringFlag{
+						Name:         "interval",
+						Patterns:     []string{"--interval"},
+						Description:  fmt.Sprintf("Time between polls, i.e. 30s or 5m, default: %s", DefaultChangesInterval),
+						DefaultValue: DefaultChangesInterval,
+					},
+					cli.BoolFlag{
+						Name:        "once",
+						Patterns:    []string{"--once"},
+						Description: "Poll a single time and exit",
+						OmitValue:   true,
+					},
+					cli.StringFlag{
+						Name:        "pageToken",
+						Patterns:    []string{"--page-token"},
+						Description: "Page token to start watching from, defaults to the token in --token-file or the latest page token",
+					},
+					cli.BoolFlag{
+						Name:        "includeShared",
+						Patterns:    []string{"--include-shared"},
+						Description: "Include changes to shared files that are not in My Drive",
+						OmitValue:   true,
+					},
+					cli.StringFlag{
+						Name:        "tokenFile",
+						Patterns:    []string{"--token-file"},
+						Description: "Read the page token to start from and write the new page token after each poll to this file",
+					},
+					cli.IntFlag{
+						Name:         "maxChanges",
+						Patterns:     []string{"-m", "--max"},
+						Description:  fmt.Sprintf("Max changes to fetch per request, default: %d", DefaultMaxChanges),
+						DefaultValue: DefaultMaxChanges,
+					},
+					cli.IntFlag{
+						Name:         "nameWidth",
+						Patterns:     []string{"--name-width"},
+						Description:  fmt.Sprintf("Width of name column, default: %d, minimum: 9, use 0 for full width", DefaultNameWidth),
+						DefaultValue: DefaultNameWidth,
+					},
 				),
 			},
 		},
@@ -677,6 +1667,17 @@ func main() {
 						Description: fmt.Sprintf("Get latest page token"),
 						OmitValue:   true,
 					},
+					cli.BoolFlag{
+						Name:        "all",
+						Patterns:    []string{"--all"},
+						Description: "List all changes since the page token, the new page token is printed to stderr or written to --token-file",
+						OmitValue:   true,
+					},
+					cli.StringFlag{
+						Name:        "tokenFile",
+						Patterns:    []string{"--token-file"},
+						Description: "Write the new page token to this file when using --all",
+					},
 					cli.IntFlag{
 						Name:         "nameWidth",
 						Patterns:     []string{"--name-width"},
@@ -694,7 +1695,7 @@ func main() {
 						Patterns:    []string{"--csv-output"},
 						Description: "Use CSV output.",
 						OmitValue:   true,
-					},					
+					},
 				),
 			},
 		},
@@ -722,13 +1723,18 @@ func main() {
 						Patterns:    []string{"--csv-output"},
 						Description: "Use CSV output.",
 						OmitValue:   true,
-					},					
+					},
 					cli.BoolFlag{
 						Name:        "sizeInBytes",
 						Patterns:    []string{"--bytes"},
 						Description: "Size in bytes",
 						OmitValue:   true,
 					},
+					cli.StringFlag{
+						Name:        "sortBy",
+						Patterns:    []string{"--sort"},
+						Description: "Sort revisions by modified time or size, ascending: modified or size. Default: api order",
+					},
 				),
 			},
 		},
@@ -771,6 +1777,39 @@ func main() {
 				),
 			},
 		},
+		&cli.Handler{
+			Pattern:     "[global] revision download-all [options] <fileId>",
+			Description: "Download all revisions of a file into a directory named after the file",
+			Callback:    downloadAllRevisionsHandler,
+			FlagGroups: cli.FlagGroups{
+				cli.NewFlagGroup("global", globalFlags...),
+				cli.NewFlagGroup("options",
+					cli.BoolFlag{
+						Name:        "force",
+						Patterns:    []string{"-f", "--force"},
+						Description: "Overwrite existing files",
+						OmitValue:   true,
+					},
+					cli.BoolFlag{
+						Name:        "noProgress",
+						Patterns:    []string{"--no-progress"},
+						Description: "Hide progress",
+						OmitValue:   true,
+					},
+					cli.StringFlag{
+						Name:        "path",
+						Patterns:    []string{"--path"},
+						Description: "Download path",
+					},
+					cli.IntFlag{
+						Name:         "timeout",
+						Patterns:     []string{"--timeout"},
+						Description:  fmt.Sprintf("Set timeout in seconds, use 0 for no timeout. Timeout is reached when no data is transferred in set amount of seconds, default: %d", DefaultTimeout),
+						DefaultValue: DefaultTimeout,
+					},
+				),
+			},
+		},
 		&cli.Handler{
 			Pattern:     "[global] revision delete <fileId> <revId>",
 			Description: "Delete file revision",
@@ -805,6 +1844,21 @@ func main() {
 				),
 			},
 		},
+		&cli.Handler{
+			Pattern:     "[global] export [options] - <fileId>",
+			Description: "Export a google document to stdout, messages are written to stderr",
+			Callback:    exportStdoutHandler,
+			FlagGroups: cli.FlagGroups{
+				cli.NewFlagGroup("global", globalFlags...),
+				cli.NewFlagGroup("options",
+					cli.StringFlag{
+						Name:        "mime",
+						Patterns:    []string{"--mime"},
+						Description: "Mime type of exported file",
+					},
+				),
+			},
+		},
 		&cli.Handler{
 			Pattern:     "[global] export [options] <fileId>",
 			Description: "Export a google document",
@@ -829,6 +1883,22 @@ func main() {
 						Description: "Print available mime types for given file",
 						OmitValue:   true,
 					},
+					cli.BoolFlag{
+						Name:        "perSheet",
+						Patterns:    []string{"--per-sheet"},
+						Description: "Export each sheet of a spreadsheet to <name>-<sheet>.csv, only supported for spreadsheets. Uses the Google Sheets API, which must be enabled in the project of the oauth client",
+						OmitValue:   true,
+					},
+					cli.StringFlag{
+						Name:        "output",
+						Patterns:    []string{"-o", "--output"},
+						Description: "Write the exported file to this path instead of the file name with the extension of the export format",
+					},
+					cli.StringFlag{
+						Name:        "exportFormat",
+						Patterns:    []string{"--export-format"},
+						Description: "Export format given as an extension, i.e. pdf or docx, or auto to use the extension of --output. Defaults to auto when --output has a known extension",
+					},
 				),
 			},
 		},
@@ -845,6 +1915,12 @@ func main() {
 						Description: "Show size in bytes",
 						OmitValue:   true,
 					},
+					cli.BoolFlag{
+						Name:        "sharedDrives",
+						Patterns:    []string{"--drives"},
+						Description: "Also list the accessible shared drives",
+						OmitValue:   true,
+					},
 				),
 			},
 		},
@@ -864,6 +1940,14 @@ func main() {
 				cli.NewFlagGroup("global", globalFlags...),
 			},
 		},
+		&cli.Handler{
+			Pattern:     "[global] whoami",
+			Description: "Print the email and name of the authenticated user",
+			Callback:    whoamiHandler,
+			FlagGroups: cli.FlagGroups{
+				cli.NewFlagGroup("global", globalFlags...),
+			},
+		},
 		&cli.Handler{
 			Pattern:     "version",
 			Description: "Print application version",
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/mzamorski/gdrive/auth"
@@ -18,24 +19,109 @@ const ClientId = "<YOUR_CLIENT_ID>"
 const ClientSecret = "<YOUR_CLIENT_SECRET>"
 const TokenFilename = "token.json"
 const DefaultCacheFileName = "file_cache.json"
+const DefaultUploadSessionsFileName = "upload_sessions.json"
 
 func listHandler(ctx cli.Context) {
 	args := ctx.Args()
-	err := newDrive(args).List(drive.ListFilesArgs{
-		Out:         os.Stdout,
-		MaxFiles:    args.Int64("maxFiles"),
-		NameWidth:   args.Int64("nameWidth"),
-		Query:       args.String("query"),
-		SortOrder:   args.String("sortOrder"),
-		SkipHeader:  args.Bool("skipHeader"),
-		SizeInBytes: args.Bool("sizeInBytes"),
-		AbsPath:     args.Bool("absPath"),
-		UseCsv:      args.Bool("useCsv"),
-		UseExtended: args.Bool("useExtended"),
+	layout, err := drive.NewTableLayout(args.Int64("minWidth"), args.Int64("padding"), args.String("padChar"))
+	checkErr(err)
+
+	err = newDrive(args).List(drive.ListFilesArgs{
+		Out:            os.Stdout,
+		MaxFiles:       args.Int64("maxFiles"),
+		NameWidth:      args.Int64("nameWidth"),
+		Query:          listQuery(args),
+		SortOrder:      args.String("sortOrder"),
+		SkipHeader:     args.Bool("skipHeader"),
+		SizeInBytes:    args.Bool("sizeInBytes"),
+		DecimalUnits:   args.Bool("si"),
+		AbsPath:        args.Bool("absPath"),
+		UseCsv:         args.Bool("useCsv"),
+		UseExtended:    args.Bool("useExtended"),
+		Columns:        splitList(args.String("columns")),
+		CountOnly:      args.Bool("countOnly"),
+		Mime:           args.String("mime"),
+		Parent:         args.String("parent"),
+		ModifiedAfter:  args.String("modifiedAfter"),
+		ModifiedBefore: args.String("modifiedBefore"),
+		FormatTemplate: args.String("formatTemplate"),
+		SharedWithMe:   args.Bool("sharedWithMe"),
+		DetailedType:   args.Bool("detailedType"),
+		Label:          args.String("label"),
+		ModifiedByMe:   args.Bool("modifiedByMe"),
+		ViewedAfter:    args.String("viewedAfter"),
+		FoldersFirst:   args.Bool("foldersFirst"),
+		SortBy:         args.String("sortBy"),
+		Natural:        args.Bool("natural"),
+		Format:         args.String("format"),
+		TimeFormat:     args.String("timeFormat"),
+		FailIfEmpty:    args.Bool("failIfEmpty"),
+		HideFolders:    args.Bool("hideFolders"),
+		Space:          args.String("space"),
+		Owner:          args.String("owner"),
+		Recent:         args.Int64("recent"),
+		Progress:       progressWriter(args.Bool("noProgress") || !isTerminal(os.Stderr)),
+		MarkDuplicates: args.Bool("markDuplicates"),
+		PageToken:      args.String("pageToken"),
+		NextPageToken:  nextPageTokenWriter(args.Bool("printNextToken")),
+		Layout:         layout,
+		OutputPath:     args.String("output"),
 	})
 	checkErr(err)
 }
 
+// The next page token is printed to stderr to keep it apart from the listed files
+func nextPageTokenWriter(print bool) io.Writer {
+	if print {
+		return os.Stderr
+	}
+	return nil
+}
+
+// Files shared with me or owned by someone else are not owned by me,
+// so the default query is replaced with a query without the owner condition
+func listQuery(args cli.Arguments) string {
+	query := args.String("query")
+
+	if path := args.String("queryFile"); path != "" {
+		if query != DefaultQuery {
+			ExitF("--query and --query-file can not be used together")
+		}
+		return readQuery(path)
+	}
+
+	if query == "-" {
+		return readQuery(query)
+	}
+
+	if (args.Bool("sharedWithMe") || args.String("owner") != "") && query == DefaultQuery {
+		return "trashed = false"
+	}
+	return query
+}
+
+// Reads the raw query from the file or stdin if the path is -,
+// trailing newlines are removed
+func readQuery(path string) string {
+	var content []byte
+	var err error
+
+	if path == "-" {
+		content, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		content, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		ExitF("Failed to read query: %s", err)
+	}
+
+	query := strings.TrimRight(string(content), "\r\n")
+	if strings.TrimSpace(query) == "" {
+		ExitF("Query in '%s' is empty", path)
+	}
+	return query
+}
+
 func listChangesHandler(ctx cli.Context) {
 	args := ctx.Args()
 	err := newDrive(args).ListChanges(drive.ListChangesArgs{
@@ -46,6 +132,30 @@ func listChangesHandler(ctx cli.Context) {
 		NameWidth:  args.Int64("nameWidth"),
 		SkipHeader: args.Bool("skipHeader"),
 		UseCsv:     args.Bool("useCsv"),
+		All:        args.Bool("all"),
+		TokenOut:   os.Stderr,
+		TokenFile:  args.String("tokenFile"),
+	})
+	checkErr(err)
+}
+
+func watchChangesHandler(ctx cli.Context) {
+	args := ctx.Args()
+	interval, err := time.ParseDuration(args.String("interval"))
+	if err != nil || interval <= 0 {
+		ExitF("Invalid interval '%s', use i.e. 30s or 5m", args.String("interval"))
+	}
+
+	err = newDrive(args).WatchChanges(drive.WatchChangesArgs{
+		Out:               os.Stdout,
+		Err:               os.Stderr,
+		PageToken:         args.String("pageToken"),
+		TokenFile:         args.String("tokenFile"),
+		Interval:          interval,
+		Once:              args.Bool("once"),
+		MaxChanges:        args.Int64("maxChanges"),
+		NameWidth:         args.Int64("nameWidth"),
+		RestrictToMyDrive: !args.Bool("includeShared"),
 	})
 	checkErr(err)
 }
@@ -54,16 +164,46 @@ func downloadHandler(ctx cli.Context) {
 	args := ctx.Args()
 	checkDownloadArgs(args)
 	err := newDrive(args).Download(drive.DownloadArgs{
-		Out:       os.Stdout,
-		Id:        args.String("fileId"),
-		Force:     args.Bool("force"),
-		Skip:      args.Bool("skip"),
-		Path:      args.String("path"),
-		Delete:    args.Bool("delete"),
-		Recursive: args.Bool("recursive"),
-		Stdout:    args.Bool("stdout"),
-		Progress:  progressWriter(args.Bool("noProgress")),
-		Timeout:   durationInSeconds(args.Int64("timeout")),
+		Out:             os.Stdout,
+		Id:              args.String("fileId"),
+		Force:           args.Bool("force"),
+		Skip:            args.Bool("skip"),
+		Path:            args.String("path"),
+		Delete:          args.Bool("delete"),
+		Recursive:       args.Bool("recursive"),
+		Stdout:          args.Bool("stdout"),
+		Resume:          args.Bool("resume"),
+		PreserveMtime:   args.Bool("preserveMtime"),
+		Progress:        progressWriter(args.Bool("noProgress")),
+		Timeout:         durationInSeconds(args.Int64("timeout")),
+		ContinueOnError: args.Bool("continueOnError"),
+		ManifestPath:    args.String("manifest"),
+		DecimalUnits:    args.Bool("si"),
+	})
+	checkErr(err)
+}
+
+func catHandler(ctx cli.Context) {
+	args := ctx.Args()
+	err := newDrive(args).Cat(drive.CatArgs{
+		Out:     os.Stdout,
+		Id:      args.String("fileId"),
+		Timeout: durationInSeconds(args.Int64("timeout")),
+	})
+	checkErr(err)
+}
+
+func cleanupDownloadsHandler(ctx cli.Context) {
+	args := ctx.Args()
+	err := newDrive(args).CleanupDownloads(drive.CleanupDownloadsArgs{
+		Out:           os.Stdout,
+		Progress:      progressWriter(args.Bool("noProgress")),
+		Path:          args.String("path"),
+		Resume:        args.Bool("resume"),
+		Delete:        args.Bool("delete"),
+		PreserveMtime: args.Bool("preserveMtime"),
+		Timeout:       durationInSeconds(args.Int64("timeout")),
+		DecimalUnits:  args.Bool("si"),
 	})
 	checkErr(err)
 }
@@ -71,13 +211,37 @@ func downloadHandler(ctx cli.Context) {
 func downloadQueryHandler(ctx cli.Context) {
 	args := ctx.Args()
 	err := newDrive(args).DownloadQuery(drive.DownloadQueryArgs{
-		Out:       os.Stdout,
-		Query:     args.String("query"),
-		Force:     args.Bool("force"),
-		Skip:      args.Bool("skip"),
-		Recursive: args.Bool("recursive"),
-		Path:      args.String("path"),
-		Progress:  progressWriter(args.Bool("noProgress")),
+		Out:          os.Stdout,
+		Query:        args.String("query"),
+		Force:        args.Bool("force"),
+		Skip:         args.Bool("skip"),
+		Recursive:    args.Bool("recursive"),
+		Path:         args.String("path"),
+		Progress:     progressWriter(args.Bool("noProgress")),
+		DecimalUnits: args.Bool("si"),
+	})
+	checkErr(err)
+}
+
+func downloadFolderHandler(ctx cli.Context) {
+	args := ctx.Args()
+	err := newDrive(args).DownloadFolder(drive.DownloadFolderArgs{
+		Out:                os.Stdout,
+		Id:                 args.String("fileId"),
+		Force:              args.Bool("force"),
+		Skip:               args.Bool("skip"),
+		Path:               args.String("path"),
+		Mime:               args.StringSlice("mime"),
+		ExcludeMime:        args.StringSlice("excludeMime"),
+		Progress:           progressWriter(args.Bool("noProgress")),
+		Timeout:            durationInSeconds(args.Int64("timeout")),
+		PreserveMtime:      args.Bool("preserveMtime"),
+		Flatten:            args.Bool("flatten"),
+		Since:              args.String("since"),
+		SinceFile:          args.String("sinceFile"),
+		FlattenSingleChild: args.Bool("flattenSingleChild"),
+		Concurrency:        int(args.Int64("concurrency")),
+		DecimalUnits:       args.Bool("si"),
 	})
 	checkErr(err)
 }
@@ -95,6 +259,10 @@ func downloadSyncHandler(ctx cli.Context) {
 		Timeout:          durationInSeconds(args.Int64("timeout")),
 		Resolution:       conflictResolution(args),
 		Comparer:         NewCachedMd5Comparer(cachePath),
+		Confirm:          confirmPrompt(args.Bool("yes")),
+		Timings:          args.Bool("timings"),
+		Mkdirs:           args.Bool("mkdirs"),
+		DecimalUnits:     args.Bool("si"),
 	})
 	checkErr(err)
 }
@@ -102,14 +270,29 @@ func downloadSyncHandler(ctx cli.Context) {
 func downloadRevisionHandler(ctx cli.Context) {
 	args := ctx.Args()
 	err := newDrive(args).DownloadRevision(drive.DownloadRevisionArgs{
-		Out:        os.Stdout,
-		FileId:     args.String("fileId"),
-		RevisionId: args.String("revId"),
-		Force:      args.Bool("force"),
-		Stdout:     args.Bool("stdout"),
-		Path:       args.String("path"),
-		Progress:   progressWriter(args.Bool("noProgress")),
-		Timeout:    durationInSeconds(args.Int64("timeout")),
+		Out:          os.Stdout,
+		FileId:       args.String("fileId"),
+		RevisionId:   args.String("revId"),
+		Force:        args.Bool("force"),
+		Stdout:       args.Bool("stdout"),
+		Path:         args.String("path"),
+		Progress:     progressWriter(args.Bool("noProgress")),
+		Timeout:      durationInSeconds(args.Int64("timeout")),
+		DecimalUnits: args.Bool("si"),
+	})
+	checkErr(err)
+}
+
+func downloadAllRevisionsHandler(ctx cli.Context) {
+	args := ctx.Args()
+	err := newDrive(args).DownloadAllRevisions(drive.DownloadAllRevisionsArgs{
+		Out:          os.Stdout,
+		FileId:       args.String("fileId"),
+		Force:        args.Bool("force"),
+		Path:         args.String("path"),
+		Progress:     progressWriter(args.Bool("noProgress")),
+		Timeout:      durationInSeconds(args.Int64("timeout")),
+		DecimalUnits: args.Bool("si"),
 	})
 	checkErr(err)
 }
@@ -118,18 +301,35 @@ func uploadHandler(ctx cli.Context) {
 	args := ctx.Args()
 	checkUploadArgs(args)
 	err := newDrive(args).Upload(drive.UploadArgs{
-		Out:         os.Stdout,
-		Progress:    progressWriter(args.Bool("noProgress")),
-		Path:        args.String("path"),
-		Name:        args.String("name"),
-		Description: args.String("description"),
-		Parents:     args.StringSlice("parent"),
-		Mime:        args.String("mime"),
-		Recursive:   args.Bool("recursive"),
-		Share:       args.Bool("share"),
-		Delete:      args.Bool("delete"),
-		ChunkSize:   args.Int64("chunksize"),
-		Timeout:     durationInSeconds(args.Int64("timeout")),
+		Out:             os.Stdout,
+		Progress:        progressWriter(args.Bool("noProgress")),
+		Path:            args.String("path"),
+		Name:            args.String("name"),
+		Description:     args.String("description"),
+		Parents:         args.StringSlice("parent"),
+		Mime:            args.String("mime"),
+		Recursive:       args.Bool("recursive"),
+		Share:           args.Bool("share"),
+		Delete:          args.Bool("delete"),
+		ChunkSize:       args.Int64("chunksize"),
+		Timeout:         durationInSeconds(args.Int64("timeout")),
+		ContinueOnError: args.Bool("continueOnError"),
+		CheckQuota:      args.Bool("checkQuota"),
+		NoConvert:       args.Bool("noConvert"),
+		Resume:          args.Bool("resume"),
+		SessionsPath:    filepath.Join(getConfigDir(args), DefaultUploadSessionsFileName),
+		DryRun:          args.Bool("dryRun"),
+		OnConflict:      conflictStrategy(args),
+		DecimalUnits:    args.Bool("si"),
+	})
+	checkErr(err)
+}
+
+func clearUploadSessionsHandler(ctx cli.Context) {
+	args := ctx.Args()
+	err := drive.ClearUploadSessions(drive.ClearUploadSessionsArgs{
+		Out:          os.Stdout,
+		SessionsPath: filepath.Join(getConfigDir(args), DefaultUploadSessionsFileName),
 	})
 	checkErr(err)
 }
@@ -137,8 +337,27 @@ func uploadHandler(ctx cli.Context) {
 func uploadStdinHandler(ctx cli.Context) {
 	args := ctx.Args()
 	err := newDrive(args).UploadStream(drive.UploadStreamArgs{
+		Out:          os.Stdout,
+		In:           os.Stdin,
+		Name:         args.String("name"),
+		Description:  args.String("description"),
+		Parents:      args.StringSlice("parent"),
+		Mime:         args.String("mime"),
+		Share:        args.Bool("share"),
+		ChunkSize:    args.Int64("chunksize"),
+		Timeout:      durationInSeconds(args.Int64("timeout")),
+		Progress:     progressWriter(args.Bool("noProgress")),
+		DecimalUnits: args.Bool("si"),
+	})
+	checkErr(err)
+}
+
+func uploadUrlHandler(ctx cli.Context) {
+	args := ctx.Args()
+	err := newDrive(args).UploadUrl(drive.UploadUrlArgs{
 		Out:         os.Stdout,
-		In:          os.Stdin,
+		Client:      newHttpClient(args),
+		Url:         args.String("url"),
 		Name:        args.String("name"),
 		Description: args.String("description"),
 		Parents:     args.StringSlice("parent"),
@@ -165,6 +384,9 @@ func uploadSyncHandler(ctx cli.Context) {
 		Timeout:          durationInSeconds(args.Int64("timeout")),
 		Resolution:       conflictResolution(args),
 		Comparer:         NewCachedMd5Comparer(cachePath),
+		Confirm:          confirmPrompt(args.Bool("yes")),
+		Timings:          args.Bool("timings"),
+		DecimalUnits:     args.Bool("si"),
 	})
 	checkErr(err)
 }
@@ -172,16 +394,20 @@ func uploadSyncHandler(ctx cli.Context) {
 func updateHandler(ctx cli.Context) {
 	args := ctx.Args()
 	err := newDrive(args).Update(drive.UpdateArgs{
-		Out:         os.Stdout,
-		Id:          args.String("fileId"),
-		Path:        args.String("path"),
-		Name:        args.String("name"),
-		Description: args.String("description"),
-		Parents:     args.StringSlice("parent"),
-		Mime:        args.String("mime"),
-		Progress:    progressWriter(args.Bool("noProgress")),
-		ChunkSize:   args.Int64("chunksize"),
-		Timeout:     durationInSeconds(args.Int64("timeout")),
+		Out:             os.Stdout,
+		Id:              args.String("fileId"),
+		Path:            args.String("path"),
+		Name:            args.String("name"),
+		Description:     args.String("description"),
+		Parents:         args.StringSlice("parent"),
+		Mime:            args.String("mime"),
+		Progress:        progressWriter(args.Bool("noProgress")),
+		ChunkSize:       args.Int64("chunksize"),
+		Timeout:         durationInSeconds(args.Int64("timeout")),
+		NewerThanRemote: args.Bool("newerThanRemote"),
+		Force:           args.Bool("force"),
+		DryRun:          args.Bool("dryRun"),
+		DecimalUnits:    args.Bool("si"),
 	})
 	checkErr(err)
 }
@@ -189,9 +415,32 @@ func updateHandler(ctx cli.Context) {
 func infoHandler(ctx cli.Context) {
 	args := ctx.Args()
 	err := newDrive(args).Info(drive.FileInfoArgs{
-		Out:         os.Stdout,
-		Id:          args.String("fileId"),
-		SizeInBytes: args.Bool("sizeInBytes"),
+		Out:          os.Stdout,
+		Id:           args.String("fileId"),
+		SizeInBytes:  args.Bool("sizeInBytes"),
+		DecimalUnits: args.Bool("si"),
+		Labels:       splitList(args.String("labels")),
+		DownloadUrl:  args.Bool("downloadUrl"),
+		Published:    args.Bool("published"),
+		Fields:       args.String("fields"),
+	})
+	checkErr(err)
+}
+
+func publishHandler(ctx cli.Context) {
+	args := ctx.Args()
+	err := newDrive(args).Publish(drive.PublishArgs{
+		Out: os.Stdout,
+		Id:  args.String("fileId"),
+	})
+	checkErr(err)
+}
+
+func unpublishHandler(ctx cli.Context) {
+	args := ctx.Args()
+	err := newDrive(args).Unpublish(drive.PublishArgs{
+		Out: os.Stdout,
+		Id:  args.String("fileId"),
 	})
 	checkErr(err)
 }
@@ -199,11 +448,12 @@ func infoHandler(ctx cli.Context) {
 func importHandler(ctx cli.Context) {
 	args := ctx.Args()
 	err := newDrive(args).Import(drive.ImportArgs{
-		Mime:     args.String("mime"),
-		Out:      os.Stdout,
-		Path:     args.String("path"),
-		Parents:  args.StringSlice("parent"),
-		Progress: progressWriter(args.Bool("noProgress")),
+		Mime:         args.String("mime"),
+		Out:          os.Stdout,
+		Path:         args.String("path"),
+		Parents:      args.StringSlice("parent"),
+		Progress:     progressWriter(args.Bool("noProgress")),
+		DecimalUnits: args.Bool("si"),
 	})
 	checkErr(err)
 }
@@ -211,11 +461,26 @@ func importHandler(ctx cli.Context) {
 func exportHandler(ctx cli.Context) {
 	args := ctx.Args()
 	err := newDrive(args).Export(drive.ExportArgs{
-		Out:        os.Stdout,
-		Id:         args.String("fileId"),
-		Mime:       args.String("mime"),
-		PrintMimes: args.Bool("printMimes"),
-		Force:      args.Bool("force"),
+		Out:          os.Stdout,
+		Id:           args.String("fileId"),
+		Mime:         args.String("mime"),
+		PrintMimes:   args.Bool("printMimes"),
+		Force:        args.Bool("force"),
+		PerSheet:     args.Bool("perSheet"),
+		OutputPath:   args.String("output"),
+		ExportFormat: args.String("exportFormat"),
+	})
+	checkErr(err)
+}
+
+func exportStdoutHandler(ctx cli.Context) {
+	args := ctx.Args()
+	err := newDrive(args).Export(drive.ExportArgs{
+		Out:    os.Stdout,
+		Err:    os.Stderr,
+		Id:     args.String("fileId"),
+		Mime:   args.String("mime"),
+		Stdout: true,
 	})
 	checkErr(err)
 }
@@ -223,12 +488,14 @@ func exportHandler(ctx cli.Context) {
 func listRevisionsHandler(ctx cli.Context) {
 	args := ctx.Args()
 	err := newDrive(args).ListRevisions(drive.ListRevisionsArgs{
-		Out:         os.Stdout,
-		Id:          args.String("fileId"),
-		NameWidth:   args.Int64("nameWidth"),
-		SizeInBytes: args.Bool("sizeInBytes"),
-		SkipHeader:  args.Bool("skipHeader"),
-		UseCsv:      args.Bool("useCsv"),
+		Out:          os.Stdout,
+		Id:           args.String("fileId"),
+		NameWidth:    args.Int64("nameWidth"),
+		SizeInBytes:  args.Bool("sizeInBytes"),
+		DecimalUnits: args.Bool("si"),
+		SkipHeader:   args.Bool("skipHeader"),
+		UseCsv:       args.Bool("useCsv"),
+		SortBy:       args.String("sortBy"),
 	})
 	checkErr(err)
 }
@@ -240,6 +507,45 @@ func mkdirHandler(ctx cli.Context) {
 		Name:        args.String("name"),
 		Description: args.String("description"),
 		Parents:     args.StringSlice("parent"),
+		ColorRgb:    args.String("colorRgb"),
+		NoDuplicate: args.Bool("noDuplicate"),
+		DryRun:      args.Bool("dryRun"),
+	})
+	checkErr(err)
+}
+
+func moveHandler(ctx cli.Context) {
+	args := ctx.Args()
+	err := newDrive(args).Move(drive.MoveArgs{
+		Out:    os.Stdout,
+		Id:     args.String("fileId"),
+		Parent: args.String("parentId"),
+		DryRun: args.Bool("dryRun"),
+	})
+	checkErr(err)
+}
+
+func copyHandler(ctx cli.Context) {
+	args := ctx.Args()
+	err := newDrive(args).Copy(drive.CopyArgs{
+		Out:                 os.Stdout,
+		Id:                  args.String("fileId"),
+		Name:                args.String("name"),
+		Parents:             args.StringSlice("parent"),
+		PreservePermissions: args.Bool("preservePermissions"),
+		Notify:              args.Bool("notify"),
+	})
+	checkErr(err)
+}
+
+func batchHandler(ctx cli.Context) {
+	args := ctx.Args()
+	err := newDrive(args).Batch(drive.BatchArgs{
+		Out:         os.Stdout,
+		Path:        args.String("path"),
+		Concurrency: int(args.Int64("concurrency")),
+		ChunkSize:   args.Int64("chunksize"),
+		Timeout:     durationInSeconds(args.Int64("timeout")),
 	})
 	checkErr(err)
 }
@@ -254,6 +560,22 @@ func shareHandler(ctx cli.Context) {
 		Email:        args.String("email"),
 		Domain:       args.String("domain"),
 		Discoverable: args.Bool("discoverable"),
+		LinkOnly:     args.Bool("linkOnly"),
+		DryRun:       args.Bool("dryRun"),
+	})
+	checkErr(err)
+}
+
+func shareBatchHandler(ctx cli.Context) {
+	args := ctx.Args()
+	err := newDrive(args).ShareBatch(drive.ShareBatchArgs{
+		Out:         os.Stdout,
+		FileId:      args.String("fileId"),
+		Role:        args.String("role"),
+		Type:        args.String("type"),
+		Emails:      args.StringSlice("email"),
+		Concurrency: int(args.Int64("concurrency")),
+		Verbose:     args.Bool("verbose"),
 	})
 	checkErr(err)
 }
@@ -263,6 +585,7 @@ func shareListHandler(ctx cli.Context) {
 	err := newDrive(args).ListPermissions(drive.ListPermissionsArgs{
 		Out:    os.Stdout,
 		FileId: args.String("fileId"),
+		SortBy: args.String("sortBy"),
 	})
 	checkErr(err)
 }
@@ -279,10 +602,165 @@ func shareRevokeHandler(ctx cli.Context) {
 
 func deleteHandler(ctx cli.Context) {
 	args := ctx.Args()
+	if args.Bool("onlyFiles") && args.Bool("onlyFolders") {
+		ExitF("--only-files and --only-folders can not be used together")
+	}
+
 	err := newDrive(args).Delete(drive.DeleteArgs{
-		Out:       os.Stdout,
-		Id:        args.String("fileId"),
-		Recursive: args.Bool("recursive"),
+		Out:             os.Stdout,
+		Id:              args.String("fileId"),
+		Recursive:       args.Bool("recursive"),
+		Confirm:         confirmPrompt(args.Bool("yes")),
+		ContinueOnError: args.Bool("continueOnError"),
+		OnlyFiles:       args.Bool("onlyFiles"),
+		OnlyFolders:     args.Bool("onlyFolders"),
+		DryRun:          args.Bool("dryRun"),
+		DecimalUnits:    args.Bool("si"),
+	})
+	checkErr(err)
+}
+
+func emptyTrashHandler(ctx cli.Context) {
+	args := ctx.Args()
+
+	var minAge time.Duration
+	if args.String("minAge") != "" {
+		var err error
+		minAge, err = parseAge(args.String("minAge"))
+		checkErr(err)
+	}
+
+	err := newDrive(args).EmptyTrash(drive.EmptyTrashArgs{
+		Out:          os.Stdout,
+		MinAge:       minAge,
+		Confirm:      confirmPrompt(args.Bool("yes")),
+		DecimalUnits: args.Bool("si"),
+	})
+	checkErr(err)
+}
+
+func cleanupHandler(ctx cli.Context) {
+	args := ctx.Args()
+	olderThan, err := parseAge(args.String("olderThan"))
+	checkErr(err)
+
+	err = newDrive(args).Cleanup(drive.CleanupArgs{
+		Out:            os.Stdout,
+		OlderThan:      olderThan,
+		Parent:         args.String("parent"),
+		IncludeFolders: args.Bool("includeFolders"),
+		Force:          args.Bool("force"),
+		SizeInBytes:    args.Bool("sizeInBytes"),
+		DecimalUnits:   args.Bool("si"),
+	})
+	checkErr(err)
+}
+
+func cleanupParentsHandler(ctx cli.Context) {
+	args := ctx.Args()
+	err := newDrive(args).CleanupParents(drive.CleanupParentsArgs{
+		Out:        os.Stdout,
+		Parent:     args.String("parent"),
+		KeepParent: args.String("keepParent"),
+		Fix:        args.Bool("fix"),
+	})
+	checkErr(err)
+}
+
+func dedupeHandler(ctx cli.Context) {
+	args := ctx.Args()
+	err := newDrive(args).Dedupe(drive.DedupeArgs{
+		Out:             os.Stdout,
+		Id:              args.String("fileId"),
+		TrashDuplicates: args.Bool("trashDuplicates"),
+		SizeInBytes:     args.Bool("sizeInBytes"),
+		DecimalUnits:    args.Bool("si"),
+	})
+	checkErr(err)
+}
+
+func verifyHandler(ctx cli.Context) {
+	args := ctx.Args()
+	cachePath := filepath.Join(args.String("configDir"), DefaultCacheFileName)
+	err := newDrive(args).Verify(drive.VerifyArgs{
+		Out:          os.Stdout,
+		Path:         args.String("path"),
+		RootId:       args.String("fileId"),
+		Comparer:     NewCachedMd5Comparer(cachePath),
+		ManifestPath: args.String("manifest"),
+	})
+	checkErr(err)
+}
+
+func verifyManifestHandler(ctx cli.Context) {
+	args := ctx.Args()
+	err := drive.VerifyManifest(drive.VerifyManifestArgs{
+		Out:      os.Stdout,
+		Path:     args.String("manifestPath"),
+		Checksum: args.String("checksum"),
+	})
+	checkErr(err)
+}
+
+func treeHandler(ctx cli.Context) {
+	args := ctx.Args()
+	err := newDrive(args).Tree(drive.TreeArgs{
+		Out:                os.Stdout,
+		Id:                 args.String("fileId"),
+		MaxDepth:           int(args.Int64("maxDepth")),
+		Sizes:              args.Bool("sizes"),
+		FlattenSingleChild: args.Bool("flattenSingleChild"),
+		DecimalUnits:       args.Bool("si"),
+	})
+	checkErr(err)
+}
+
+func findHandler(ctx cli.Context) {
+	args := ctx.Args()
+	err := newDrive(args).Find(drive.FindArgs{
+		Out:      os.Stdout,
+		Id:       args.String("fileId"),
+		Pattern:  args.String("pattern"),
+		Type:     args.String("type"),
+		MaxDepth: int(args.Int64("maxDepth")),
+	})
+	checkErr(err)
+}
+
+func existsHandler(ctx cli.Context) {
+	args := ctx.Args()
+
+	// Exit code 1 means the file does not exist, so errors need another code
+	errorExitCode = ExistsErrorExitCode
+
+	exists, id, err := newDrive(args).Exists(args.String("path"))
+	checkErr(err)
+
+	if !exists {
+		os.Exit(1)
+	}
+	fmt.Println(id)
+}
+
+func touchHandler(ctx cli.Context) {
+	args := ctx.Args()
+	err := newDrive(args).Touch(drive.TouchArgs{
+		Out:  os.Stdout,
+		Id:   args.String("fileId"),
+		In:   os.Stdin,
+		Time: args.String("time"),
+	})
+	checkErr(err)
+}
+
+func diskUsageHandler(ctx cli.Context) {
+	args := ctx.Args()
+	err := newDrive(args).DiskUsage(drive.DiskUsageArgs{
+		Out:          os.Stdout,
+		Id:           args.String("fileId"),
+		MaxDepth:     int(args.Int64("maxDepth")),
+		SizeInBytes:  args.Bool("sizeInBytes"),
+		DecimalUnits: args.Bool("si"),
 	})
 	checkErr(err)
 }
@@ -300,13 +778,14 @@ func listSyncHandler(ctx cli.Context) {
 func listRecursiveSyncHandler(ctx cli.Context) {
 	args := ctx.Args()
 	err := newDrive(args).ListRecursiveSync(drive.ListRecursiveSyncArgs{
-		Out:         os.Stdout,
-		RootId:      args.String("fileId"),
-		SkipHeader:  args.Bool("skipHeader"),
-		UseCsv:      args.Bool("useCsv"),
-		PathWidth:   args.Int64("pathWidth"),
-		SizeInBytes: args.Bool("sizeInBytes"),
-		SortOrder:   args.String("sortOrder"),
+		Out:          os.Stdout,
+		RootId:       args.String("fileId"),
+		SkipHeader:   args.Bool("skipHeader"),
+		UseCsv:       args.Bool("useCsv"),
+		PathWidth:    args.Int64("pathWidth"),
+		SizeInBytes:  args.Bool("sizeInBytes"),
+		DecimalUnits: args.Bool("si"),
+		SortOrder:    args.String("sortOrder"),
 	})
 	checkErr(err)
 }
@@ -324,8 +803,18 @@ func deleteRevisionHandler(ctx cli.Context) {
 func aboutHandler(ctx cli.Context) {
 	args := ctx.Args()
 	err := newDrive(args).About(drive.AboutArgs{
-		Out:         os.Stdout,
-		SizeInBytes: args.Bool("sizeInBytes"),
+		Out:          os.Stdout,
+		SizeInBytes:  args.Bool("sizeInBytes"),
+		DecimalUnits: args.Bool("si"),
+		SharedDrives: args.Bool("sharedDrives"),
+	})
+	checkErr(err)
+}
+
+func whoamiHandler(ctx cli.Context) {
+	args := ctx.Args()
+	err := newDrive(args).Whoami(drive.WhoamiArgs{
+		Out: os.Stdout,
 	})
 	checkErr(err)
 }
@@ -363,11 +852,15 @@ func getOauthClient(args cli.Arguments) (*http.Client, error) {
 
 	if args.String("serviceAccount") != "" {
 		serviceAccountPath := ConfigFilePath(configDir, args.String("serviceAccount"))
-		serviceAccountClient, err := auth.NewServiceAccountClient(serviceAccountPath)
+		serviceAccount, err := auth.NewServiceAccount(serviceAccountPath)
 		if err != nil {
 			return nil, err
 		}
-		return serviceAccountClient, nil
+		return serviceAccount.WithSubject(args.String("impersonate"))
+	}
+
+	if args.String("impersonate") != "" {
+		ExitF("--impersonate requires --service-account")
 	}
 
 	tokenPath := ConfigFilePath(configDir, TokenFilename)
@@ -388,6 +881,24 @@ func newDrive(args cli.Arguments) *drive.Drive {
 		ExitF("Failed getting oauth client: %s", err.Error())
 	}
 
+	// Abort requests without a response within the given http timeout
+	if timeout := durationInSeconds(args.Int64("httpTimeout")); timeout > 0 {
+		setBaseTransport(oauth, timeoutTransport(timeout))
+	}
+
+	if headers := requestHeaders(args); len(headers) > 0 {
+		oauth.Transport = headerTransport{clientTransport(oauth), headers}
+	}
+
+	if args.Bool("debug") {
+		oauth.Transport = debugTransport{clientTransport(oauth), os.Stderr}
+	}
+
+	// Commands without their own dry run fail instead of changing anything
+	if args.Bool("dryRun") {
+		oauth.Transport = dryRunTransport{clientTransport(oauth)}
+	}
+
 	client, err := drive.New(oauth)
 	if err != nil {
 		ExitF("Failed getting drive: %s", err.Error())
@@ -396,6 +907,36 @@ func newDrive(args cli.Arguments) *drive.Drive {
 	return client
 }
 
+// Client for requests to other hosts than drive, it uses the same http timeout
+// and debug logging as the drive client but never sends the drive credentials
+func newHttpClient(args cli.Arguments) *http.Client {
+	client := &http.Client{}
+
+	if timeout := durationInSeconds(args.Int64("httpTimeout")); timeout > 0 {
+		client.Transport = timeoutTransport(timeout)
+	}
+
+	if args.Bool("debug") {
+		client.Transport = debugTransport{clientTransport(client), os.Stderr}
+	}
+
+	return client
+}
+
+func requestHeaders(args cli.Arguments) http.Header {
+	headers := http.Header{}
+
+	if userAgent := args.String("userAgent"); userAgent != "" {
+		headers.Set("User-Agent", userAgent)
+	}
+
+	if project := args.String("quotaProject"); project != "" {
+		headers.Set("X-Goog-User-Project", project)
+	}
+
+	return headers
+}
+
 func authCodePrompt(url string) func() string {
 	return func() string {
 		fmt.Println("Authentication needed")
@@ -411,6 +952,30 @@ func authCodePrompt(url string) func() string {
 	}
 }
 
+// Asks for confirmation on stdin when stdout is a terminal,
+// when not running interactively --yes is required
+func confirmPrompt(yes bool) drive.ConfirmFunc {
+	return func(message string) error {
+		if yes {
+			return nil
+		}
+
+		if !isTerminal(os.Stdout) {
+			return fmt.Errorf("%s: refusing to continue without confirmation, use --yes to confirm", message)
+		}
+
+		fmt.Printf("%s, continue? [y/N]: ", message)
+
+		var answer string
+		fmt.Scanln(&answer)
+
+		if answer != "y" && answer != "yes" {
+			return fmt.Errorf("Aborted")
+		}
+		return nil
+	}
+}
+
 func progressWriter(discard bool) io.Writer {
 	if discard {
 		return ioutil.Discard
@@ -456,8 +1021,27 @@ func checkUploadArgs(args cli.Arguments) {
 	}
 }
 
+// The conflict strategy flags are mutually exclusive
+func conflictStrategy(args cli.Arguments) string {
+	var strategies []string
+	for _, strategy := range []string{"rename", "skip", "replace"} {
+		if args.Bool(strategy + "OnConflict") {
+			strategies = append(strategies, strategy)
+		}
+	}
+
+	if len(strategies) > 1 {
+		ExitF("Only one of --rename-on-conflict, --skip-on-conflict and --replace-on-conflict can be given")
+	}
+	return strings.Join(strategies, "")
+}
+
 func checkDownloadArgs(args cli.Arguments) {
 	if args.Bool("recursive") && args.Bool("delete") {
 		ExitF("--delete is not allowed for recursive downloads")
 	}
+
+	if args.Bool("stdout") && args.String("manifest") != "" {
+		ExitF("--manifest is not allowed when writing to stdout")
+	}
 }
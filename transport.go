@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"golang.org/x/oauth2"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Logs method, url, status and time until the response headers
+// was received for every request. Headers and bodies are never logged,
+// as they contain credentials and file content
+type debugTransport struct {
+	transport http.RoundTripper
+	out       io.Writer
+}
+
+func (self debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	started := time.Now()
+	res, err := self.transport.RoundTrip(req)
+	elapsed := time.Since(started)
+
+	if err != nil {
+		fmt.Fprintf(self.out, "[debug] %s %s failed after %s: %s\n", req.Method, req.URL, elapsed, err)
+		return res, err
+	}
+
+	fmt.Fprintf(self.out, "[debug] %s %s %s %s\n", req.Method, req.URL, res.Status, elapsed)
+	return res, nil
+}
+
+// Sets the given headers on every request, replacing any existing values
+type headerTransport struct {
+	transport http.RoundTripper
+	headers   http.Header
+}
+
+func (self headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// The request must not be modified, so the headers are set on a copy
+	r := new(http.Request)
+	*r = *req
+	r.Header = make(http.Header, len(req.Header)+len(self.headers))
+	for key, values := range req.Header {
+		r.Header[key] = values
+	}
+	for key, values := range self.headers {
+		r.Header[key] = values
+	}
+
+	return self.transport.RoundTrip(r)
+}
+
+// Only lets read requests through, any request that could change a file is refused
+type dryRunTransport struct {
+	transport http.RoundTripper
+}
+
+func (self dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != "GET" && req.Method != "HEAD" {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, fmt.Errorf("Refusing %s request in dry run, the command does not support --dry-run", req.Method)
+	}
+	return self.transport.RoundTrip(req)
+}
+
+// The timeout covers connecting and waiting for the response headers, but not
+// reading the body, so long uploads and downloads are not cut off
+func timeoutTransport(timeout time.Duration) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}).DialContext
+	transport.TLSHandshakeTimeout = timeout
+	transport.ResponseHeaderTimeout = timeout
+	return transport
+}
+
+// Replaces the transport the oauth client sends its requests with
+func setBaseTransport(client *http.Client, base http.RoundTripper) {
+	if transport, ok := client.Transport.(*oauth2.Transport); ok {
+		transport.Base = base
+	} else if client.Transport == nil {
+		client.Transport = base
+	}
+}
+
+func clientTransport(client *http.Client) http.RoundTripper {
+	if client.Transport == nil {
+		return http.DefaultTransport
+	}
+	return client.Transport
+}
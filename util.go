@@ -3,11 +3,16 @@ package main
 import (
 	"crypto/md5"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/mzamorski/gdrive/drive"
 	"io"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
+	"strings"
+	"time"
 )
 
 func GetDefaultConfigDir() string {
@@ -47,16 +52,68 @@ func equal(a, b []string) bool {
 	return true
 }
 
+// Split comma separated string into a list, an empty string gives an empty list
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// Parse age like 90d or 2w, other units are parsed by time.ParseDuration
+func parseAge(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, fmt.Errorf("Missing age, use i.e. 90d, 2w or 12h")
+	}
+
+	units := map[string]time.Duration{
+		"d": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour,
+	}
+
+	for suffix, unit := range units {
+		if !strings.HasSuffix(s, suffix) {
+			continue
+		}
+
+		n, err := strconv.Atoi(strings.TrimSuffix(s, suffix))
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("Invalid age '%s', use i.e. 90d, 2w or 12h", s)
+		}
+		return time.Duration(n) * unit, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("Invalid age '%s', use i.e. 90d, 2w or 12h", s)
+	}
+	return d, nil
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Exit code of failed commands, commands using exit code 1 for
+// an expected outcome change it to tell the two apart
+var errorExitCode = 1
+
 func ExitF(format string, a ...interface{}) {
 	fmt.Fprintf(os.Stderr, format, a...)
 	fmt.Println("")
-	os.Exit(1)
+	os.Exit(errorExitCode)
 }
 
 func checkErr(err error) {
+	if errors.Is(err, drive.ErrNoFiles) {
+		fmt.Println(err)
+		os.Exit(NoFilesExitCode)
+	}
+
 	if err != nil {
 		fmt.Println(err)
-		os.Exit(1)
+		os.Exit(errorExitCode)
 	}
 }
 